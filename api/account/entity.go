@@ -1,6 +1,8 @@
 // Package account implements account entities and services
 package account // import "github.com/coltoneshaw/ynab.go/api/account"
 
+import "github.com/coltoneshaw/ynab.go/api"
+
 // LoanAccountPeriodicValue represents periodic values for loan accounts
 // keyed by date strings in YYYY-MM-DD format (e.g., "2024-01-01").
 // Values are int64 amounts in milliunits format.
@@ -46,3 +48,47 @@ type SearchResultSnapshot struct {
 	Accounts        []*Account
 	ServerKnowledge uint64
 }
+
+// List returns the snapshot as a generic api.ListResult
+func (s *SearchResultSnapshot) List() *api.ListResult[*Account] {
+	return &api.ListResult[*Account]{Items: s.Accounts, ServerKnowledge: s.ServerKnowledge}
+}
+
+// Discrepancy returns the difference in milliunits between the account
+// balance and the sum of its cleared and uncleared balances. It should
+// normally be zero; any other value signals the balances are out of sync.
+func (a *Account) Discrepancy() int64 {
+	return a.Balance - (a.ClearedBalance + a.UnclearedBalance)
+}
+
+// IsClosed returns true if the account is closed
+func (a *Account) IsClosed() bool {
+	return a.Closed
+}
+
+// IsOnBudget returns true if the account is on budget
+func (a *Account) IsOnBudget() bool {
+	return a.OnBudget
+}
+
+// NetWorth sums the balances in milliunits of asset and liability
+// accounts, on budget and tracking alike, along with their net. Debts
+// have negative balances, so liabilities is normally negative and net is
+// assets plus liabilities. Deleted accounts, and closed ones unless
+// api.IncludeClosed is given, are skipped, as are accounts of unknown types.
+func NetWorth(accounts []*Account, opts ...api.IncludeOption) (assets int64, liabilities int64, net int64) {
+	o := api.NewIncludeOptions(opts...)
+
+	for _, a := range accounts {
+		if a == nil || a.Deleted || (a.Closed && !o.Closed) {
+			continue
+		}
+		switch {
+		case a.Type.IsAsset():
+			assets += a.Balance
+		case a.Type.IsLiability():
+			liabilities += a.Balance
+		}
+	}
+	return assets, liabilities, assets + liabilities
+}
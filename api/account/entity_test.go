@@ -0,0 +1,129 @@
+package account_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coltoneshaw/ynab.go/api"
+	"github.com/coltoneshaw/ynab.go/api/account"
+)
+
+func TestAccount_Discrepancy(t *testing.T) {
+	table := []struct {
+		Name    string
+		Account account.Account
+		Output  int64
+	}{
+		{
+			Name: "balanced",
+			Account: account.Account{
+				Balance:          -123930,
+				ClearedBalance:   -100000,
+				UnclearedBalance: -23930,
+			},
+			Output: 0,
+		},
+		{
+			Name: "positive discrepancy",
+			Account: account.Account{
+				Balance:          150000,
+				ClearedBalance:   100000,
+				UnclearedBalance: 25000,
+			},
+			Output: 25000,
+		},
+		{
+			Name: "negative discrepancy",
+			Account: account.Account{
+				Balance:          0,
+				ClearedBalance:   1000,
+				UnclearedBalance: 500,
+			},
+			Output: -1500,
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Output, test.Account.Discrepancy())
+		})
+	}
+}
+
+func TestAccount_IsClosed(t *testing.T) {
+	assert.True(t, (&account.Account{Closed: true}).IsClosed())
+	assert.False(t, (&account.Account{Closed: false}).IsClosed())
+}
+
+func TestAccount_IsOnBudget(t *testing.T) {
+	assert.True(t, (&account.Account{OnBudget: true}).IsOnBudget())
+	assert.False(t, (&account.Account{OnBudget: false}).IsOnBudget())
+}
+
+func TestType_IsAsset_IsLiability(t *testing.T) {
+	for _, typ := range []account.Type{account.TypeChecking, account.TypeSavings, account.TypeCash, account.TypeOtherAsset} {
+		assert.True(t, typ.IsAsset(), typ)
+		assert.False(t, typ.IsLiability(), typ)
+	}
+	for _, typ := range []account.Type{account.TypeCreditCard, account.TypeLineOfCredit, account.TypeMortgage, account.TypeOtherDebt} {
+		assert.True(t, typ.IsLiability(), typ)
+		assert.False(t, typ.IsAsset(), typ)
+	}
+
+	unknown := account.Type("spaceship")
+	assert.False(t, unknown.IsAsset())
+	assert.False(t, unknown.IsLiability())
+}
+
+func TestNetWorth(t *testing.T) {
+	accounts := []*account.Account{
+		{ID: "checking", Type: account.TypeChecking, OnBudget: true, Balance: 250000},
+		{ID: "savings", Type: account.TypeSavings, OnBudget: true, Balance: 1000000},
+		{ID: "credit-card", Type: account.TypeCreditCard, OnBudget: true, Balance: -120000},
+		{ID: "mortgage", Type: account.TypeMortgage, Balance: -500000},
+		{ID: "closed", Type: account.TypeChecking, Closed: true, Balance: 40000},
+		{ID: "deleted", Type: account.TypeSavings, Deleted: true, Balance: 99000},
+		nil,
+	}
+
+	assets, liabilities, net := account.NetWorth(accounts)
+	assert.Equal(t, int64(1250000), assets)
+	assert.Equal(t, int64(-620000), liabilities)
+	assert.Equal(t, int64(630000), net)
+
+	assets, liabilities, net = account.NetWorth(accounts, api.IncludeClosed())
+	assert.Equal(t, int64(1290000), assets)
+	assert.Equal(t, int64(-620000), liabilities)
+	assert.Equal(t, int64(670000), net)
+
+	assets, liabilities, net = account.NetWorth(nil)
+	assert.Zero(t, assets)
+	assert.Zero(t, liabilities)
+	assert.Zero(t, net)
+}
+
+func TestType_UnknownValues(t *testing.T) {
+	var a account.Account
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"acc","type":"brokerage","balance":1000}`), &a))
+
+	assert.Equal(t, account.Type("brokerage"), a.Type)
+	assert.False(t, a.Type.IsValid())
+	assert.Equal(t, account.TypeUnknown, a.Type.Known())
+
+	assert.True(t, account.TypeInvestment.IsValid())
+	assert.Equal(t, account.TypeCreditCard, account.TypeCreditCard.Known())
+	assert.False(t, account.TypeUnknown.IsValid())
+}
+
+func TestAccount_TransferPayeeID(t *testing.T) {
+	var a account.Account
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"acc-savings","transfer_payee_id":"payee-transfer-savings"}`), &a))
+	require.NotNil(t, a.TransferPayeeID)
+	assert.Equal(t, "payee-transfer-savings", *a.TransferPayeeID)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"acc-savings","transfer_payee_id":null}`), &a))
+	assert.Nil(t, a.TransferPayeeID)
+}
@@ -36,4 +36,45 @@ const (
 	TypeMerchant Type = "merchantAccount"
 	// TypeInvestment DEPRECATED identifies an investment account
 	TypeInvestment Type = "investmentAccount"
+	// TypeUnknown is the catch-all returned by Known for an account type
+	// this package does not recognize. It is never sent by the API.
+	TypeUnknown Type = "unknown"
 )
+
+// IsValid returns true if the type is one of the known constants, including
+// the deprecated ones. An account type added to the API after this package
+// was released is decoded as is and reports false. TypeUnknown is not valid.
+func (t Type) IsValid() bool {
+	return t.IsAsset() || t.IsLiability()
+}
+
+// Known returns the type if it is valid and TypeUnknown otherwise, so a
+// switch can handle account types added to the API in one case
+func (t Type) Known() Type {
+	if t.IsValid() {
+		return t
+	}
+	return TypeUnknown
+}
+
+// IsAsset returns true if accounts of this type hold assets, such as
+// checking, savings and cash accounts
+func (t Type) IsAsset() bool {
+	switch t {
+	case TypeChecking, TypeSavings, TypeCash, TypeOtherAsset,
+		TypePayPal, TypeMerchant, TypeInvestment:
+		return true
+	}
+	return false
+}
+
+// IsLiability returns true if accounts of this type hold debts, such as
+// credit cards, lines of credit and loans
+func (t Type) IsLiability() bool {
+	switch t {
+	case TypeCreditCard, TypeLineOfCredit, TypeOtherLiability, TypeMortgage,
+		TypeAutoLoan, TypeStudentLoan, TypePersonalLoan, TypeMedicalDebt, TypeOtherDebt:
+		return true
+	}
+	return false
+}
@@ -1,12 +1,19 @@
 package account
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/coltoneshaw/ynab.go/api"
 )
 
+// ErrClosedStateUnsupported is returned by CloseAccount and ReopenAccount:
+// the YNAB API has no endpoint updating an account, so accounts can only
+// be closed or reopened from the YNAB apps. It wraps errors.ErrUnsupported.
+var ErrClosedStateUnsupported = fmt.Errorf("account: closing and reopening accounts is unsupported by the YNAB API: %w",
+	errors.ErrUnsupported)
+
 // NewService facilitates the creation of a new account service instance
 func NewService(c api.ClientReaderWriter) *Service {
 	return &Service{c}
@@ -41,6 +48,76 @@ func (s *Service) GetAccounts(budgetID string, f *api.Filter) (*SearchResultSnap
 	}, nil
 }
 
+// GetAccountsFiltered fetches the list of accounts from a budget,
+// applying last_knowledge_of_server on the API side and excluding closed
+// accounts unless f.IncludeClosed is set. A nil filter excludes closed
+// accounts and performs a full fetch.
+// https://api.youneedabudget.com/v1#/Accounts/getAccounts
+func (s *Service) GetAccountsFiltered(budgetID string, f *Filter) (*SearchResultSnapshot, error) {
+	resModel := struct {
+		Data struct {
+			Accounts        []*Account `json:"accounts"`
+			ServerKnowledge uint64     `json:"server_knowledge"`
+		} `json:"data"`
+	}{}
+
+	url := fmt.Sprintf("/budgets/%s/accounts", budgetID)
+	if f != nil {
+		if query := f.ToQuery(); query != "" {
+			url = fmt.Sprintf("%s?%s", url, query)
+		}
+	}
+	if err := s.c.GET(url, &resModel); err != nil {
+		return nil, err
+	}
+
+	accounts := resModel.Data.Accounts
+	if f == nil || !f.IncludeClosed {
+		accounts = make([]*Account, 0, len(resModel.Data.Accounts))
+		for _, a := range resModel.Data.Accounts {
+			if !a.Closed {
+				accounts = append(accounts, a)
+			}
+		}
+	}
+
+	return &SearchResultSnapshot{
+		Accounts:        accounts,
+		ServerKnowledge: resModel.Data.ServerKnowledge,
+	}, nil
+}
+
+// GetOpenAccounts fetches the list of accounts from a budget that are not closed
+// https://api.youneedabudget.com/v1#/Accounts/getAccounts
+func (s *Service) GetOpenAccounts(budgetID string) ([]*Account, error) {
+	snapshot, err := (&Service{c: api.FullReads(s.c)}).GetAccountsFiltered(budgetID, nil)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.Accounts, nil
+}
+
+// GetAccountNameMap returns the names of the accounts of a budget keyed by
+// account ID. Closed and deleted accounts are left out unless
+// api.IncludeClosed or api.IncludeDeleted is given.
+// https://api.youneedabudget.com/v1#/Accounts/getAccounts
+func (s *Service) GetAccountNameMap(budgetID string, opts ...api.IncludeOption) (map[string]string, error) {
+	accounts, err := (&Service{c: api.FullReads(s.c)}).GetAccounts(budgetID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	o := api.NewIncludeOptions(opts...)
+	names := make(map[string]string, len(accounts.Accounts))
+	for _, a := range accounts.Accounts {
+		if a == nil || (a.Closed && !o.Closed) || (a.Deleted && !o.Deleted) {
+			continue
+		}
+		names[a.ID] = a.Name
+	}
+	return names, nil
+}
+
 // GetAccount fetches a specific account from a budget
 // https://api.youneedabudget.com/v1#/Accounts/getAccountById
 func (s *Service) GetAccount(budgetID, accountID string) (*Account, error) {
@@ -57,16 +134,17 @@ func (s *Service) GetAccount(budgetID, accountID string) (*Account, error) {
 	return resModel.Data.Account, nil
 }
 
-// CreateAccount creates a new account in a budget
+// CreateAccount creates a new account in a budget. An Idempotency-Key
+// header can be set with api.WithIdempotencyKey.
 // https://api.youneedabudget.com/v1#/Accounts/createAccount
-func (s *Service) CreateAccount(budgetID string, p PayloadAccount) (*Account, error) {
+func (s *Service) CreateAccount(budgetID string, p PayloadAccount, opts ...api.WriteOption) (*Account, error) {
 	payload := struct {
 		Account *PayloadAccount `json:"account"`
 	}{
 		&p,
 	}
 
-	buf, err := json.Marshal(&payload)
+	buf, err := api.Marshal(s.c, &payload)
 	if err != nil {
 		return nil, err
 	}
@@ -78,8 +156,42 @@ func (s *Service) CreateAccount(budgetID string, p PayloadAccount) (*Account, er
 	}{}
 
 	url := fmt.Sprintf("/budgets/%s/accounts", budgetID)
-	if err := s.c.POST(url, &resModel, buf); err != nil {
+	if err := api.POSTWithHeader(s.c, url, &resModel, buf, api.NewWriteOptions(opts...).Header()); err != nil {
 		return nil, err
 	}
 	return resModel.Data.Account, nil
 }
+
+// CloseAccount would close an account of a budget. The YNAB API does not
+// support updating accounts, so it always fails with
+// ErrClosedStateUnsupported without sending any request.
+func (s *Service) CloseAccount(budgetID, accountID string) (*Account, error) {
+	return nil, ErrClosedStateUnsupported
+}
+
+// ReopenAccount would reopen a closed account of a budget. The YNAB API
+// does not support updating accounts, so it always fails with
+// ErrClosedStateUnsupported without sending any request.
+func (s *Service) ReopenAccount(budgetID, accountID string) (*Account, error) {
+	return nil, ErrClosedStateUnsupported
+}
+
+// Filter represents the optional filter while fetching accounts
+type Filter struct {
+	// IncludeClosed includes closed accounts in the result. Closed
+	// accounts are filtered out client-side when false.
+	IncludeClosed bool
+	// LastKnowledgeOfServer The starting server knowledge. If provided,
+	// only accounts that have changed since last_knowledge_of_server
+	// will be included
+	LastKnowledgeOfServer *uint64
+}
+
+// ToQuery returns the filters as a HTTP query string
+func (f *Filter) ToQuery() string {
+	pairs := make([]string, 0, 1)
+	if f.LastKnowledgeOfServer != nil {
+		pairs = append(pairs, fmt.Sprintf("last_knowledge_of_server=%d", *f.LastKnowledgeOfServer))
+	}
+	return strings.Join(pairs, "&")
+}
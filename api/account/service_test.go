@@ -1,6 +1,7 @@
 package account_test
 
 import (
+	"errors"
 	"net/http"
 	"testing"
 
@@ -11,6 +12,7 @@ import (
 
 	"github.com/coltoneshaw/ynab.go"
 	"github.com/coltoneshaw/ynab.go/api/account"
+	"github.com/coltoneshaw/ynab.go/api/budget"
 )
 
 func TestService_GetAccounts(t *testing.T) {
@@ -122,6 +124,31 @@ func TestService_GetAccounts_NilFilter(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestService_GetAccounts_BudgetAlias(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	url := "https://api.youneedabudget.com/v1/budgets/last-used/accounts"
+	httpmock.RegisterResponder(http.MethodGet, url,
+		func(req *http.Request) (*http.Response, error) {
+			// Verify that the alias is passed through verbatim
+			assert.Equal(t, "/v1/budgets/last-used/accounts", req.URL.Path)
+
+			res := httpmock.NewStringResponse(200, `{
+  "data": {
+    "accounts": [],
+    "server_knowledge": 0
+  }
+}`)
+			return res, nil
+		},
+	)
+
+	client := ynab.NewClient("")
+	_, err := client.Account().GetAccounts(budget.LastUsed, nil)
+	assert.NoError(t, err)
+}
+
 func TestService_GetAccount(t *testing.T) {
 	httpmock.Activate()
 	defer httpmock.DeactivateAndReset()
@@ -228,3 +255,163 @@ func TestService_CreateAccount(t *testing.T) {
 	}
 	assert.Equal(t, expected, a)
 }
+
+func TestService_GetAccountsFiltered(t *testing.T) {
+	registerAccounts := func(t *testing.T, expectedQuery string) {
+		url := "https://api.youneedabudget.com/v1/budgets/bbdccdb0-9007-42aa-a6fe-02a3e94476be/accounts"
+		httpmock.RegisterResponder(http.MethodGet, url,
+			func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, expectedQuery, req.URL.RawQuery)
+
+				res := httpmock.NewStringResponse(200, `{
+  "data": {
+    "accounts": [
+      {
+        "id": "aa248caa-eed7-4575-a990-717386438d2c",
+        "name": "Open Account",
+        "type": "checking",
+        "on_budget": true,
+        "closed": false,
+        "balance": 1000,
+        "cleared_balance": 1000,
+        "uncleared_balance": 0,
+        "deleted": false
+      },
+      {
+        "id": "0ba2d7a2-8a8d-4b59-8c6c-9e8c7a3f1e10",
+        "name": "Closed Account",
+        "type": "savings",
+        "on_budget": false,
+        "closed": true,
+        "balance": 0,
+        "cleared_balance": 0,
+        "uncleared_balance": 0,
+        "deleted": false
+      }
+    ],
+    "server_knowledge": 15
+  }
+}`)
+				return res, nil
+			},
+		)
+	}
+
+	t.Run("excludes closed accounts by default", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		registerAccounts(t, "")
+
+		client := ynab.NewClient("")
+		snapshot, err := client.Account().GetAccountsFiltered("bbdccdb0-9007-42aa-a6fe-02a3e94476be", &account.Filter{})
+		assert.NoError(t, err)
+		assert.Len(t, snapshot.Accounts, 1)
+		assert.Equal(t, "Open Account", snapshot.Accounts[0].Name)
+		assert.Equal(t, uint64(15), snapshot.ServerKnowledge)
+	})
+
+	t.Run("includes closed accounts when requested", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		registerAccounts(t, "")
+
+		client := ynab.NewClient("")
+		snapshot, err := client.Account().GetAccountsFiltered("bbdccdb0-9007-42aa-a6fe-02a3e94476be", &account.Filter{IncludeClosed: true})
+		assert.NoError(t, err)
+		assert.Len(t, snapshot.Accounts, 2)
+	})
+
+	t.Run("sends server knowledge", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		registerAccounts(t, "last_knowledge_of_server=12")
+
+		knowledge := uint64(12)
+		client := ynab.NewClient("")
+		_, err := client.Account().GetAccountsFiltered("bbdccdb0-9007-42aa-a6fe-02a3e94476be", &account.Filter{LastKnowledgeOfServer: &knowledge})
+		assert.NoError(t, err)
+	})
+
+	t.Run("open accounts", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		registerAccounts(t, "")
+
+		client := ynab.NewClient("")
+		accounts, err := client.Account().GetOpenAccounts("bbdccdb0-9007-42aa-a6fe-02a3e94476be")
+		assert.NoError(t, err)
+		assert.Len(t, accounts, 1)
+		assert.False(t, accounts[0].Closed)
+	})
+}
+
+func TestFilter_ToQuery(t *testing.T) {
+	knowledge := uint64(42)
+	assert.Equal(t, "", (&account.Filter{}).ToQuery())
+	assert.Equal(t, "", (&account.Filter{IncludeClosed: true}).ToQuery())
+	assert.Equal(t, "last_knowledge_of_server=42", (&account.Filter{LastKnowledgeOfServer: &knowledge}).ToQuery())
+}
+
+func TestService_GetAccounts_StrictEnvelope(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	url := "https://api.youneedabudget.com/v1/budgets/aa248caa-eed7-4575-a990-717386438d2c/accounts"
+	httpmock.RegisterResponder(http.MethodGet, url,
+		httpmock.NewStringResponder(http.StatusOK, `{"data":{"accounts":[],"server_knowledge":10}}`))
+
+	client := ynab.NewClient("").WithStrictEnvelope()
+	snapshot, err := client.Account().GetAccounts("aa248caa-eed7-4575-a990-717386438d2c", nil)
+	assert.NoError(t, err)
+	assert.Empty(t, snapshot.Accounts)
+
+	httpmock.RegisterResponder(http.MethodGet, url,
+		httpmock.NewStringResponder(http.StatusOK, `{}`))
+
+	snapshot, err = client.Account().GetAccounts("aa248caa-eed7-4575-a990-717386438d2c", nil)
+	assert.ErrorIs(t, err, api.ErrMissingEnvelope)
+	assert.Nil(t, snapshot)
+}
+
+func TestService_CloseAndReopenAccount(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	client := ynab.NewClient("")
+
+	a, err := client.Account().CloseAccount("aa248caa", "acc-1")
+	assert.Nil(t, a)
+	assert.ErrorIs(t, err, account.ErrClosedStateUnsupported)
+	assert.ErrorIs(t, err, errors.ErrUnsupported)
+
+	a, err = client.Account().ReopenAccount("aa248caa", "acc-1")
+	assert.Nil(t, a)
+	assert.ErrorIs(t, err, account.ErrClosedStateUnsupported)
+
+	assert.Zero(t, httpmock.GetTotalCallCount())
+}
+
+func TestService_GetAccountNameMap(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	url := "https://api.youneedabudget.com/v1/budgets/aa248caa/accounts"
+	httpmock.RegisterResponder(http.MethodGet, url, httpmock.NewStringResponder(http.StatusOK, `{"data":{"accounts":[
+		{"id":"acc-checking","name":"Checking"},
+		{"id":"acc-closed","name":"Old Savings","closed":true},
+		{"id":"acc-deleted","name":"Mistake","deleted":true}
+	],"server_knowledge":1}}`))
+
+	client := ynab.NewClient("")
+	names, err := client.Account().GetAccountNameMap("aa248caa")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"acc-checking": "Checking"}, names)
+
+	names, err = client.Account().GetAccountNameMap("aa248caa", api.IncludeClosed())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"acc-checking": "Checking", "acc-closed": "Old Savings"}, names)
+
+	names, err = client.Account().GetAccountNameMap("aa248caa", api.IncludeDeleted())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"acc-checking": "Checking", "acc-deleted": "Mistake"}, names)
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRetryBaseDelay is the delay before the first retry used by
+	// DefaultBackoff
+	DefaultRetryBaseDelay = 500 * time.Millisecond
+	// DefaultRetryMaxDelay caps the delay between retries used by
+	// DefaultBackoff
+	DefaultRetryMaxDelay = 30 * time.Second
+)
+
+// BackoffStrategy computes how long to wait before retrying a failed
+// request. Attempt is 1 before the first retry and grows by one with each
+// further retry. Implementations must be safe for concurrent use.
+type BackoffStrategy interface {
+	NextDelay(attempt int) time.Duration
+}
+
+// DefaultBackoff returns the strategy used when retries are enabled
+// without one: full jitter between DefaultRetryBaseDelay and
+// DefaultRetryMaxDelay
+func DefaultBackoff() BackoffStrategy {
+	return NewFullJitter(DefaultRetryBaseDelay, DefaultRetryMaxDelay)
+}
+
+// NoJitter waits exactly base * 2^(attempt-1), capped at max. Clients
+// using it retry in lockstep, so prefer a jittered strategy when many of
+// them share the same rate limit.
+type NoJitter struct {
+	base time.Duration
+	max  time.Duration
+}
+
+// NewNoJitter creates a plain exponential backoff strategy
+func NewNoJitter(base, max time.Duration) *NoJitter {
+	return &NoJitter{base: base, max: max}
+}
+
+// NextDelay implements BackoffStrategy
+func (b *NoJitter) NextDelay(attempt int) time.Duration {
+	return exponentialDelay(b.base, b.max, attempt)
+}
+
+// FullJitter waits a random duration between zero and the exponential
+// delay, spreading retries the most
+type FullJitter struct {
+	base   time.Duration
+	max    time.Duration
+	jitter jitterSource
+}
+
+// NewFullJitter creates a full jitter backoff strategy
+func NewFullJitter(base, max time.Duration) *FullJitter {
+	return &FullJitter{base: base, max: max}
+}
+
+// WithRand sets the random source, e.g. a seeded one in tests
+func (b *FullJitter) WithRand(r *rand.Rand) *FullJitter {
+	b.jitter.rng = r
+	return b
+}
+
+// NextDelay implements BackoffStrategy
+func (b *FullJitter) NextDelay(attempt int) time.Duration {
+	return b.jitter.upTo(exponentialDelay(b.base, b.max, attempt))
+}
+
+// EqualJitter waits half the exponential delay plus a random duration up
+// to the other half, keeping a minimum wait while still spreading retries
+type EqualJitter struct {
+	base   time.Duration
+	max    time.Duration
+	jitter jitterSource
+}
+
+// NewEqualJitter creates an equal jitter backoff strategy
+func NewEqualJitter(base, max time.Duration) *EqualJitter {
+	return &EqualJitter{base: base, max: max}
+}
+
+// WithRand sets the random source, e.g. a seeded one in tests
+func (b *EqualJitter) WithRand(r *rand.Rand) *EqualJitter {
+	b.jitter.rng = r
+	return b
+}
+
+// NextDelay implements BackoffStrategy
+func (b *EqualJitter) NextDelay(attempt int) time.Duration {
+	delay := exponentialDelay(b.base, b.max, attempt)
+	half := delay / 2
+	return delay - half + b.jitter.upTo(half)
+}
+
+// exponentialDelay returns base * 2^(attempt-1), capped at max
+func exponentialDelay(base, max time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+	delay := base
+	for i := 1; i < attempt; i++ {
+		if delay >= max/2 {
+			return max
+		}
+		delay *= 2
+	}
+	if delay > max {
+		return max
+	}
+	return delay
+}
+
+// jitterSource draws random durations from an optional, mutex-guarded
+// random source, falling back to the global one
+type jitterSource struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// upTo returns a random duration in [0, d]
+func (j *jitterSource) upTo(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.rng == nil {
+		return time.Duration(rand.Int64N(int64(d) + 1))
+	}
+	return time.Duration(j.rng.Int64N(int64(d) + 1))
+}
@@ -0,0 +1,78 @@
+package api_test
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coltoneshaw/ynab.go/api"
+)
+
+func TestNoJitter_NextDelay(t *testing.T) {
+	b := api.NewNoJitter(100*time.Millisecond, time.Second)
+
+	expected := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}
+	for i, want := range expected {
+		assert.Equal(t, want, b.NextDelay(i+1), "attempt %d", i+1)
+	}
+
+	// Large attempts do not overflow
+	assert.Equal(t, time.Second, b.NextDelay(200))
+}
+
+func TestFullJitter_NextDelay(t *testing.T) {
+	b := api.NewFullJitter(100*time.Millisecond, time.Second).WithRand(rand.New(rand.NewPCG(1, 2)))
+	ceiling := api.NewNoJitter(100*time.Millisecond, time.Second)
+
+	distinct := map[time.Duration]bool{}
+	for attempt := 1; attempt <= 8; attempt++ {
+		for range 20 {
+			delay := b.NextDelay(attempt)
+			assert.GreaterOrEqual(t, delay, time.Duration(0))
+			assert.LessOrEqual(t, delay, ceiling.NextDelay(attempt), "attempt %d", attempt)
+			distinct[delay] = true
+		}
+	}
+	assert.Greater(t, len(distinct), 1, "delays should be jittered")
+}
+
+func TestEqualJitter_NextDelay(t *testing.T) {
+	b := api.NewEqualJitter(100*time.Millisecond, time.Second).WithRand(rand.New(rand.NewPCG(3, 4)))
+	ceiling := api.NewNoJitter(100*time.Millisecond, time.Second)
+
+	distinct := map[time.Duration]bool{}
+	for attempt := 1; attempt <= 8; attempt++ {
+		for range 20 {
+			delay := b.NextDelay(attempt)
+			assert.GreaterOrEqual(t, delay, ceiling.NextDelay(attempt)/2, "attempt %d", attempt)
+			assert.LessOrEqual(t, delay, ceiling.NextDelay(attempt), "attempt %d", attempt)
+			distinct[delay] = true
+		}
+	}
+	assert.Greater(t, len(distinct), 1, "delays should be jittered")
+}
+
+func TestJitter_SeededRandIsDeterministic(t *testing.T) {
+	first := api.NewFullJitter(time.Second, time.Minute).WithRand(rand.New(rand.NewPCG(7, 7)))
+	second := api.NewFullJitter(time.Second, time.Minute).WithRand(rand.New(rand.NewPCG(7, 7)))
+
+	for attempt := 1; attempt <= 5; attempt++ {
+		assert.Equal(t, first.NextDelay(attempt), second.NextDelay(attempt))
+	}
+}
+
+func TestDefaultBackoff(t *testing.T) {
+	b := api.DefaultBackoff()
+	for attempt := 1; attempt <= 10; attempt++ {
+		assert.LessOrEqual(t, b.NextDelay(attempt), api.DefaultRetryMaxDelay)
+	}
+}
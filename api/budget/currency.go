@@ -0,0 +1,154 @@
+package budget
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/coltoneshaw/ynab.go/internal/ascii"
+)
+
+// ErrInvalidAmount is returned by CurrencyFormat.Parse for input that is
+// not an amount in the budget's format
+var ErrInvalidAmount = errors.New("budget: invalid amount")
+
+// Format renders a milliunit amount the way the budget displays it, using
+// the configured decimal digits, separators and currency symbol. Amounts
+// are rounded half away from zero to the decimal digits, and negative
+// amounts are prefixed with a minus sign ahead of any leading symbol.
+func (f *CurrencyFormat) Format(amount int64) string {
+	negative := amount < 0
+	if negative {
+		amount = -amount
+	}
+
+	value := uint64(amount)
+	digits := f.DecimalDigits
+	if digits <= 3 {
+		div := pow10(3 - digits)
+		value = (value + div/2) / div
+	} else {
+		value *= pow10(digits - 3)
+	}
+
+	scale := pow10(digits)
+	number := groupThousands(strconv.FormatUint(value/scale, 10), f.GroupSeparator)
+	if digits > 0 {
+		frac := strconv.FormatUint(value%scale, 10)
+		number += f.DecimalSeparator + strings.Repeat("0", int(digits)-len(frac)) + frac
+	}
+
+	var b strings.Builder
+	if negative {
+		b.WriteString("-")
+	}
+	if f.DisplaySymbol && f.SymbolFirst {
+		b.WriteString(f.CurrencySymbol)
+	}
+	b.WriteString(number)
+	if f.DisplaySymbol && !f.SymbolFirst {
+		b.WriteString(f.CurrencySymbol)
+	}
+	return b.String()
+}
+
+// Parse converts a user-entered amount to milliunits, accepting the
+// budget's currency symbol wherever it is placed, its group and decimal
+// separators, and negatives written with a leading minus sign or wrapped in
+// parentheses as in accounting notation, e.g. "(1,234.56)" for USD or
+// "-1.234,56€" for a comma-decimal EUR budget. Group separators are
+// ignored wherever they appear, and input more precise than a milliunit is
+// rejected with ErrInvalidAmount.
+func (f *CurrencyFormat) Parse(s string) (int64, error) {
+	invalid := func(reason string) (int64, error) {
+		return 0, fmt.Errorf("%w %q: %s", ErrInvalidAmount, s, reason)
+	}
+
+	value := strings.TrimSpace(s)
+	negative := false
+	if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		negative = true
+		value = value[1 : len(value)-1]
+	}
+	if f.CurrencySymbol != "" {
+		value = strings.ReplaceAll(value, f.CurrencySymbol, "")
+	}
+	value = strings.TrimSpace(value)
+	switch {
+	case strings.HasPrefix(value, "-"):
+		if negative {
+			return invalid("more than one sign")
+		}
+		negative = true
+		value = strings.TrimSpace(value[1:])
+	case strings.HasPrefix(value, "+"):
+		value = strings.TrimSpace(value[1:])
+	}
+
+	decimal := f.DecimalSeparator
+	if decimal == "" {
+		decimal = "."
+	}
+	if f.GroupSeparator != "" && f.GroupSeparator != decimal {
+		value = strings.ReplaceAll(value, f.GroupSeparator, "")
+	}
+
+	whole, frac, _ := strings.Cut(value, decimal)
+	if whole == "" && frac == "" {
+		return invalid("no digits")
+	}
+	if len(frac) > 3 {
+		return invalid("more precise than a milliunit")
+	}
+	if !ascii.IsDigits(whole) || !ascii.IsDigits(frac) {
+		return invalid("unexpected characters")
+	}
+
+	amount := int64(0)
+	if whole != "" {
+		units, err := strconv.ParseInt(whole, 10, 64)
+		if err != nil || units > math.MaxInt64/1000 {
+			return invalid("out of range")
+		}
+		amount = units * 1000
+	}
+	if frac != "" {
+		milli, _ := strconv.ParseInt(frac+strings.Repeat("0", 3-len(frac)), 10, 64)
+		amount += milli
+	}
+
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+// groupThousands inserts sep between every group of three digits
+func groupThousands(digits, sep string) string {
+	if sep == "" || len(digits) <= 3 {
+		return digits
+	}
+
+	var b strings.Builder
+	head := len(digits) % 3
+	if head > 0 {
+		b.WriteString(digits[:head])
+	}
+	for i := head; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
+
+func pow10(n uint64) uint64 {
+	p := uint64(1)
+	for i := uint64(0); i < n; i++ {
+		p *= 10
+	}
+	return p
+}
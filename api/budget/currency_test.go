@@ -0,0 +1,127 @@
+package budget_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coltoneshaw/ynab.go/api/budget"
+)
+
+func TestCurrencyFormat_Format(t *testing.T) {
+	usd := &budget.CurrencyFormat{
+		ISOCode:          "USD",
+		DecimalDigits:    2,
+		DecimalSeparator: ".",
+		GroupSeparator:   ",",
+		SymbolFirst:      true,
+		CurrencySymbol:   "$",
+		DisplaySymbol:    true,
+	}
+	eur := &budget.CurrencyFormat{
+		ISOCode:          "EUR",
+		DecimalDigits:    2,
+		DecimalSeparator: ",",
+		GroupSeparator:   ".",
+		SymbolFirst:      false,
+		CurrencySymbol:   "€",
+		DisplaySymbol:    true,
+	}
+	jpy := &budget.CurrencyFormat{
+		ISOCode:          "JPY",
+		DecimalDigits:    0,
+		DecimalSeparator: ".",
+		GroupSeparator:   ",",
+		SymbolFirst:      true,
+		CurrencySymbol:   "¥",
+		DisplaySymbol:    true,
+	}
+	noSymbol := *usd
+	noSymbol.DisplaySymbol = false
+
+	tests := []struct {
+		name     string
+		format   *budget.CurrencyFormat
+		amount   int64
+		expected string
+	}{
+		{"usd zero", usd, 0, "$0.00"},
+		{"usd cents", usd, 50, "$0.05"},
+		{"usd grouping", usd, 1234567890, "$1,234,567.89"},
+		{"usd negative", usd, -1234560, "-$1,234.56"},
+		{"usd rounding", usd, 1005, "$1.01"},
+		{"usd negative rounding", usd, -1005, "-$1.01"},
+		{"usd exact thousands", usd, 1000000000, "$1,000,000.00"},
+		{"eur", eur, 1234567890, "1.234.567,89€"},
+		{"eur negative", eur, -42500, "-42,50€"},
+		{"jpy", jpy, 1234000, "¥1,234"},
+		{"jpy rounding", jpy, 1234500, "¥1,235"},
+		{"jpy negative", jpy, -999000, "-¥999"},
+		{"no symbol", &noSymbol, 1234560, "1,234.56"},
+		{"three digits", &budget.CurrencyFormat{DecimalDigits: 3, DecimalSeparator: "."}, 1234567, "1234.567"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.format.Format(test.amount))
+		})
+	}
+}
+
+func TestCurrencyFormat_Parse(t *testing.T) {
+	usd := &budget.CurrencyFormat{
+		ISOCode:          "USD",
+		DecimalDigits:    2,
+		DecimalSeparator: ".",
+		GroupSeparator:   ",",
+		SymbolFirst:      true,
+		CurrencySymbol:   "$",
+		DisplaySymbol:    true,
+	}
+	eur := &budget.CurrencyFormat{
+		ISOCode:          "EUR",
+		DecimalDigits:    2,
+		DecimalSeparator: ",",
+		GroupSeparator:   ".",
+		SymbolFirst:      false,
+		CurrencySymbol:   "€",
+		DisplaySymbol:    true,
+	}
+
+	tests := []struct {
+		name     string
+		format   *budget.CurrencyFormat
+		input    string
+		expected int64
+	}{
+		{"usd", usd, "$1,234.56", 1234560},
+		{"usd no symbol", usd, "1234.5", 1234500},
+		{"usd whole", usd, "$42", 42000},
+		{"usd cents only", usd, ".05", 50},
+		{"usd negative", usd, "-$1,234.56", -1234560},
+		{"usd minus after symbol", usd, "$-12.00", -12000},
+		{"usd accounting", usd, "(1,234.56)", -1234560},
+		{"usd accounting with symbol", usd, "($1,234.56)", -1234560},
+		{"usd milliunits", usd, "0.125", 125},
+		{"usd spaces", usd, "  $ 10.00 ", 10000},
+		{"usd plus", usd, "+5", 5000},
+		{"eur", eur, "€1.234,56", 1234560},
+		{"eur trailing symbol", eur, "1.234,56€", 1234560},
+		{"eur negative", eur, "-42,50€", -42500},
+		{"eur accounting", eur, "(1.234,56 €)", -1234560},
+		{"round trip", eur, eur.Format(-987654320), -987654320},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			amount, err := test.format.Parse(test.input)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, amount)
+		})
+	}
+
+	for _, input := range []string{"", "$", "abc", "1.2.3", "1.2345", "--5", "(-5)", "5-", "£5", "99999999999999999999"} {
+		_, err := usd.Parse(input)
+		assert.ErrorIs(t, err, budget.ErrInvalidAmount, input)
+	}
+}
@@ -26,7 +26,7 @@ type Budget struct {
 	Transactions             []*transaction.Summary                 `json:"transactions"`
 	SubTransactions          []*transaction.SubTransaction          `json:"subtransactions"`
 	ScheduledTransactions    []*transaction.ScheduledSummary        `json:"scheduled_transactions"`
-	ScheduledSubTransactions []*transaction.ScheduledSubTransaction `json:"scheduled_sub_transactions"`
+	ScheduledSubTransactions []*transaction.ScheduledSubTransaction `json:"scheduled_subtransactions"`
 
 	// DateFormat the date format setting for the budget. In some cases
 	// the format will not be available and will be specified as null.
@@ -44,6 +44,76 @@ type Budget struct {
 	LastMonth *api.Date `json:"last_month"`
 }
 
+// HydratedTransactions returns the embedded transactions as full
+// transactions: each carries its subtransactions and the names of its
+// account, payee and category, resolved from the other embedded entities
+// as the per-transaction endpoints would return them
+func (b *Budget) HydratedTransactions() []*transaction.Transaction {
+	accountNames := make(map[string]string, len(b.Accounts))
+	for _, a := range b.Accounts {
+		accountNames[a.ID] = a.Name
+	}
+	payeeNames := make(map[string]string, len(b.Payees))
+	for _, p := range b.Payees {
+		payeeNames[p.ID] = p.Name
+	}
+	categoryNames := make(map[string]string, len(b.Categories))
+	for _, c := range b.Categories {
+		categoryNames[c.ID] = c.Name
+	}
+	lookup := func(names map[string]string, id *string) *string {
+		if id == nil {
+			return nil
+		}
+		if name, ok := names[*id]; ok {
+			return &name
+		}
+		return nil
+	}
+
+	subTransactions := make(map[string][]*transaction.SubTransaction)
+	for _, embedded := range b.SubTransactions {
+		sub := *embedded
+		if sub.PayeeName == nil {
+			sub.PayeeName = lookup(payeeNames, sub.PayeeID)
+		}
+		if sub.CategoryName == nil {
+			sub.CategoryName = lookup(categoryNames, sub.CategoryID)
+		}
+		subTransactions[sub.TransactionID] = append(subTransactions[sub.TransactionID], &sub)
+	}
+
+	transactions := make([]*transaction.Transaction, 0, len(b.Transactions))
+	for _, t := range b.Transactions {
+		transactions = append(transactions, &transaction.Transaction{
+			ID:                      t.ID,
+			Date:                    t.Date,
+			Amount:                  t.Amount,
+			Cleared:                 t.Cleared,
+			Approved:                t.Approved,
+			AccountID:               t.AccountID,
+			Deleted:                 t.Deleted,
+			AccountName:             accountNames[t.AccountID],
+			SubTransactions:         subTransactions[t.ID],
+			Memo:                    t.Memo,
+			FlagColor:               t.FlagColor,
+			FlagName:                t.FlagName,
+			PayeeID:                 t.PayeeID,
+			CategoryID:              t.CategoryID,
+			TransferAccountID:       t.TransferAccountID,
+			TransferTransactionID:   t.TransferTransactionID,
+			MatchedTransactionID:    t.MatchedTransactionID,
+			ImportID:                t.ImportID,
+			ImportPayeeName:         t.ImportPayeeName,
+			ImportPayeeNameOriginal: t.ImportPayeeNameOriginal,
+			DebtTransactionType:     t.DebtTransactionType,
+			PayeeName:               lookup(payeeNames, t.PayeeID),
+			CategoryName:            lookup(categoryNames, t.CategoryID),
+		})
+	}
+	return transactions
+}
+
 // Summary represents the summary of a budget
 type Summary struct {
 	ID   string `json:"id"`
@@ -65,6 +135,22 @@ type Summary struct {
 	LastMonth *api.Date `json:"last_month"`
 }
 
+// LastModified returns the last time the budget was changed, or the zero
+// time when the API did not report it
+func (s *Summary) LastModified() time.Time {
+	if s.LastModifiedOn == nil {
+		return time.Time{}
+	}
+	return *s.LastModifiedOn
+}
+
+// BudgetWithAccounts represents the summary of a budget along with its
+// accounts, as listed by GetBudgetsAndAccounts
+type BudgetWithAccounts struct {
+	Summary
+	Accounts []*account.Account `json:"accounts"`
+}
+
 // Snapshot represents a versioned snapshot for a budget
 type Snapshot struct {
 	Budget          *Budget
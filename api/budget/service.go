@@ -1,11 +1,32 @@
 package budget
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/coltoneshaw/ynab.go/api"
 )
 
+// Budget ID aliases accepted by the YNAB API in place of a concrete budget ID.
+// They can be passed as the budgetID argument of any service method, of
+// this package or the account, category, month, payee and transaction
+// ones, and are sent to the API unchanged.
+const (
+	// LastUsed identifies the last used budget
+	LastUsed = "last-used"
+	// Default identifies the default budget, only available when default
+	// budget selection is enabled for the OAuth application
+	Default = "default"
+)
+
+var (
+	// ErrNoBudgetMatch is returned when no budget matches the given name
+	ErrNoBudgetMatch = errors.New("no budget matches name")
+	// ErrBudgetAmbiguous is returned when more than one budget matches the given name
+	ErrBudgetAmbiguous = errors.New("multiple budgets match name")
+)
+
 // NewService facilitates the creation of a new budget service instance
 func NewService(c api.ClientReader) *Service {
 	return &Service{c}
@@ -23,7 +44,8 @@ func (s *Service) GetBudgets() ([]*Summary, error) {
 }
 
 // GetBudgetsWithAccounts fetches the list of budgets of the logger in user
-// with optional account information included
+// with optional account information included. Summary has no room for the
+// accounts; use GetBudgetsAndAccounts to read them.
 // https://api.youneedabudget.com/v1#/Budgets/getBudgets
 func (s *Service) GetBudgetsWithAccounts(includeAccounts bool) ([]*Summary, error) {
 	resModel := struct {
@@ -43,8 +65,61 @@ func (s *Service) GetBudgetsWithAccounts(includeAccounts bool) ([]*Summary, erro
 	return resModel.Data.Budgets, nil
 }
 
+// GetBudgetsAndAccounts fetches the list of budgets of the logged in user
+// along with the accounts of each, in a single request
+// https://api.youneedabudget.com/v1#/Budgets/getBudgets
+func (s *Service) GetBudgetsAndAccounts() ([]*BudgetWithAccounts, error) {
+	resModel := struct {
+		Data struct {
+			Budgets []*BudgetWithAccounts `json:"budgets"`
+		} `json:"data"`
+	}{}
+
+	if err := s.c.GET("/budgets?include_accounts=true", &resModel); err != nil {
+		return nil, err
+	}
+	return resModel.Data.Budgets, nil
+}
+
+// GetBudgetByName fetches the list of budgets and returns the single budget
+// whose name matches exactly. ErrNoBudgetMatch is returned when there
+// is no match and ErrBudgetAmbiguous when more than one budget matches.
+func (s *Service) GetBudgetByName(name string) (*Summary, error) {
+	return s.getBudgetByName(name, func(a, b string) bool { return a == b })
+}
+
+// GetBudgetByNameFold behaves like GetBudgetByName but matches the name
+// case-insensitively
+func (s *Service) GetBudgetByNameFold(name string) (*Summary, error) {
+	return s.getBudgetByName(name, strings.EqualFold)
+}
+
+func (s *Service) getBudgetByName(name string, match func(a, b string) bool) (*Summary, error) {
+	budgets, err := s.GetBudgets()
+	if err != nil {
+		return nil, err
+	}
+
+	var found *Summary
+	for _, b := range budgets {
+		if !match(b.Name, name) {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("%w: %q", ErrBudgetAmbiguous, name)
+		}
+		found = b
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("%w: %q", ErrNoBudgetMatch, name)
+	}
+	return found, nil
+}
+
 // GetBudget fetches a single budget with all related entities,
-// effectively a full budget export with filtering capabilities
+// effectively a full budget export with filtering capabilities.
+// The budgetID may also be one of the LastUsed or Default aliases.
 // https://api.youneedabudget.com/v1#/Budgets/getBudgetById
 func (s *Service) GetBudget(budgetID string, f *api.Filter) (*Snapshot, error) {
 	resModel := struct {
@@ -73,8 +148,14 @@ func (s *Service) GetBudget(budgetID string, f *api.Filter) (*Snapshot, error) {
 // entities, effectively a full budget export with filtering capabilities
 // https://api.youneedabudget.com/v1#/Budgets/getBudgetById
 func (s *Service) GetLastUsedBudget(f *api.Filter) (*Snapshot, error) {
-	const lastUsedBudgetID = "last-used"
-	return s.GetBudget(lastUsedBudgetID, f)
+	return s.GetBudget(LastUsed, f)
+}
+
+// GetDefaultBudget fetches the default budget with all related
+// entities, effectively a full budget export with filtering capabilities
+// https://api.youneedabudget.com/v1#/Budgets/getBudgetById
+func (s *Service) GetDefaultBudget(f *api.Filter) (*Snapshot, error) {
+	return s.GetBudget(Default, f)
 }
 
 // GetBudgetSettings fetches a budget settings
@@ -6,11 +6,17 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/jarcoal/httpmock.v1"
 
 	"github.com/coltoneshaw/ynab.go"
 	"github.com/coltoneshaw/ynab.go/api"
+	"github.com/coltoneshaw/ynab.go/api/account"
 	"github.com/coltoneshaw/ynab.go/api/budget"
+	"github.com/coltoneshaw/ynab.go/api/category"
+	"github.com/coltoneshaw/ynab.go/api/month"
+	"github.com/coltoneshaw/ynab.go/api/payee"
+	"github.com/coltoneshaw/ynab.go/api/transaction"
 )
 
 func TestService_GetBudgets(t *testing.T) {
@@ -646,6 +652,100 @@ func TestService_GetBudget(t *testing.T) {
 	})
 }
 
+func TestService_GetBudget_EmbeddedEntities(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	url := "https://api.youneedabudget.com/v1/budgets/aa248caa-eed7-4575-a990-717386438d2c"
+	httpmock.RegisterResponder(http.MethodGet, url,
+		httpmock.NewStringResponder(200, `{
+  "data": {
+    "budget": {
+      "id": "aa248caa-eed7-4575-a990-717386438d2c",
+      "name": "Test Budget",
+      "accounts": [
+        {"id": "acc-1", "name": "Checking", "type": "checking", "on_budget": true, "balance": 100000}
+      ],
+      "payees": [
+        {"id": "payee-1", "name": "Supermarket"}
+      ],
+      "category_groups": [
+        {"id": "group-1", "name": "Everyday"}
+      ],
+      "categories": [
+        {"id": "cat-1", "category_group_id": "group-1", "name": "Groceries", "balance": 5000},
+        {"id": "cat-2", "category_group_id": "group-1", "name": "Household", "balance": 2000}
+      ],
+      "months": [
+        {"month": "2024-01-01", "income": 500000, "to_be_budgeted": 1000, "categories": []}
+      ],
+      "transactions": [
+        {"id": "tx-1", "date": "2024-01-09", "amount": -8000, "cleared": "cleared", "approved": true,
+         "account_id": "acc-1", "payee_id": "payee-1", "category_id": "cat-1", "deleted": false},
+        {"id": "tx-2", "date": "2024-01-10", "amount": -3000, "cleared": "uncleared", "approved": false,
+         "account_id": "acc-1", "payee_id": "payee-1", "category_id": null, "deleted": false}
+      ],
+      "subtransactions": [
+        {"id": "sub-1", "transaction_id": "tx-2", "amount": -2000, "category_id": "cat-1", "deleted": false},
+        {"id": "sub-2", "transaction_id": "tx-2", "amount": -1000, "category_id": "cat-2", "deleted": false}
+      ],
+      "scheduled_transactions": [
+        {"id": "st-1", "date_first": "2024-01-01", "date_next": "2024-02-01", "frequency": "monthly",
+         "amount": -3000, "account_id": "acc-1", "deleted": false}
+      ],
+      "scheduled_subtransactions": [
+        {"id": "ss-1", "scheduled_transaction_id": "st-1", "amount": -3000, "deleted": false}
+      ]
+    },
+    "server_knowledge": 10
+  }
+}`))
+
+	client := ynab.NewClient("")
+	snapshot, err := client.Budget().GetBudget("aa248caa-eed7-4575-a990-717386438d2c", nil)
+	require.NoError(t, err)
+	b := snapshot.Budget
+
+	if assert.Len(t, b.Accounts, 1) {
+		assert.IsType(t, &account.Account{}, b.Accounts[0])
+		assert.Equal(t, int64(100000), b.Accounts[0].Balance)
+	}
+	if assert.Len(t, b.Payees, 1) {
+		assert.IsType(t, &payee.Payee{}, b.Payees[0])
+	}
+	if assert.Len(t, b.Categories, 2) {
+		assert.IsType(t, &category.Category{}, b.Categories[0])
+	}
+	if assert.Len(t, b.Months, 1) {
+		assert.IsType(t, &month.Month{}, b.Months[0])
+		assert.Equal(t, int64(1000), b.Months[0].ToBeBudgetedAmount())
+	}
+	assert.Len(t, b.Transactions, 2)
+	assert.Len(t, b.SubTransactions, 2)
+	assert.Len(t, b.ScheduledTransactions, 1)
+	assert.Len(t, b.ScheduledSubTransactions, 1)
+
+	transactions := b.HydratedTransactions()
+	require.Len(t, transactions, 2)
+	assert.IsType(t, &transaction.Transaction{}, transactions[0])
+
+	assert.Equal(t, "tx-1", transactions[0].ID)
+	assert.Equal(t, "Checking", transactions[0].AccountName)
+	assert.Equal(t, "Supermarket", *transactions[0].PayeeName)
+	assert.Equal(t, "Groceries", *transactions[0].CategoryName)
+	assert.False(t, transactions[0].IsSplit())
+
+	split := transactions[1]
+	assert.Nil(t, split.CategoryName)
+	if assert.True(t, split.IsSplit()) && assert.Len(t, split.SubTransactions, 2) {
+		assert.Equal(t, "Groceries", *split.SubTransactions[0].CategoryName)
+		assert.Equal(t, "Household", *split.SubTransactions[1].CategoryName)
+	}
+
+	// The embedded subtransactions are left untouched
+	assert.Nil(t, b.SubTransactions[0].CategoryName)
+}
+
 func TestService_GetLastUsedBudget(t *testing.T) {
 	httpmock.Activate()
 	defer httpmock.DeactivateAndReset()
@@ -1005,3 +1105,318 @@ func TestService_GetBudgetsWithAccounts(t *testing.T) {
 	assert.Equal(t, "aa248caa-eed7-4575-a990-717386438d2c", budgets[0].ID)
 	assert.Equal(t, "TestBudget", budgets[0].Name)
 }
+
+func TestService_GetBudgetsAndAccounts(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var query string
+	httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets",
+		func(req *http.Request) (*http.Response, error) {
+			query = req.URL.RawQuery
+			return httpmock.NewStringResponse(200, `{
+  "data": {
+    "budgets": [
+      {
+        "id": "budget-household",
+        "name": "Household",
+        "currency_format": {"iso_code": "EUR", "decimal_digits": 2},
+        "accounts": [
+          {"id": "acc-checking", "name": "Checking", "type": "checking", "on_budget": true, "balance": 150000},
+          {"id": "acc-card", "name": "Credit Card", "type": "creditCard", "on_budget": true, "balance": -25000}
+        ]
+      },
+      {
+        "id": "budget-business",
+        "name": "Business",
+        "accounts": [
+          {"id": "acc-business", "name": "Business Checking", "type": "checking", "on_budget": true, "balance": 900000}
+        ]
+      },
+      {
+        "id": "budget-empty",
+        "name": "Empty",
+        "accounts": []
+      }
+    ]
+  }
+}`), nil
+		},
+	)
+
+	client := ynab.NewClient("")
+	budgets, err := client.Budget().GetBudgetsAndAccounts()
+	require.NoError(t, err)
+	assert.Equal(t, "include_accounts=true", query)
+
+	require.Len(t, budgets, 3)
+	assert.Equal(t, "Household", budgets[0].Name)
+	assert.Equal(t, "EUR", budgets[0].CurrencyFormat.ISOCode)
+	if assert.Len(t, budgets[0].Accounts, 2) {
+		assert.Equal(t, "acc-checking", budgets[0].Accounts[0].ID)
+		assert.Equal(t, int64(150000), budgets[0].Accounts[0].Balance)
+		assert.Equal(t, account.TypeCreditCard, budgets[0].Accounts[1].Type)
+	}
+	assert.Equal(t, "budget-business", budgets[1].ID)
+	if assert.Len(t, budgets[1].Accounts, 1) {
+		assert.Equal(t, "Business Checking", budgets[1].Accounts[0].Name)
+	}
+	assert.Empty(t, budgets[2].Accounts)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestService_GetBudgetByName(t *testing.T) {
+	registerBudgets := func() {
+		httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets",
+			func(req *http.Request) (*http.Response, error) {
+				res := httpmock.NewStringResponse(200, `{
+  "data": {
+    "budgets": [
+      {
+        "id": "aa248caa-eed7-4575-a990-717386438d2c",
+        "name": "Household"
+      },
+      {
+        "id": "bbdccdb0-9007-42aa-a6fe-02a3e94476be",
+        "name": "Business"
+      },
+      {
+        "id": "cc1e7a2f-56cd-4bd5-a2cf-9a8d3d5a1d4c",
+        "name": "business"
+      }
+    ]
+  }
+}
+		`)
+				return res, nil
+			},
+		)
+	}
+
+	t.Run(`single match`, func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		registerBudgets()
+
+		client := ynab.NewClient("")
+		b, err := client.Budget().GetBudgetByName("Household")
+		assert.NoError(t, err)
+		assert.Equal(t, "aa248caa-eed7-4575-a990-717386438d2c", b.ID)
+	})
+
+	t.Run(`exact match is case sensitive`, func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		registerBudgets()
+
+		client := ynab.NewClient("")
+		b, err := client.Budget().GetBudgetByName("business")
+		assert.NoError(t, err)
+		assert.Equal(t, "cc1e7a2f-56cd-4bd5-a2cf-9a8d3d5a1d4c", b.ID)
+	})
+
+	t.Run(`no match`, func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		registerBudgets()
+
+		client := ynab.NewClient("")
+		b, err := client.Budget().GetBudgetByName("household")
+		assert.Nil(t, b)
+		assert.ErrorIs(t, err, budget.ErrNoBudgetMatch)
+	})
+
+	t.Run(`fold single match`, func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		registerBudgets()
+
+		client := ynab.NewClient("")
+		b, err := client.Budget().GetBudgetByNameFold("HOUSEHOLD")
+		assert.NoError(t, err)
+		assert.Equal(t, "aa248caa-eed7-4575-a990-717386438d2c", b.ID)
+	})
+
+	t.Run(`fold duplicate names`, func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		registerBudgets()
+
+		client := ynab.NewClient("")
+		b, err := client.Budget().GetBudgetByNameFold("Business")
+		assert.Nil(t, b)
+		assert.ErrorIs(t, err, budget.ErrBudgetAmbiguous)
+	})
+
+	t.Run(`api error`, func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets",
+			func(req *http.Request) (*http.Response, error) {
+				res := httpmock.NewStringResponse(401, `{
+  "error": {
+    "id": "401",
+    "name": "unauthorized",
+    "detail": "Unauthorized"
+  }
+}
+		`)
+				return res, nil
+			},
+		)
+
+		client := ynab.NewClient("")
+		b, err := client.Budget().GetBudgetByName("Household")
+		assert.Nil(t, b)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, budget.ErrNoBudgetMatch)
+	})
+}
+
+func TestService_GetDefaultBudget(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	url := "https://api.youneedabudget.com/v1/budgets/default"
+	httpmock.RegisterResponder(http.MethodGet, url,
+		func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "/v1/budgets/default", req.URL.Path)
+
+			res := httpmock.NewStringResponse(200, `{
+  "data": {
+    "budget": {
+      "id": "aa248caa-eed7-4575-a990-717386438d2c",
+      "name": "Test Budget"
+    },
+    "server_knowledge": 12
+  }
+}
+		`)
+			return res, nil
+		},
+	)
+
+	client := ynab.NewClient("")
+	snapshot, err := client.Budget().GetDefaultBudget(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "aa248caa-eed7-4575-a990-717386438d2c", snapshot.Budget.ID)
+	assert.Equal(t, uint64(12), snapshot.ServerKnowledge)
+}
+
+func TestBudgetAliases(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets/last-used/accounts",
+		httpmock.NewStringResponder(http.StatusOK, `{"data":{"accounts":[{"id":"account-1","name":"Checking"}],"server_knowledge":1}}`))
+	httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets/default/payees",
+		httpmock.NewStringResponder(http.StatusOK, `{"data":{"payees":[{"id":"payee-1","name":"Landlord"}],"server_knowledge":1}}`))
+
+	client := ynab.NewClient("")
+	accounts, err := client.Account().GetAccounts(budget.LastUsed, nil)
+	require.NoError(t, err)
+	assert.Len(t, accounts.Accounts, 1)
+
+	payees, err := client.Payee().GetPayees(budget.Default, nil)
+	require.NoError(t, err)
+	assert.Len(t, payees.Payees, 1)
+
+	assert.Equal(t, map[string]int{
+		"GET https://api.youneedabudget.com/v1/budgets/last-used/accounts": 1,
+		"GET https://api.youneedabudget.com/v1/budgets/default/payees":     1,
+	}, httpmock.GetCallCountInfo())
+}
+
+func TestService_GetBudgets_StrictEnvelope(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+	}{
+		{name: "empty list", body: `{"data":{"budgets":[]}}`},
+		{name: "missing envelope", body: `{}`, err: api.ErrMissingEnvelope},
+		{name: "null envelope", body: `{"data":null}`, err: api.ErrMissingEnvelope},
+		{name: "empty body", body: ``, err: api.ErrMissingEnvelope},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets",
+				httpmock.NewStringResponder(http.StatusOK, test.body))
+
+			client := ynab.NewClient("").WithStrictEnvelope()
+			budgets, err := client.Budget().GetBudgets()
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				assert.Nil(t, budgets)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Empty(t, budgets)
+		})
+	}
+
+	t.Run("lenient by default", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets",
+			httpmock.NewStringResponder(http.StatusOK, `{}`))
+
+		client := ynab.NewClient("")
+		budgets, err := client.Budget().GetBudgets()
+		assert.NoError(t, err)
+		assert.Empty(t, budgets)
+	})
+}
+
+func TestService_GetBudgets_Dates(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets",
+		httpmock.NewStringResponder(http.StatusOK, `{
+  "data": {
+    "budgets": [
+      {
+        "id": "aa248caa-eed7-4575-a990-717386438d2c",
+        "name": "Recent",
+        "last_modified_on": "2024-03-05T17:05:23+00:00",
+        "first_month": "2023-01-01",
+        "last_month": "2024-04-01"
+      },
+      {
+        "id": "f419ac25-6217-4175-88dc-c3136ff5f6fd",
+        "name": "Never Modified"
+      }
+    ]
+  }
+}`))
+
+	client := ynab.NewClient("")
+	budgets, err := client.Budget().GetBudgets()
+	assert.NoError(t, err)
+	if !assert.Len(t, budgets, 2) {
+		return
+	}
+
+	expectedFirstMonth, err := api.DateFromString("2023-01-01")
+	assert.NoError(t, err)
+	expectedLastMonth, err := api.DateFromString("2024-04-01")
+	assert.NoError(t, err)
+
+	recent := budgets[0]
+	assert.True(t, time.Date(2024, 3, 5, 17, 5, 23, 0, time.UTC).Equal(recent.LastModified()))
+	assert.Equal(t, &expectedFirstMonth, recent.FirstMonth)
+	assert.Equal(t, &expectedLastMonth, recent.LastMonth)
+
+	never := budgets[1]
+	assert.True(t, never.LastModified().IsZero())
+	assert.Nil(t, never.FirstMonth)
+	assert.Nil(t, never.LastMonth)
+	assert.True(t, recent.LastModified().After(never.LastModified()))
+}
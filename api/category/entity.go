@@ -79,6 +79,21 @@ type GroupWithCategories struct {
 	Categories []*Category `json:"categories"`
 }
 
+// CategoryGroup represents a category group for a budget along with
+// its categories
+type CategoryGroup = GroupWithCategories
+
+// IsHidden returns true if the category is hidden
+func (c *Category) IsHidden() bool {
+	return c.Hidden
+}
+
+// Available returns the balance of the category, named Available in the
+// YNAB UI, in milliunits format
+func (c *Category) Available() int64 {
+	return c.Balance
+}
+
 // SearchResultSnapshot represents a versioned snapshot for an account search
 type SearchResultSnapshot struct {
 	GroupWithCategories []*GroupWithCategories
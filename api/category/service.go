@@ -1,14 +1,11 @@
 package category
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"github.com/coltoneshaw/ynab.go/api"
 )
 
-const currentMonthID = "current"
-
 // NewService facilitates the creation of a new category service instance
 func NewService(c api.ClientReaderWriter) *Service {
 	return &Service{c}
@@ -43,7 +40,46 @@ func (s *Service) GetCategories(budgetID string, f *api.Filter) (*SearchResultSn
 	}, nil
 }
 
-// GetCategory fetches a specific category from a budget
+// GetCategoryGroups fetches the category groups for a budget, each with
+// its categories
+// https://api.youneedabudget.com/v1#/Categories/getCategories
+func (s *Service) GetCategoryGroups(budgetID string) ([]*CategoryGroup, error) {
+	snapshot, err := (&Service{c: api.FullReads(s.c)}).GetCategories(budgetID, nil)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.GroupWithCategories, nil
+}
+
+// GetCategoryNameMap returns the names of the categories of a budget keyed
+// by category ID. Hidden and deleted categories, including those of hidden
+// or deleted groups, are left out unless api.IncludeHidden or
+// api.IncludeDeleted is given.
+// https://api.youneedabudget.com/v1#/Categories/getCategories
+func (s *Service) GetCategoryNameMap(budgetID string, opts ...api.IncludeOption) (map[string]string, error) {
+	groups, err := s.GetCategoryGroups(budgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	o := api.NewIncludeOptions(opts...)
+	names := make(map[string]string)
+	for _, g := range groups {
+		if g == nil || (g.Hidden && !o.Hidden) || (g.Deleted && !o.Deleted) {
+			continue
+		}
+		for _, c := range g.Categories {
+			if c == nil || (c.Hidden && !o.Hidden) || (c.Deleted && !o.Deleted) {
+				continue
+			}
+			names[c.ID] = c.Name
+		}
+	}
+	return names, nil
+}
+
+// GetCategory fetches a specific category from a budget. Its budgeted,
+// activity and balance amounts are those of the current month.
 // https://api.youneedabudget.com/v1#/Categories/getCategoryById
 func (s *Service) GetCategory(budgetID, categoryID string) (*Category, error) {
 	resModel := struct {
@@ -70,7 +106,7 @@ func (s *Service) GetCategoryForMonth(budgetID, categoryID string,
 // GetCategoryForCurrentMonth fetches a specific category from the current budget month
 // https://api.youneedabudget.com/v1#/Categories/getMonthCategoryById
 func (s *Service) GetCategoryForCurrentMonth(budgetID, categoryID string) (*Category, error) {
-	return s.getCategoryForMonth(budgetID, categoryID, currentMonthID)
+	return s.getCategoryForMonth(budgetID, categoryID, api.CurrentMonth)
 }
 
 func (s *Service) getCategoryForMonth(budgetID, categoryID, month string) (*Category, error) {
@@ -80,7 +116,12 @@ func (s *Service) getCategoryForMonth(budgetID, categoryID, month string) (*Cate
 		} `json:"data"`
 	}{}
 
-	url := fmt.Sprintf("/budgets/%s/months/%s/categories/%s", budgetID, month, categoryID)
+	monthParam, err := api.MonthParam(month)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("/budgets/%s/months/%s/categories/%s", budgetID, monthParam, categoryID)
 	if err := s.c.GET(url, &resModel); err != nil {
 		return nil, err
 	}
@@ -100,19 +141,24 @@ func (s *Service) UpdateCategoryForMonth(budgetID, categoryID string, month api.
 func (s *Service) UpdateCategoryForCurrentMonth(budgetID, categoryID string,
 	p PayloadMonthCategory) (*Category, error) {
 
-	return s.updateCategoryForMonth(budgetID, categoryID, currentMonthID, p)
+	return s.updateCategoryForMonth(budgetID, categoryID, api.CurrentMonth, p)
 }
 
 func (s *Service) updateCategoryForMonth(budgetID, categoryID, month string,
 	p PayloadMonthCategory) (*Category, error) {
 
+	monthParam, err := api.MonthParam(month)
+	if err != nil {
+		return nil, err
+	}
+
 	payload := struct {
 		Category *PayloadMonthCategory `json:"category"`
 	}{
 		&p,
 	}
 
-	buf, err := json.Marshal(&payload)
+	buf, err := api.Marshal(s.c, &payload)
 	if err != nil {
 		return nil, err
 	}
@@ -124,7 +170,7 @@ func (s *Service) updateCategoryForMonth(budgetID, categoryID, month string,
 	}{}
 
 	url := fmt.Sprintf("/budgets/%s/months/%s/categories/%s", budgetID,
-		month, categoryID)
+		monthParam, categoryID)
 
 	if err := s.c.PATCH(url, &resModel, buf); err != nil {
 		return nil, err
@@ -141,7 +187,7 @@ func (s *Service) UpdateCategory(budgetID, categoryID string, p PayloadCategory)
 		&p,
 	}
 
-	buf, err := json.Marshal(&payload)
+	buf, err := api.Marshal(s.c, &payload)
 	if err != nil {
 		return nil, err
 	}
@@ -103,6 +103,99 @@ func TestService_GetCategories(t *testing.T) {
 	assert.Equal(t, expected, snapshot)
 }
 
+func TestService_GetCategoryGroups(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	url := "https://api.youneedabudget.com/v1/budgets/aa248caa-eed7-4575-a990-717386438d2c/categories"
+	httpmock.RegisterResponder(http.MethodGet, url,
+		func(req *http.Request) (*http.Response, error) {
+			res := httpmock.NewStringResponse(200, `{
+  "data": {
+    "category_groups": [
+      {
+        "id": "13419c12-78d3-4818-a5dc-601b2b8a6064",
+        "name": "Bills",
+        "hidden": false,
+        "deleted": false,
+        "categories": [
+          {
+            "id": "13419c12-78d3-4a26-82ca-1cde7aa1d6f8",
+            "category_group_id": "13419c12-78d3-4818-a5dc-601b2b8a6064",
+            "category_group_name": "Bills",
+            "name": "Rent",
+            "hidden": false,
+            "budgeted": 0,
+            "activity": 0,
+            "balance": 0,
+            "deleted": false
+          }
+        ]
+      },
+      {
+        "id": "2b8a6064-78d3-4818-a5dc-601b13419c12",
+        "name": "Archive",
+        "hidden": true,
+        "deleted": false,
+        "categories": [
+          {
+            "id": "1cde7aa1-78d3-4a26-82ca-d6f813419c12",
+            "category_group_id": "2b8a6064-78d3-4818-a5dc-601b13419c12",
+            "category_group_name": "Archive",
+            "name": "Old Car",
+            "hidden": true,
+            "budgeted": 0,
+            "activity": 0,
+            "balance": 0,
+            "deleted": false
+          }
+        ]
+      }
+    ],
+    "server_knowledge": 10
+  }
+}`)
+			return res, nil
+		},
+	)
+
+	client := ynab.NewClient("")
+	groups, err := client.Category().GetCategoryGroups("aa248caa-eed7-4575-a990-717386438d2c")
+	assert.NoError(t, err)
+
+	expected := []*category.CategoryGroup{
+		{
+			ID:   "13419c12-78d3-4818-a5dc-601b2b8a6064",
+			Name: "Bills",
+			Categories: []*category.Category{
+				{
+					ID:                "13419c12-78d3-4a26-82ca-1cde7aa1d6f8",
+					CategoryGroupID:   "13419c12-78d3-4818-a5dc-601b2b8a6064",
+					CategoryGroupName: "Bills",
+					Name:              "Rent",
+				},
+			},
+		},
+		{
+			ID:     "2b8a6064-78d3-4818-a5dc-601b13419c12",
+			Name:   "Archive",
+			Hidden: true,
+			Categories: []*category.Category{
+				{
+					ID:                "1cde7aa1-78d3-4a26-82ca-d6f813419c12",
+					CategoryGroupID:   "2b8a6064-78d3-4818-a5dc-601b13419c12",
+					CategoryGroupName: "Archive",
+					Name:              "Old Car",
+					Hidden:            true,
+				},
+			},
+		},
+	}
+	assert.Equal(t, expected, groups)
+	assert.False(t, groups[0].Categories[0].IsHidden())
+	assert.True(t, groups[1].Categories[0].IsHidden())
+}
+
 func TestService_GetCategory(t *testing.T) {
 	httpmock.Activate()
 	defer httpmock.DeactivateAndReset()
@@ -168,6 +261,7 @@ func TestService_GetCategory(t *testing.T) {
 		GoalPercentageComplete: &expectedGoalPercentageComplete,
 	}
 	assert.Equal(t, expected, c)
+	assert.Equal(t, int64(18740), c.Available())
 }
 
 func TestService_GetCategoryForMonth(t *testing.T) {
@@ -466,3 +560,38 @@ func TestService_UpdateCategoryForCurrentMonth(t *testing.T) {
 	}
 	assert.Equal(t, expected, c)
 }
+
+func TestService_GetCategoryNameMap(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	url := "https://api.youneedabudget.com/v1/budgets/aa248caa/categories"
+	httpmock.RegisterResponder(http.MethodGet, url, httpmock.NewStringResponder(http.StatusOK, `{"data":{"category_groups":[
+		{"id":"group-everyday","name":"Everyday","categories":[
+			{"id":"cat-groceries","name":"Groceries"},
+			{"id":"cat-hidden","name":"Old Gym","hidden":true},
+			{"id":"cat-deleted","name":"Takeout","deleted":true}
+		]},
+		{"id":"group-hidden","name":"Archive","hidden":true,"categories":[
+			{"id":"cat-archived","name":"Travel 2019"}
+		]}
+	],"server_knowledge":1}}`))
+
+	client := ynab.NewClient("")
+	names, err := client.Category().GetCategoryNameMap("aa248caa")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"cat-groceries": "Groceries"}, names)
+
+	names, err = client.Category().GetCategoryNameMap("aa248caa", api.IncludeHidden())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"cat-groceries": "Groceries",
+		"cat-hidden":    "Old Gym",
+		"cat-archived":  "Travel 2019",
+	}, names)
+
+	names, err = client.Category().GetCategoryNameMap("aa248caa", api.IncludeHidden(), api.IncludeDeleted())
+	assert.NoError(t, err)
+	assert.Len(t, names, 4)
+	assert.Equal(t, "Takeout", names["cat-deleted"])
+}
@@ -0,0 +1,62 @@
+package api
+
+import "errors"
+
+// ErrorClass tells how an application should react to an API error
+type ErrorClass int
+
+const (
+	// ErrorClassFatal identifies an error the request cannot recover from
+	ErrorClassFatal ErrorClass = iota
+	// ErrorClassTransient identifies an error that may be resolved by
+	// retrying the request
+	ErrorClassTransient
+	// ErrorClassIgnorable identifies an error that is not a failure for the
+	// application, such as a conflict on a create it treats as a no-op
+	ErrorClassIgnorable
+)
+
+// String returns the name of the error class
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorClassFatal:
+		return "fatal"
+	case ErrorClassTransient:
+		return "transient"
+	case ErrorClassIgnorable:
+		return "ignorable"
+	}
+	return "unknown"
+}
+
+// ErrorClassifier maps an API error to the class driving how it is
+// handled, centralizing an application's error policy
+type ErrorClassifier func(*Error) ErrorClass
+
+// DefaultErrorClassifier classifies retryable errors as transient and every
+// other error as fatal
+func DefaultErrorClassifier(e *Error) ErrorClass {
+	if e.IsRetryable() {
+		return ErrorClassTransient
+	}
+	return ErrorClassFatal
+}
+
+// Class returns the class of the error under DefaultErrorClassifier
+func (e *Error) Class() ErrorClass {
+	return DefaultErrorClassifier(e)
+}
+
+// ClassifyError returns the class of the *Error wrapped in err under
+// classifier, or DefaultErrorClassifier when classifier is nil. Errors that
+// are not API errors, such as network failures, are fatal.
+func ClassifyError(err error, classifier ErrorClassifier) ErrorClass {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		return ErrorClassFatal
+	}
+	if classifier == nil {
+		classifier = DefaultErrorClassifier
+	}
+	return classifier(apiErr)
+}
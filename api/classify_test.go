@@ -0,0 +1,42 @@
+package api_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coltoneshaw/ynab.go/api"
+)
+
+func TestError_Class(t *testing.T) {
+	assert.Equal(t, api.ErrorClassTransient, (&api.Error{ID: api.ErrorRateLimit}).Class())
+	assert.Equal(t, api.ErrorClassTransient, (&api.Error{ID: api.ErrorServiceUnavailable}).Class())
+	assert.Equal(t, api.ErrorClassFatal, (&api.Error{ID: api.ErrorConflict}).Class())
+	assert.Equal(t, api.ErrorClassFatal, (&api.Error{ID: api.ErrorNotFound}).Class())
+}
+
+func TestClassifyError(t *testing.T) {
+	conflict := &api.Error{ID: api.ErrorConflict}
+	wrapped := fmt.Errorf("creating transaction: %w", conflict)
+
+	ignoreConflicts := func(e *api.Error) api.ErrorClass {
+		if e.IsConflict() {
+			return api.ErrorClassIgnorable
+		}
+		return api.DefaultErrorClassifier(e)
+	}
+
+	assert.Equal(t, api.ErrorClassIgnorable, api.ClassifyError(wrapped, ignoreConflicts))
+	assert.Equal(t, api.ErrorClassFatal, api.ClassifyError(wrapped, nil))
+	assert.Equal(t, api.ErrorClassTransient, api.ClassifyError(&api.Error{ID: api.ErrorRateLimit}, ignoreConflicts))
+	assert.Equal(t, api.ErrorClassFatal, api.ClassifyError(errors.New("connection reset"), ignoreConflicts))
+}
+
+func TestErrorClass_String(t *testing.T) {
+	assert.Equal(t, "fatal", api.ErrorClassFatal.String())
+	assert.Equal(t, "transient", api.ErrorClassTransient.String())
+	assert.Equal(t, "ignorable", api.ErrorClassIgnorable.String())
+	assert.Equal(t, "unknown", api.ErrorClass(42).String())
+}
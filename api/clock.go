@@ -0,0 +1,19 @@
+package api
+
+import "time"
+
+// Clock provides the current time. It allows time-dependent components,
+// such as RateLimitTracker, to be driven by a fake clock in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the Clock backed by time.Now, used by default
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+// Now returns the current local time
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,41 @@
+package api
+
+import "encoding/json"
+
+// Codec encodes request bodies and decodes response bodies. It allows a
+// faster JSON library to be plugged in without this module importing it.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// StdlibCodec is the Codec backed by encoding/json, used by default
+var StdlibCodec Codec = stdlibCodec{}
+
+type stdlibCodec struct{}
+
+// Marshal encodes v with json.Marshal
+func (stdlibCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal decodes data into v with json.Unmarshal
+func (stdlibCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// CodecProvider is implemented by clients configured with a Codec
+type CodecProvider interface {
+	Codec() Codec
+}
+
+// Marshal encodes a request body with the codec of c when it implements
+// CodecProvider, falling back to StdlibCodec otherwise
+func Marshal(c any, v any) ([]byte, error) {
+	if p, ok := c.(CodecProvider); ok {
+		if codec := p.Codec(); codec != nil {
+			return codec.Marshal(v)
+		}
+	}
+	return StdlibCodec.Marshal(v)
+}
@@ -0,0 +1,45 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coltoneshaw/ynab.go/api"
+)
+
+type upperCodec struct{ api.Codec }
+
+func (upperCodec) Marshal(v any) ([]byte, error) {
+	return []byte(`"CUSTOM"`), nil
+}
+
+type codecClient struct{ codec api.Codec }
+
+func (c codecClient) Codec() api.Codec { return c.codec }
+
+func TestMarshal(t *testing.T) {
+	payload := map[string]int{"amount": 1000}
+
+	buf, err := api.Marshal(nil, payload)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":1000}`, string(buf))
+
+	buf, err = api.Marshal(codecClient{codec: upperCodec{}}, payload)
+	require.NoError(t, err)
+	assert.Equal(t, `"CUSTOM"`, string(buf))
+
+	buf, err = api.Marshal(codecClient{}, payload)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":1000}`, string(buf))
+}
+
+func TestHTTPClient_WithCodec(t *testing.T) {
+	h := api.NewHTTPClient()
+	assert.Equal(t, api.StdlibCodec, h.Codec())
+
+	codec := upperCodec{Codec: api.StdlibCodec}
+	assert.Equal(t, codec, h.WithCodec(codec).Codec())
+	assert.Equal(t, api.StdlibCodec, h.WithCodec(nil).Codec())
+}
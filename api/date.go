@@ -9,6 +9,12 @@ import (
 // dateLayout expected layout format for the Date type
 const dateLayout = "2006-01-02"
 
+// monthLayout layout format for a year and month without a day
+const monthLayout = "2006-01"
+
+// CurrentMonth is the month path alias YNAB resolves to the current month
+const CurrentMonth = "current"
+
 // Date represents a budget date
 type Date struct {
 	time.Time
@@ -52,3 +58,23 @@ func DateFromString(s string) (Date, error) {
 func DateFormat(date Date) string {
 	return date.Format(dateLayout)
 }
+
+// MonthParam normalizes a month path argument to the "YYYY-MM-01" form
+// expected by the YNAB API. It accepts a full ISO date ("YYYY-MM-DD"),
+// a year and month ("YYYY-MM") or the CurrentMonth alias, which is
+// returned unchanged. Any other value returns an error.
+func MonthParam(s string) (string, error) {
+	if s == CurrentMonth {
+		return s, nil
+	}
+
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		t, err = time.Parse(monthLayout, s)
+	}
+	if err != nil {
+		return "", fmt.Errorf("api: invalid month %q: expected YYYY-MM-DD, YYYY-MM or %q", s, CurrentMonth)
+	}
+
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC).Format(dateLayout), nil
+}
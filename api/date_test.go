@@ -97,3 +97,31 @@ func TestDateFormat(t *testing.T) {
 		assert.Equal(t, test.OutputFormattedDate, formattedDate)
 	}
 }
+
+func TestMonthParam(t *testing.T) {
+	table := []struct {
+		Name        string
+		Input       string
+		Output      string
+		OutputError bool
+	}{
+		{"full_date_first_day", "2018-11-01", "2018-11-01", false},
+		{"full_date_mid_month", "2018-11-13", "2018-11-01", false},
+		{"full_date_last_day", "2024-02-29", "2024-02-01", false},
+		{"year_month", "2018-11", "2018-11-01", false},
+		{"current", "current", "current", false},
+		{"month_name", "November", "", true},
+		{"invalid_month", "2018-13", "", true},
+		{"invalid_day", "2018-02-30", "", true},
+		{"empty", "", "", true},
+		{"uppercase_current", "CURRENT", "", true},
+	}
+
+	for _, test := range table {
+		t.Run(test.Name, func(t *testing.T) {
+			month, err := api.MonthParam(test.Input)
+			assert.Equal(t, test.OutputError, err != nil)
+			assert.Equal(t, test.Output, month)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 )
@@ -37,17 +38,54 @@ const (
 	ErrorServiceUnavailable = "503" // API temporarily disabled or request timeout
 )
 
+// ErrUnauthorized matches with errors.Is any API error with the
+// ErrorUnauthorized ID
+var ErrUnauthorized = errors.New("api: unauthorized")
+
+// ErrTokenRejected wraps the 401 error of a request sent with a personal
+// access token, which can only succeed again with a new token
+var ErrTokenRejected = errors.New("api: personal access token rejected; verify it hasn't been revoked")
+
+// ErrBudgetNotFound matches, through errors.Is, the ErrorNotFound error of
+// a request under a budget, so that a wrong budget ID stands out from a
+// missing resource
+var ErrBudgetNotFound = errors.New("api: budget not found")
+
 // Error represents an API Error
 type Error struct {
 	ID     string `json:"id"`
 	Name   string `json:"name"`
 	Detail string `json:"detail"`
+
+	// BudgetID names the budget of the request of an ErrorNotFound error
+	// when the request was made under one
+	BudgetID string `json:"-"`
 }
 
 // Error returns the string version of the error
 func (e Error) Error() string {
-	return fmt.Sprintf("api: error id=%s name=%s detail=%s",
+	msg := fmt.Sprintf("api: error id=%s name=%s detail=%s",
 		e.ID, e.Name, e.Detail)
+	if e.BudgetID != "" {
+		msg += fmt.Sprintf(" budget=%q", e.BudgetID)
+	}
+	return msg
+}
+
+// Is reports whether the error matches target, so that errors.Is(err,
+// ErrUnauthorized) holds for unauthorized errors and errors.Is(err,
+// ErrBudgetNotFound) for the ErrorNotFound errors of a budget
+func (e *Error) Is(target error) bool {
+	if e == nil {
+		return false
+	}
+	switch target {
+	case ErrUnauthorized:
+		return e.IsUnauthorized()
+	case ErrBudgetNotFound:
+		return e.IsURINotFound() && e.BudgetID != ""
+	}
+	return false
 }
 
 // Account/Subscription related error checks
@@ -74,6 +112,12 @@ func (e *Error) IsUnauthorized() bool {
 	return e.ID == ErrorUnauthorized
 }
 
+// IsTokenInvalid returns true if the access token was rejected, as revoked,
+// expired or mistyped. It is an alias of IsUnauthorized.
+func (e *Error) IsTokenInvalid() bool {
+	return e.IsUnauthorized()
+}
+
 // IsUnauthorizedScope returns true if the error indicates insufficient permissions
 func (e *Error) IsUnauthorizedScope() bool {
 	return e.ID == ErrorUnauthorizedScope
@@ -86,9 +130,21 @@ func (e *Error) IsAuthenticationError() bool {
 
 // Resource related error checks
 
-// IsNotFound returns true if the error indicates a resource was not found
+// IsNotFound returns true if the error indicates a resource was not found,
+// either through IsURINotFound or IsResourceNotFound
 func (e *Error) IsNotFound() bool {
-	return e.ID == ErrorNotFound || e.ID == ErrorResourceNotFound
+	return e.IsURINotFound() || e.IsResourceNotFound()
+}
+
+// IsURINotFound returns true if the requested URI does not exist, as when
+// the budget ID of the request is wrong
+func (e *Error) IsURINotFound() bool {
+	return e.ID == ErrorNotFound
+}
+
+// IsResourceNotFound returns true if the requested resource does not exist
+func (e *Error) IsResourceNotFound() bool {
+	return e.ID == ErrorResourceNotFound
 }
 
 // IsConflict returns true if the error indicates a resource conflict
@@ -142,7 +198,34 @@ func (e *Error) IsValidationError() bool {
 	return e.ID == ErrorBadRequest
 }
 
+// IsNoLinkedAccounts returns true if the error is the bad request the
+// transactions import endpoint returns for a budget without any linked
+// account. The API has no dedicated error ID for it, so the detail is
+// matched.
+func (e *Error) IsNoLinkedAccounts() bool {
+	return e.IsValidationError() && strings.Contains(strings.ToLower(e.Detail), "linked account")
+}
+
 // RequiresUserAction returns true if the error requires user intervention
 func (e *Error) RequiresUserAction() bool {
 	return e.IsAccountError() || e.IsAuthenticationError() || e.IsDataLimitReached()
 }
+
+// withBudgetContext sets the BudgetID of an ErrorNotFound error of a
+// request to a URL under a budget. The error is returned as is, still an
+// *Error, so type assertions keep working.
+func withBudgetContext(url string, err error) error {
+	apiErr, ok := err.(*Error)
+	if !ok || apiErr == nil || !apiErr.IsURINotFound() {
+		return err
+	}
+
+	if i := strings.IndexByte(url, '?'); i >= 0 {
+		url = url[:i]
+	}
+	segments := strings.Split(strings.TrimPrefix(url, "/"), "/")
+	if len(segments) >= 2 && segments[0] == "budgets" && segments[1] != "" {
+		apiErr.BudgetID = segments[1]
+	}
+	return err
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -349,6 +350,25 @@ func TestError_IsValidationError(t *testing.T) {
 	}
 }
 
+func TestError_IsNoLinkedAccounts(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      *Error
+		expected bool
+	}{
+		{"no linked accounts", &Error{ID: ErrorBadRequest, Detail: "This budget does not have any linked accounts"}, true},
+		{"case insensitive", &Error{ID: ErrorBadRequest, Detail: "No Linked Accounts found"}, true},
+		{"other bad request", &Error{ID: ErrorBadRequest, Detail: "Invalid date"}, false},
+		{"other error id", &Error{ID: ErrorNotFound, Detail: "linked accounts"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.err.IsNoLinkedAccounts())
+		})
+	}
+}
+
 func TestError_RequiresUserAction(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -450,3 +470,53 @@ func TestError_UsageScenarios(t *testing.T) {
 		assert.True(t, err.IsServerError())
 	})
 }
+
+func TestError_Unauthorized(t *testing.T) {
+	unauthorized := &Error{ID: ErrorUnauthorized, Name: "unauthorized"}
+	assert.True(t, unauthorized.IsTokenInvalid())
+	assert.ErrorIs(t, unauthorized, ErrUnauthorized)
+	assert.ErrorIs(t, fmt.Errorf("%w: %w", ErrTokenRejected, unauthorized), ErrUnauthorized)
+
+	scope := &Error{ID: ErrorUnauthorizedScope}
+	assert.False(t, scope.IsTokenInvalid())
+	assert.NotErrorIs(t, scope, ErrUnauthorized)
+}
+
+func TestError_NotFoundKinds(t *testing.T) {
+	uri := &Error{ID: ErrorNotFound}
+	assert.True(t, uri.IsNotFound())
+	assert.True(t, uri.IsURINotFound())
+	assert.False(t, uri.IsResourceNotFound())
+
+	resource := &Error{ID: ErrorResourceNotFound}
+	assert.True(t, resource.IsNotFound())
+	assert.False(t, resource.IsURINotFound())
+	assert.True(t, resource.IsResourceNotFound())
+}
+
+func TestWithBudgetContext(t *testing.T) {
+	uri := &Error{ID: ErrorNotFound, Name: "not_found", Detail: "Not found"}
+	assert.NotErrorIs(t, uri, ErrBudgetNotFound)
+
+	err := withBudgetContext("/budgets/aa248caa/accounts?last_knowledge_of_server=3", uri)
+	assert.EqualError(t, err, `api: error id=404.1 name=not_found detail=Not found budget="aa248caa"`)
+	assert.ErrorIs(t, err, ErrBudgetNotFound)
+	apiErr, ok := err.(*Error)
+	if assert.True(t, ok) {
+		assert.Same(t, uri, apiErr)
+		assert.Equal(t, "aa248caa", apiErr.BudgetID)
+	}
+
+	other := &Error{ID: ErrorNotFound}
+	assert.Same(t, other, withBudgetContext("/user", other))
+	assert.Empty(t, other.BudgetID)
+	assert.NotErrorIs(t, other, ErrBudgetNotFound)
+
+	resource := &Error{ID: ErrorResourceNotFound}
+	assert.Same(t, resource, withBudgetContext("/budgets/aa248caa/accounts/missing", resource))
+	assert.Empty(t, resource.BudgetID)
+	assert.NotErrorIs(t, resource, ErrBudgetNotFound)
+	assert.NoError(t, withBudgetContext("/budgets/aa248caa", nil))
+	var typedNil *Error
+	assert.Equal(t, error(typedNil), withBudgetContext("/budgets/aa248caa", typedNil))
+}
@@ -4,23 +4,36 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 const APIEndpoint = "https://api.youneedabudget.com/v1"
 
+// DefaultTimeout is the request timeout used by NewHTTPClient
+const DefaultTimeout = 30 * time.Second
+
+// ErrMissingEnvelope is returned in strict envelope mode when a successful
+// response body has no data key
+var ErrMissingEnvelope = errors.New("api: response is missing the data envelope")
+
 // HTTPClient represents a configurable HTTP client
 type HTTPClient struct {
-	client *http.Client
+	client         *http.Client
+	observer       Observer
+	strictEnvelope bool
+	codec          Codec
 }
 
 // NewHTTPClient creates a new HTTP client with default configuration
+// and a DefaultTimeout request timeout
 func NewHTTPClient() *HTTPClient {
 	return &HTTPClient{
-		client: http.DefaultClient,
+		client: &http.Client{Timeout: DefaultTimeout},
 	}
 }
 
@@ -31,12 +44,57 @@ func NewHTTPClientWithClient(client *http.Client) *HTTPClient {
 	}
 }
 
-// WithHTTPClient sets a custom HTTP client
+// WithHTTPClient sets a custom HTTP client, replacing any timeout
+// previously set through WithTimeout with the client's own Timeout
 func (h *HTTPClient) WithHTTPClient(client *http.Client) *HTTPClient {
 	h.client = client
 	return h
 }
 
+// WithTimeout sets the request timeout of the underlying HTTP client.
+// The client is copied first so an http.Client passed to WithHTTPClient
+// is never modified. A zero timeout disables the timeout.
+func (h *HTTPClient) WithTimeout(timeout time.Duration) *HTTPClient {
+	client := *h.client
+	client.Timeout = timeout
+	h.client = &client
+	return h
+}
+
+// WithObserver sets an observer notified after every request
+func (h *HTTPClient) WithObserver(observer Observer) *HTTPClient {
+	h.observer = observer
+	return h
+}
+
+// WithStrictEnvelope makes successful responses without a data key fail
+// with ErrMissingEnvelope instead of leaving the response model empty, so
+// a partial response cannot be mistaken for an empty result
+func (h *HTTPClient) WithStrictEnvelope() *HTTPClient {
+	h.strictEnvelope = true
+	return h
+}
+
+// WithCodec sets the codec used to decode response bodies. A nil codec
+// restores StdlibCodec.
+func (h *HTTPClient) WithCodec(codec Codec) *HTTPClient {
+	h.codec = codec
+	return h
+}
+
+// Codec returns the codec used to decode response bodies
+func (h *HTTPClient) Codec() Codec {
+	if h.codec == nil {
+		return StdlibCodec
+	}
+	return h.codec
+}
+
+// Timeout returns the request timeout of the underlying HTTP client
+func (h *HTTPClient) Timeout() time.Duration {
+	return h.client.Timeout
+}
+
 // PrepareRequest prepares an HTTP request with common headers
 func (h *HTTPClient) PrepareRequest(ctx context.Context, method, url string, requestBody []byte) (*http.Request, error) {
 	fullURL := fmt.Sprintf("%s%s", APIEndpoint, url)
@@ -75,9 +133,19 @@ func (h *HTTPClient) ExecuteRequest(req *http.Request) (*http.Response, error) {
 }
 
 // HandleResponse processes the HTTP response and handles errors
+//
+// A StreamFunc response model is handed the body of a successful response
+// unread instead of having it decoded.
 func (h *HTTPClient) HandleResponse(resp *http.Response, responseModel any) error {
 	defer func() { _ = resp.Body.Close() }()
 
+	if fn, ok := responseModel.(StreamFunc); ok && resp.StatusCode < 400 {
+		if resp.StatusCode == http.StatusNoContent {
+			return nil
+		}
+		return fn(resp.Body)
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return fmt.Errorf("failed to read response: %w", err)
@@ -88,8 +156,9 @@ func (h *HTTPClient) HandleResponse(resp *http.Response, responseModel any) erro
 			Error *Error `json:"error"`
 		}{}
 
-		if err := json.Unmarshal(body, &response); err != nil {
-			// Return a forged *Error for ease of use
+		// Return a forged *Error for ease of use when the body is not JSON
+		// or holds no error object
+		if err := h.Codec().Unmarshal(body, &response); err != nil || response.Error == nil {
 			apiError := &Error{
 				ID:     strconv.Itoa(resp.StatusCode),
 				Name:   "unknown_api_error",
@@ -101,11 +170,38 @@ func (h *HTTPClient) HandleResponse(resp *http.Response, responseModel any) erro
 		return response.Error
 	}
 
-	// Parse successful response
-	if responseModel != nil {
-		if err := json.Unmarshal(body, responseModel); err != nil {
+	// A 204 No Content is a success with nothing to decode, so the
+	// response model is left untouched
+	if resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	empty := len(bytes.TrimSpace(body)) == 0
+
+	// In strict mode any other response expecting a model must carry the
+	// data envelope, an empty body included
+	if responseModel != nil && h.strictEnvelope {
+		if empty {
+			return ErrMissingEnvelope
+		}
+		envelope := struct {
+			Data json.RawMessage `json:"data"`
+		}{}
+		if err := h.Codec().Unmarshal(body, &envelope); err != nil {
 			return fmt.Errorf("failed to parse response: %w", err)
 		}
+		if len(envelope.Data) == 0 || string(envelope.Data) == "null" {
+			return ErrMissingEnvelope
+		}
+	}
+
+	// An empty 2xx body is otherwise a success with nothing to decode
+	if empty || responseModel == nil {
+		return nil
+	}
+
+	// Parse successful response
+	if err := h.Codec().Unmarshal(body, responseModel); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
 	}
 
 	return nil
@@ -113,19 +209,45 @@ func (h *HTTPClient) HandleResponse(resp *http.Response, responseModel any) erro
 
 // DoRequest performs a complete HTTP request with error handling
 func (h *HTTPClient) DoRequest(ctx context.Context, method, url string, responseModel any, requestBody []byte, accessToken string) error {
+	return h.DoRequestWithHeader(ctx, method, url, responseModel, requestBody, accessToken, nil)
+}
+
+// DoRequestWithHeader performs a complete HTTP request with extra headers
+func (h *HTTPClient) DoRequestWithHeader(ctx context.Context, method, url string, responseModel any, requestBody []byte,
+	accessToken string, header http.Header) error {
 	req, err := h.PrepareRequest(ctx, method, url, requestBody)
 	if err != nil {
 		return err
 	}
 
+	for name, values := range header {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+
 	h.SetAuthorizationHeader(req, accessToken)
 
+	start := time.Now()
+	statusCode := 0
+
 	resp, err := h.ExecuteRequest(req)
-	if err != nil {
-		return err
+	if err == nil {
+		statusCode = resp.StatusCode
+		err = withBudgetContext(url, h.HandleResponse(resp, responseModel))
+	}
+
+	if h.observer != nil {
+		h.observer.ObserveRequest(RequestInfo{
+			Method:     method,
+			Path:       TemplatePath(url),
+			StatusCode: statusCode,
+			Duration:   time.Since(start),
+			Err:        err,
+		})
 	}
 
-	return h.HandleResponse(resp, responseModel)
+	return err
 }
 
 // DoRequestWithContext performs a complete HTTP request with context
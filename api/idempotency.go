@@ -0,0 +1,73 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// IdempotencyKeyHeader is the request header carrying the idempotency key
+// of a write. YNAB itself deduplicates transactions by import_id and
+// ignores the header; it is a defense in depth for cooperating proxies
+// and caches, and it is kept when a request is retried.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// WriteOption configures a single create request
+type WriteOption func(*WriteOptions)
+
+// WriteOptions holds the per-call options of a create request
+type WriteOptions struct {
+	// IdempotencyKey the value sent in the Idempotency-Key header, if any
+	IdempotencyKey string
+}
+
+// WithIdempotencyKey sets the Idempotency-Key header of a create request,
+// overriding any key derived from the payload
+func WithIdempotencyKey(key string) WriteOption {
+	return func(o *WriteOptions) {
+		o.IdempotencyKey = key
+	}
+}
+
+// NewWriteOptions applies opts in order
+func NewWriteOptions(opts ...WriteOption) WriteOptions {
+	var o WriteOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// Header returns the request headers for the options, nil when there are
+// none
+func (o WriteOptions) Header() http.Header {
+	if o.IdempotencyKey == "" {
+		return nil
+	}
+	return http.Header{IdempotencyKeyHeader: []string{o.IdempotencyKey}}
+}
+
+// IdempotencyKey derives a stable key from the parts identifying a write,
+// such as the import IDs of the transactions it creates
+func IdempotencyKey(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// HeaderWriter is implemented by clients able to send extra headers with
+// a POST request
+type HeaderWriter interface {
+	POSTWithHeader(url string, responseModel any, requestBody []byte, header http.Header) error
+}
+
+// POSTWithHeader sends a POST request through c with the given headers when
+// c implements HeaderWriter, and without them otherwise
+func POSTWithHeader(c ClientWriter, url string, responseModel any, requestBody []byte, header http.Header) error {
+	if w, ok := c.(HeaderWriter); ok && len(header) > 0 {
+		return w.POSTWithHeader(url, responseModel, requestBody, header)
+	}
+	return c.POST(url, responseModel, requestBody)
+}
@@ -0,0 +1,60 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coltoneshaw/ynab.go/api"
+)
+
+type postRecorder struct {
+	header http.Header
+	posts  int
+}
+
+func (r *postRecorder) POST(string, any, []byte) error {
+	r.posts++
+	return nil
+}
+
+func (r *postRecorder) PUT(string, any, []byte) error   { return nil }
+func (r *postRecorder) PATCH(string, any, []byte) error { return nil }
+func (r *postRecorder) DELETE(string, any) error        { return nil }
+
+type headerPostRecorder struct{ postRecorder }
+
+func (r *headerPostRecorder) POSTWithHeader(url string, responseModel any, requestBody []byte, header http.Header) error {
+	r.header = header
+	return nil
+}
+
+func TestWriteOptions_Header(t *testing.T) {
+	assert.Nil(t, api.NewWriteOptions().Header())
+
+	o := api.NewWriteOptions(api.WithIdempotencyKey("first"), nil, api.WithIdempotencyKey("second"))
+	assert.Equal(t, "second", o.IdempotencyKey)
+	assert.Equal(t, "second", o.Header().Get(api.IdempotencyKeyHeader))
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	key := api.IdempotencyKey("budget", "acc:YNAB:-1000:2024-01-15:1")
+	assert.Len(t, key, 32)
+	assert.Equal(t, key, api.IdempotencyKey("budget", "acc:YNAB:-1000:2024-01-15:1"))
+	assert.NotEqual(t, key, api.IdempotencyKey("budget", "acc:YNAB:-1000:2024-01-15:2"))
+	assert.NotEqual(t, api.IdempotencyKey("ab", "c"), api.IdempotencyKey("a", "bc"))
+}
+
+func TestPOSTWithHeader(t *testing.T) {
+	header := http.Header{api.IdempotencyKeyHeader: []string{"key"}}
+
+	withHeaders := &headerPostRecorder{}
+	assert.NoError(t, api.POSTWithHeader(withHeaders, "/foo", nil, nil, header))
+	assert.Equal(t, header, withHeaders.header)
+	assert.Zero(t, withHeaders.posts)
+
+	plain := &postRecorder{}
+	assert.NoError(t, api.POSTWithHeader(plain, "/foo", nil, nil, header))
+	assert.Equal(t, 1, plain.posts)
+}
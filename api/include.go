@@ -0,0 +1,48 @@
+package api
+
+// IncludeOption configures which hidden, closed or deleted entries an
+// operation includes, e.g. the ID to name maps of the services or the
+// totals of a month, which leave them out by default
+type IncludeOption func(*IncludeOptions)
+
+// IncludeOptions holds the include options of an operation
+type IncludeOptions struct {
+	// Hidden includes hidden entries, e.g. hidden categories
+	Hidden bool
+	// Closed includes closed accounts
+	Closed bool
+	// Deleted includes deleted entries
+	Deleted bool
+}
+
+// IncludeHidden makes an operation include hidden entries
+func IncludeHidden() IncludeOption {
+	return func(o *IncludeOptions) {
+		o.Hidden = true
+	}
+}
+
+// IncludeClosed makes an operation include closed accounts
+func IncludeClosed() IncludeOption {
+	return func(o *IncludeOptions) {
+		o.Closed = true
+	}
+}
+
+// IncludeDeleted makes an operation include deleted entries
+func IncludeDeleted() IncludeOption {
+	return func(o *IncludeOptions) {
+		o.Deleted = true
+	}
+}
+
+// NewIncludeOptions applies opts in order
+func NewIncludeOptions(opts ...IncludeOption) IncludeOptions {
+	var o IncludeOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
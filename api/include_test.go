@@ -0,0 +1,17 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coltoneshaw/ynab.go/api"
+)
+
+func TestNewIncludeOptions(t *testing.T) {
+	assert.Equal(t, api.IncludeOptions{}, api.NewIncludeOptions())
+	assert.Equal(t, api.IncludeOptions{Hidden: true}, api.NewIncludeOptions(api.IncludeHidden(), nil))
+	assert.Equal(t, api.IncludeOptions{Closed: true}, api.NewIncludeOptions(api.IncludeClosed()))
+	assert.Equal(t, api.IncludeOptions{Hidden: true, Deleted: true},
+		api.NewIncludeOptions(api.IncludeDeleted(), api.IncludeHidden()))
+}
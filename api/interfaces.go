@@ -32,7 +32,39 @@ type RateLimiter interface {
 	IsAtLimit() bool
 }
 
-// HTTPClientConfigurer defines the interface for HTTP client configuration
+// HTTPClientConfigurer defines the interface for HTTP client configuration.
+// WithHTTPClient and WithTimeout compose in call order: the last one called
+// decides the effective timeout.
 type HTTPClientConfigurer interface {
 	WithHTTPClient(client *http.Client) HTTPClientConfigurer
+	WithTimeout(timeout time.Duration) HTTPClientConfigurer
+}
+
+// FullReader is implemented by clients whose GET requests may be turned
+// into delta requests, such as by a cache of server knowledge, and that can
+// send one unchanged
+type FullReader interface {
+	GETFull(url string, responseModel any) error
+}
+
+// FullReads returns a client whose GET requests go through GETFull when c
+// implements FullReader, and c itself otherwise. Services use it for the
+// methods returning entities without their server knowledge, which must
+// always see every entity.
+func FullReads(c ClientReaderWriter) ClientReaderWriter {
+	if r, ok := c.(FullReader); ok {
+		return fullReads{ClientReaderWriter: c, r: r}
+	}
+	return c
+}
+
+// fullReads sends the GET requests of a client through its GETFull
+type fullReads struct {
+	ClientReaderWriter
+	r FullReader
+}
+
+// GET sends the request through GETFull
+func (f fullReads) GET(url string, responseModel any) error {
+	return f.r.GETFull(url, responseModel)
 }
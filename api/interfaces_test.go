@@ -0,0 +1,39 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coltoneshaw/ynab.go/api"
+)
+
+type readRecorder struct {
+	postRecorder
+	gets []string
+}
+
+func (r *readRecorder) GET(url string, _ any) error {
+	r.gets = append(r.gets, "GET "+url)
+	return nil
+}
+
+type fullReadRecorder struct{ readRecorder }
+
+func (r *fullReadRecorder) GETFull(url string, _ any) error {
+	r.gets = append(r.gets, "GETFull "+url)
+	return nil
+}
+
+func TestFullReads(t *testing.T) {
+	full := &fullReadRecorder{}
+	assert.NoError(t, api.FullReads(full).GET("/foo", nil))
+	assert.NoError(t, api.FullReads(full).POST("/foo", nil, nil))
+	assert.Equal(t, []string{"GETFull /foo"}, full.gets)
+	assert.Equal(t, 1, full.posts)
+
+	plain := &readRecorder{}
+	assert.Same(t, plain, api.FullReads(plain))
+	assert.NoError(t, api.FullReads(plain).GET("/foo", nil))
+	assert.Equal(t, []string{"GET /foo"}, plain.gets)
+}
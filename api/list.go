@@ -0,0 +1,9 @@
+package api
+
+// ListResult represents a page of entities returned by a list endpoint
+// along with the server knowledge to pass as last_knowledge_of_server on
+// the next delta request
+type ListResult[T any] struct {
+	Items           []T
+	ServerKnowledge uint64
+}
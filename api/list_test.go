@@ -0,0 +1,52 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/jarcoal/httpmock.v1"
+
+	"github.com/coltoneshaw/ynab.go"
+	"github.com/coltoneshaw/ynab.go/api"
+	"github.com/coltoneshaw/ynab.go/api/account"
+	"github.com/coltoneshaw/ynab.go/api/transaction"
+)
+
+// itemIDs is a generic helper written once for any list result
+func itemIDs[T any](r *api.ListResult[T], id func(T) string) ([]string, uint64) {
+	ids := make([]string, 0, len(r.Items))
+	for _, item := range r.Items {
+		ids = append(ids, id(item))
+	}
+	return ids, r.ServerKnowledge
+}
+
+func TestListResult(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets/aa248caa/transactions",
+		httpmock.NewStringResponder(200, `{"data":{"transactions":[{"id":"tx-1","date":"2018-03-10","amount":-43950},{"id":"tx-2","date":"2018-03-11","amount":-1000}],"server_knowledge":12}}`))
+	httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets/aa248caa/accounts",
+		httpmock.NewStringResponder(200, `{"data":{"accounts":[{"id":"acc-1","name":"Checking","type":"checking"}],"server_knowledge":34}}`))
+
+	client := ynab.NewClient("")
+
+	transactions, err := client.Transaction().GetTransactions("aa248caa", nil)
+	require.NoError(t, err)
+	ids, serverKnowledge := itemIDs(transactions.List(), func(tx *transaction.Transaction) string { return tx.ID })
+	assert.Equal(t, []string{"tx-1", "tx-2"}, ids)
+	assert.Equal(t, uint64(12), serverKnowledge)
+
+	accounts, err := client.Account().GetAccounts("aa248caa", nil)
+	require.NoError(t, err)
+	ids, serverKnowledge = itemIDs(accounts.List(), func(a *account.Account) string { return a.ID })
+	assert.Equal(t, []string{"acc-1"}, ids)
+	assert.Equal(t, uint64(34), serverKnowledge)
+
+	// The named snapshots keep their original fields
+	assert.Len(t, transactions.Transactions, 2)
+	assert.Len(t, accounts.Accounts, 1)
+}
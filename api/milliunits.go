@@ -0,0 +1,55 @@
+package api
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// Milliunits is an amount in milliunits format, e.g. -43950 for -43.95.
+// YNAB encodes amounts as JSON integers, but a float such as -43950.0 is
+// decoded too and rounded to the nearest integer milliunit, halves away
+// from zero. Other values, including numbers in strings, fail as they
+// would for an int64.
+type Milliunits int64
+
+// UnmarshalJSON decodes an integer or float JSON number. Being a method of
+// the field type, it leaves the rest of the enclosing struct to the codec.
+func (m *Milliunits) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*m = Milliunits(i)
+		return nil
+	}
+
+	if len(data) > 0 && data[0] != '"' {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			if f = math.Round(f); f >= math.MinInt64 && f < math.MaxInt64 {
+				*m = Milliunits(f)
+				return nil
+			}
+		}
+	}
+	return &json.UnmarshalTypeError{Value: jsonKind(data), Type: reflect.TypeFor[int64]()}
+}
+
+// jsonKind describes the JSON value in data as json.UnmarshalTypeError does
+func jsonKind(data []byte) string {
+	if len(data) > 0 {
+		switch data[0] {
+		case '"':
+			return "string"
+		case 't', 'f':
+			return "bool"
+		case '{':
+			return "object"
+		case '[':
+			return "array"
+		}
+	}
+	return "number " + string(data)
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMilliunits_UnmarshalJSON(t *testing.T) {
+	tests := map[string]Milliunits{
+		`-43950`:   -43950,
+		`-43950.0`: -43950,
+		`-4.395e4`: -43950,
+		`-43950.4`: -43950,
+		`-43950.5`: -43951,
+		`43950.5`:  43951,
+		`0.49`:     0,
+	}
+	for amount, expected := range tests {
+		var m Milliunits
+		require.NoError(t, json.Unmarshal([]byte(amount), &m), amount)
+		assert.Equal(t, expected, m, amount)
+	}
+
+	// A null amount leaves the value alone, other values fail
+	m := Milliunits(1000)
+	require.NoError(t, json.Unmarshal([]byte(`null`), &m))
+	assert.Equal(t, Milliunits(1000), m)
+	for _, amount := range []string{`"-43950"`, `true`, `1e30`} {
+		var typeErr *json.UnmarshalTypeError
+		assert.ErrorAs(t, json.Unmarshal([]byte(amount), &m), &typeErr, amount)
+	}
+
+	// Only the amount field is decoded by Milliunits
+	var s struct {
+		Amount Milliunits `json:"amount"`
+		Memo   string     `json:"memo"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":-43950.0,"memo":"lunch"}`), &s))
+	assert.Equal(t, Milliunits(-43950), s.Amount)
+	assert.Equal(t, "lunch", s.Memo)
+}
@@ -29,6 +29,78 @@ type Month struct {
 	Activity *int64 `json:"activity"`
 }
 
+// CategoryByID returns the category of the month with the given ID
+func (m *Month) CategoryByID(id string) (*category.Category, bool) {
+	for _, c := range m.Categories {
+		if c != nil && c.ID == id {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// CategoriesByGroup returns the categories of the month keyed by their
+// category group ID, in the order the API returned them
+func (m *Month) CategoriesByGroup() map[string][]*category.Category {
+	groups := make(map[string][]*category.Category)
+	for _, c := range m.Categories {
+		if c == nil {
+			continue
+		}
+		groups[c.CategoryGroupID] = append(groups[c.CategoryGroupID], c)
+	}
+	return groups
+}
+
+// GroupTotal holds the amounts of the categories of a category group
+// summed over a month, in milliunits format
+type GroupTotal struct {
+	Budgeted int64
+	Activity int64
+	Balance  int64
+}
+
+// GroupTotals returns the budgeted, activity and balance amounts of the
+// categories of the month summed by category group ID. Hidden and deleted
+// categories are skipped unless api.IncludeHidden or api.IncludeDeleted is
+// given; a group with no category left is not reported.
+func (m *Month) GroupTotals(opts ...api.IncludeOption) map[string]GroupTotal {
+	o := api.NewIncludeOptions(opts...)
+
+	totals := make(map[string]GroupTotal)
+	for _, c := range m.Categories {
+		if c == nil || (c.Hidden && !o.Hidden) || (c.Deleted && !o.Deleted) {
+			continue
+		}
+		total := totals[c.CategoryGroupID]
+		total.Budgeted += c.Budgeted
+		total.Activity += c.Activity
+		total.Balance += c.Balance
+		totals[c.CategoryGroupID] = total
+	}
+	return totals
+}
+
+// ToBeBudgetedAmount returns the To be Budgeted amount of the month in
+// milliunits, zero when the API did not provide it
+func (m *Month) ToBeBudgetedAmount() int64 {
+	return valueOrZero(m.ToBeBudgeted)
+}
+
+// IncomeAmount returns the income of the month in milliunits, zero when
+// the API did not provide it
+func (m *Month) IncomeAmount() int64 {
+	return valueOrZero(m.Income)
+}
+
+// valueOrZero dereferences an optional amount
+func valueOrZero(v *int64) int64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
 // Summary represents the summary of a month for a budget
 // Each budget contains one or more months, which is where To be Budgeted,
 // Age of Money and Category (budgeted / activity / balances)
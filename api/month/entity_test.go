@@ -0,0 +1,107 @@
+package month_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coltoneshaw/ynab.go/api"
+	"github.com/coltoneshaw/ynab.go/api/month"
+)
+
+const monthDetailJSON = `{
+  "month": "2024-01-01",
+  "note": null,
+  "income": 500000,
+  "budgeted": 420000,
+  "activity": -310000,
+  "to_be_budgeted": 80000,
+  "age_of_money": 42,
+  "deleted": false,
+  "categories": [
+    {"id": "cat-rent", "category_group_id": "group-bills", "name": "Rent", "hidden": false, "budgeted": 300000, "activity": -300000, "balance": 0, "deleted": false},
+    {"id": "cat-groceries", "category_group_id": "group-everyday", "name": "Groceries", "hidden": false, "budgeted": 100000, "activity": -8000, "balance": 92000, "deleted": false},
+    {"id": "cat-power", "category_group_id": "group-bills", "name": "Electricity", "hidden": false, "budgeted": 20000, "activity": -2000, "balance": 18000, "deleted": false}
+  ]
+}`
+
+func TestMonth_CategoryByID(t *testing.T) {
+	var m month.Month
+	require.NoError(t, json.Unmarshal([]byte(monthDetailJSON), &m))
+
+	c, ok := m.CategoryByID("cat-groceries")
+	require.True(t, ok)
+	assert.Equal(t, "Groceries", c.Name)
+	assert.Equal(t, int64(92000), c.Balance)
+
+	c, ok = m.CategoryByID("cat-missing")
+	assert.False(t, ok)
+	assert.Nil(t, c)
+}
+
+func TestMonth_CategoriesByGroup(t *testing.T) {
+	var m month.Month
+	require.NoError(t, json.Unmarshal([]byte(monthDetailJSON), &m))
+
+	groups := m.CategoriesByGroup()
+	assert.Len(t, groups, 2)
+
+	if assert.Len(t, groups["group-bills"], 2) {
+		assert.Equal(t, "cat-rent", groups["group-bills"][0].ID)
+		assert.Equal(t, "cat-power", groups["group-bills"][1].ID)
+	}
+	if assert.Len(t, groups["group-everyday"], 1) {
+		assert.Equal(t, "cat-groceries", groups["group-everyday"][0].ID)
+	}
+
+	assert.Empty(t, (&month.Month{}).CategoriesByGroup())
+}
+
+func TestMonth_Amounts(t *testing.T) {
+	var m month.Month
+	require.NoError(t, json.Unmarshal([]byte(monthDetailJSON), &m))
+
+	assert.Equal(t, int64(80000), m.ToBeBudgetedAmount())
+	assert.Equal(t, int64(500000), m.IncomeAmount())
+
+	empty := month.Month{}
+	assert.Zero(t, empty.ToBeBudgetedAmount())
+	assert.Zero(t, empty.IncomeAmount())
+}
+
+func TestMonth_GroupTotals(t *testing.T) {
+	var m month.Month
+	require.NoError(t, json.Unmarshal([]byte(`{
+	  "month": "2024-01-01",
+	  "categories": [
+	    {"id": "cat-rent", "category_group_id": "group-bills", "budgeted": 300000, "activity": -300000, "balance": 0},
+	    {"id": "cat-power", "category_group_id": "group-bills", "budgeted": 20000, "activity": -2000, "balance": 18000},
+	    {"id": "cat-old-phone", "category_group_id": "group-bills", "hidden": true, "budgeted": 5000, "activity": 0, "balance": 5000},
+	    {"id": "cat-groceries", "category_group_id": "group-everyday", "budgeted": 100000, "activity": -8000, "balance": 92000},
+	    {"id": "cat-dining", "category_group_id": "group-everyday", "budgeted": 30000, "activity": -35000, "balance": -5000},
+	    {"id": "cat-gone", "category_group_id": "group-everyday", "deleted": true, "budgeted": 1000, "activity": 0, "balance": 1000},
+	    {"id": "cat-archived", "category_group_id": "group-archive", "hidden": true, "budgeted": 7000, "activity": 0, "balance": 7000}
+	  ]
+	}`), &m))
+
+	assert.Equal(t, map[string]month.GroupTotal{
+		"group-bills":    {Budgeted: 320000, Activity: -302000, Balance: 18000},
+		"group-everyday": {Budgeted: 130000, Activity: -43000, Balance: 87000},
+	}, m.GroupTotals())
+
+	assert.Equal(t, map[string]month.GroupTotal{
+		"group-bills":    {Budgeted: 325000, Activity: -302000, Balance: 23000},
+		"group-everyday": {Budgeted: 130000, Activity: -43000, Balance: 87000},
+		"group-archive":  {Budgeted: 7000, Balance: 7000},
+	}, m.GroupTotals(api.IncludeHidden()))
+
+	assert.Equal(t, map[string]month.GroupTotal{
+		"group-bills":    {Budgeted: 325000, Activity: -302000, Balance: 23000},
+		"group-everyday": {Budgeted: 131000, Activity: -43000, Balance: 88000},
+		"group-archive":  {Budgeted: 7000, Balance: 7000},
+	}, m.GroupTotals(api.IncludeHidden(), api.IncludeDeleted()))
+
+	assert.Empty(t, (&month.Month{}).GroupTotals())
+}
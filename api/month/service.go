@@ -1,19 +1,29 @@
 package month
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/coltoneshaw/ynab.go/api"
+	"github.com/coltoneshaw/ynab.go/api/category"
 )
 
+// ErrPartialMove is returned by MoveBudgeted when the destination category
+// could not be updated and the source category could not be restored
+var ErrPartialMove = errors.New("month: budgeted amount only partially moved")
+
+// ErrSameCategory is returned by MoveBudgeted when asked to move money from
+// a category to itself
+var ErrSameCategory = errors.New("month: cannot move budgeted amount to the same category")
+
 // NewService facilitates the creation of a new month service instance
-func NewService(c api.ClientReader) *Service {
+func NewService(c api.ClientReaderWriter) *Service {
 	return &Service{c}
 }
 
 // Service wraps YNAB month API endpoints
 type Service struct {
-	c api.ClientReader
+	c api.ClientReaderWriter
 }
 
 // GetMonths fetches the list of months from a budget
@@ -40,7 +50,8 @@ func (s *Service) GetMonths(budgetID string, f *api.Filter) (*SearchResultSnapsh
 	}, nil
 }
 
-// GetMonth fetches a specific month from a budget
+// GetMonth fetches a specific month from a budget. Any day within the
+// month may be given; it is normalized to the first day of the month.
 // https://api.youneedabudget.com/v1#/Months/getBudgetMonth
 func (s *Service) GetMonth(budgetID string, month api.Date) (*Month, error) {
 	resModel := struct {
@@ -49,10 +60,82 @@ func (s *Service) GetMonth(budgetID string, month api.Date) (*Month, error) {
 		} `json:"data"`
 	}{}
 
-	url := fmt.Sprintf("/budgets/%s/months/%s", budgetID,
-		api.DateFormat(month))
+	monthParam, err := api.MonthParam(api.DateFormat(month))
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("/budgets/%s/months/%s", budgetID, monthParam)
 	if err := s.c.GET(url, &resModel); err != nil {
 		return nil, err
 	}
 	return resModel.Data.Month, nil
 }
+
+// MoveBudgeted moves amount milliunits of budgeted money from one category
+// to another for the given month, which accepts the same formats as
+// api.MonthParam. Both categories are fetched first so the new budgeted
+// amounts are computed from their current values. If updating the
+// destination fails, the source is restored; should that fail too, the
+// returned error wraps ErrPartialMove along with both failures. Moving to
+// the same category fails with ErrSameCategory without any request.
+// https://api.youneedabudget.com/v1#/Categories/updateMonthCategory
+func (s *Service) MoveBudgeted(budgetID, month, fromCategoryID, toCategoryID string, amount int64) error {
+	if fromCategoryID == toCategoryID {
+		return ErrSameCategory
+	}
+
+	monthParam, err := api.MonthParam(month)
+	if err != nil {
+		return err
+	}
+
+	categories := category.NewService(s.c)
+	get := func(categoryID string) (*category.Category, error) {
+		if monthParam == api.CurrentMonth {
+			return categories.GetCategoryForCurrentMonth(budgetID, categoryID)
+		}
+		date, err := api.DateFromString(monthParam)
+		if err != nil {
+			return nil, err
+		}
+		return categories.GetCategoryForMonth(budgetID, categoryID, date)
+	}
+	update := func(categoryID string, budgeted int64) error {
+		p := category.PayloadMonthCategory{Budgeted: budgeted}
+		if monthParam == api.CurrentMonth {
+			_, err := categories.UpdateCategoryForCurrentMonth(budgetID, categoryID, p)
+			return err
+		}
+		date, err := api.DateFromString(monthParam)
+		if err != nil {
+			return err
+		}
+		_, err = categories.UpdateCategoryForMonth(budgetID, categoryID, date, p)
+		return err
+	}
+
+	from, err := get(fromCategoryID)
+	if err != nil {
+		return err
+	}
+	to, err := get(toCategoryID)
+	if err != nil {
+		return err
+	}
+
+	if err := update(fromCategoryID, from.Budgeted-amount); err != nil {
+		return err
+	}
+	if err := update(toCategoryID, to.Budgeted+amount); err != nil {
+		if revertErr := update(fromCategoryID, from.Budgeted); revertErr != nil {
+			return errors.Join(
+				fmt.Errorf("%w: updating %q: %w", ErrPartialMove, toCategoryID, err),
+				fmt.Errorf("restoring %q: %w", fromCategoryID, revertErr),
+			)
+		}
+		return err
+	}
+
+	return nil
+}
@@ -1,6 +1,8 @@
 package month_test
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"testing"
 
@@ -9,6 +11,7 @@ import (
 
 	"github.com/coltoneshaw/ynab.go"
 	"github.com/coltoneshaw/ynab.go/api"
+	"github.com/coltoneshaw/ynab.go/api/month"
 )
 
 func TestService_GetMonths(t *testing.T) {
@@ -111,3 +114,155 @@ func TestService_GetMonth(t *testing.T) {
 	assert.Equal(t, &expectedActivity, m.Activity)
 	assert.Nil(t, m.Note)
 }
+
+func TestService_GetMonth_NormalizesDay(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	url := "https://api.youneedabudget.com/v1/budgets/aa248caa-eed7-4575-a990-717386438d2c/months/2017-10-01"
+	httpmock.RegisterResponder(http.MethodGet, url,
+		func(req *http.Request) (*http.Response, error) {
+			res := httpmock.NewStringResponse(200, `{
+  "data": {
+    "month": {
+      "month": "2017-10-01",
+      "categories": [],
+      "deleted": false
+    }
+  }
+}
+		`)
+			return res, nil
+		},
+	)
+
+	date, err := api.DateFromString("2017-10-17")
+	assert.NoError(t, err)
+
+	client := ynab.NewClient("")
+	m, err := client.Month().GetMonth("aa248caa-eed7-4575-a990-717386438d2c", date)
+	assert.NoError(t, err)
+	assert.Equal(t, "2017-10-01", api.DateFormat(m.Month))
+}
+
+func TestService_MoveBudgeted(t *testing.T) {
+	const base = "https://api.youneedabudget.com/v1/budgets/aa248caa/months/2024-03-01/categories/"
+
+	registerCategory := func(id string, budgeted int64) {
+		httpmock.RegisterResponder(http.MethodGet, base+id,
+			httpmock.NewStringResponder(http.StatusOK,
+				fmt.Sprintf(`{"data":{"category":{"id":%q,"budgeted":%d}}}`, id, budgeted)))
+	}
+
+	t.Run("success", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		registerCategory("cat-dining", 200000)
+		registerCategory("cat-groceries", 50000)
+
+		updates := map[string]int64{}
+		for _, id := range []string{"cat-dining", "cat-groceries"} {
+			id := id
+			httpmock.RegisterResponder(http.MethodPatch, base+id,
+				func(req *http.Request) (*http.Response, error) {
+					payload := struct {
+						Category struct {
+							Budgeted int64 `json:"budgeted"`
+						} `json:"category"`
+					}{}
+					assert.NoError(t, json.NewDecoder(req.Body).Decode(&payload))
+					updates[id] = payload.Category.Budgeted
+					return httpmock.NewStringResponse(http.StatusOK,
+						fmt.Sprintf(`{"data":{"category":{"id":%q,"budgeted":%d}}}`, id, payload.Category.Budgeted)), nil
+				},
+			)
+		}
+
+		client := ynab.NewClient("")
+		err := client.Month().MoveBudgeted("aa248caa", "2024-03-15", "cat-dining", "cat-groceries", 75000)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int64{
+			"cat-dining":    125000,
+			"cat-groceries": 125000,
+		}, updates)
+	})
+
+	t.Run("same category", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		registerCategory("cat-dining", 200000)
+
+		client := ynab.NewClient("")
+		err := client.Month().MoveBudgeted("aa248caa", "2024-03-15", "cat-dining", "cat-dining", 75000)
+		assert.ErrorIs(t, err, month.ErrSameCategory)
+		assert.Zero(t, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("destination failure restores source", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		registerCategory("cat-dining", 200000)
+		registerCategory("cat-groceries", 50000)
+
+		var sourceUpdates []int64
+		httpmock.RegisterResponder(http.MethodPatch, base+"cat-dining",
+			func(req *http.Request) (*http.Response, error) {
+				payload := struct {
+					Category struct {
+						Budgeted int64 `json:"budgeted"`
+					} `json:"category"`
+				}{}
+				assert.NoError(t, json.NewDecoder(req.Body).Decode(&payload))
+				sourceUpdates = append(sourceUpdates, payload.Category.Budgeted)
+				return httpmock.NewStringResponse(http.StatusOK, `{"data":{"category":{"id":"cat-dining"}}}`), nil
+			},
+		)
+		httpmock.RegisterResponder(http.MethodPatch, base+"cat-groceries",
+			httpmock.NewStringResponder(http.StatusInternalServerError,
+				`{"error":{"id":"500","name":"internal_server_error","detail":"Internal Server Error"}}`))
+
+		client := ynab.NewClient("")
+		err := client.Month().MoveBudgeted("aa248caa", "2024-03", "cat-dining", "cat-groceries", 75000)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, month.ErrPartialMove)
+		assert.Equal(t, []int64{125000, 200000}, sourceUpdates)
+	})
+
+	t.Run("partial failure", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		registerCategory("cat-dining", 200000)
+		registerCategory("cat-groceries", 50000)
+
+		sourceUpdates := 0
+		httpmock.RegisterResponder(http.MethodPatch, base+"cat-dining",
+			func(req *http.Request) (*http.Response, error) {
+				sourceUpdates++
+				if sourceUpdates > 1 {
+					return httpmock.NewStringResponse(http.StatusServiceUnavailable,
+						`{"error":{"id":"503","name":"service_unavailable","detail":"Service Unavailable"}}`), nil
+				}
+				return httpmock.NewStringResponse(http.StatusOK, `{"data":{"category":{"id":"cat-dining"}}}`), nil
+			},
+		)
+		httpmock.RegisterResponder(http.MethodPatch, base+"cat-groceries",
+			httpmock.NewStringResponder(http.StatusInternalServerError,
+				`{"error":{"id":"500","name":"internal_server_error","detail":"Internal Server Error"}}`))
+
+		client := ynab.NewClient("")
+		err := client.Month().MoveBudgeted("aa248caa", "2024-03-01", "cat-dining", "cat-groceries", 75000)
+		assert.ErrorIs(t, err, month.ErrPartialMove)
+		assert.Contains(t, err.Error(), "cat-groceries")
+		assert.Contains(t, err.Error(), "restoring \"cat-dining\"")
+	})
+
+	t.Run("invalid month", func(t *testing.T) {
+		client := ynab.NewClient("")
+		err := client.Month().MoveBudgeted("aa248caa", "March", "cat-dining", "cat-groceries", 75000)
+		assert.Error(t, err)
+	})
+}
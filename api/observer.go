@@ -0,0 +1,75 @@
+package api
+
+import (
+	"strings"
+	"time"
+)
+
+// RequestInfo describes a completed YNAB API request
+type RequestInfo struct {
+	// Method is the HTTP method of the request
+	Method string
+	// Path is the request path with IDs replaced by placeholders such as
+	// {budget_id}, suitable as a low-cardinality metric label
+	Path string
+	// StatusCode is the HTTP status code, zero if no response was received
+	StatusCode int
+	// Duration is the time taken to send the request and read the response
+	Duration time.Duration
+	// Err is the error returned for the request, if any
+	Err error
+}
+
+// Observer receives information about every request sent to the YNAB API.
+// ObserveRequest is called synchronously after each request completes, so
+// implementations should return quickly.
+type Observer interface {
+	ObserveRequest(info RequestInfo)
+}
+
+// ObserverFunc adapts an ordinary function to the Observer interface
+type ObserverFunc func(info RequestInfo)
+
+// ObserveRequest calls f(info)
+func (f ObserverFunc) ObserveRequest(info RequestInfo) {
+	f(info)
+}
+
+// pathPlaceholders maps a collection segment to the placeholder used for
+// the ID segment that follows it
+var pathPlaceholders = map[string]string{
+	"budgets":                "{budget_id}",
+	"accounts":               "{account_id}",
+	"categories":             "{category_id}",
+	"payees":                 "{payee_id}",
+	"payee_locations":        "{payee_location_id}",
+	"months":                 "{month}",
+	"transactions":           "{transaction_id}",
+	"scheduled_transactions": "{scheduled_transaction_id}",
+}
+
+// pathLiterals are fixed segments that can follow a collection segment
+// and must not be mistaken for an ID
+var pathLiterals = map[string]bool{
+	"bulk":   true,
+	"import": true,
+}
+
+// TemplatePath returns the request path with its query string removed and
+// every ID segment replaced by a placeholder, e.g.
+// "/budgets/{budget_id}/accounts/{account_id}"
+func TemplatePath(path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+
+	segments := strings.Split(path, "/")
+	for i := 1; i < len(segments); i++ {
+		placeholder, ok := pathPlaceholders[segments[i-1]]
+		if !ok || segments[i] == "" || pathLiterals[segments[i]] {
+			continue
+		}
+		segments[i] = placeholder
+	}
+	return strings.Join(segments, "/")
+}
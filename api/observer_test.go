@@ -0,0 +1,37 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coltoneshaw/ynab.go/api"
+)
+
+func TestTemplatePath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/user", "/user"},
+		{"/budgets", "/budgets"},
+		{"/budgets/last-used", "/budgets/{budget_id}"},
+		{"/budgets/aa248caa/settings", "/budgets/{budget_id}/settings"},
+		{"/budgets/aa248caa/accounts?last_knowledge_of_server=10", "/budgets/{budget_id}/accounts"},
+		{"/budgets/aa248caa/accounts/e5a9b1c7", "/budgets/{budget_id}/accounts/{account_id}"},
+		{"/budgets/aa248caa/accounts/e5a9b1c7/transactions", "/budgets/{budget_id}/accounts/{account_id}/transactions"},
+		{"/budgets/aa248caa/months/2024-01-01/categories/13419c12", "/budgets/{budget_id}/months/{month}/categories/{category_id}"},
+		{"/budgets/aa248caa/payees/793e2c07/transactions", "/budgets/{budget_id}/payees/{payee_id}/transactions"},
+		{"/budgets/aa248caa/payee_locations/1c7a7b4c", "/budgets/{budget_id}/payee_locations/{payee_location_id}"},
+		{"/budgets/aa248caa/transactions/import", "/budgets/{budget_id}/transactions/import"},
+		{"/budgets/aa248caa/transactions/bulk", "/budgets/{budget_id}/transactions/bulk"},
+		{"/budgets/aa248caa/transactions/c2d3a9f0", "/budgets/{budget_id}/transactions/{transaction_id}"},
+		{"/budgets/aa248caa/scheduled_transactions/b5e1f3d2", "/budgets/{budget_id}/scheduled_transactions/{scheduled_transaction_id}"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			assert.Equal(t, test.expected, api.TemplatePath(test.path))
+		})
+	}
+}
@@ -1,10 +1,10 @@
 package payee
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"github.com/coltoneshaw/ynab.go/api"
+	"github.com/coltoneshaw/ynab.go/api/transaction"
 )
 
 // NewService facilitates the creation of a new payee service instance
@@ -41,6 +41,27 @@ func (s *Service) GetPayees(budgetID string, f *api.Filter) (*SearchResultSnapsh
 	}, nil
 }
 
+// GetPayeeNameMap returns the names of the payees of a budget keyed by
+// payee ID. Deleted payees are left out unless api.IncludeDeleted is given;
+// payees are never hidden.
+// https://api.youneedabudget.com/v1#/Payees/getPayees
+func (s *Service) GetPayeeNameMap(budgetID string, opts ...api.IncludeOption) (map[string]string, error) {
+	snapshot, err := (&Service{c: api.FullReads(s.c)}).GetPayees(budgetID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	o := api.NewIncludeOptions(opts...)
+	names := make(map[string]string, len(snapshot.Payees))
+	for _, p := range snapshot.Payees {
+		if p == nil || (p.Deleted && !o.Deleted) {
+			continue
+		}
+		names[p.ID] = p.Name
+	}
+	return names, nil
+}
+
 // GetPayee fetches a specific payee from a budget
 // https://api.youneedabudget.com/v1#/Payees/getPayeeById
 func (s *Service) GetPayee(budgetID, payeeID string) (*Payee, error) {
@@ -114,7 +135,7 @@ func (s *Service) UpdatePayee(budgetID, payeeID string, p PayloadPayee) (*Payee,
 		&p,
 	}
 
-	buf, err := json.Marshal(&payload)
+	buf, err := api.Marshal(s.c, &payload)
 	if err != nil {
 		return nil, err
 	}
@@ -132,3 +153,111 @@ func (s *Service) UpdatePayee(budgetID, payeeID string, p PayloadPayee) (*Payee,
 	}
 	return resModel.Data.Payee, nil
 }
+
+// payloadTransactionPayee is the minimal payload reassigning the payee of
+// an existing transaction
+type payloadTransactionPayee struct {
+	ID      string `json:"id"`
+	PayeeID string `json:"payee_id"`
+}
+
+// MergePayees reassigns the transactions and scheduled transactions of the
+// payees in mergePayeeIDs to keepPayeeID, leaving the merged payees unused.
+// Transactions are updated in a single request and the scheduled ones one
+// by one; the IDs of the latter are reported in the ScheduledTransactionIDs
+// of the returned summary. The API cannot change the subtransactions of an
+// existing split, so splits whose subtransactions reference a merged payee,
+// and split scheduled transactions, are left alone and reported in
+// SkippedSplitIDs.
+// https://api.youneedabudget.com/v1#/Transactions/updateTransactions
+func (s *Service) MergePayees(budgetID, keepPayeeID string, mergePayeeIDs []string) (*transaction.OperationSummary, error) {
+	transactions := transaction.NewService(s.c)
+
+	merged := make(map[string]bool, len(mergePayeeIDs))
+	for _, id := range mergePayeeIDs {
+		if id != keepPayeeID {
+			merged[id] = true
+		}
+	}
+
+	var (
+		updates []payloadTransactionPayee
+		skipped []string
+		seen    = make(map[string]bool)
+	)
+	for _, id := range mergePayeeIDs {
+		if !merged[id] || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		hybrids, err := transactions.GetTransactionsByPayee(budgetID, id, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range hybrids {
+			if t.Deleted {
+				continue
+			}
+			if t.Type == transaction.TypeSubTransaction {
+				if t.ParentTransactionID != nil && !seen[*t.ParentTransactionID] {
+					seen[*t.ParentTransactionID] = true
+					skipped = append(skipped, *t.ParentTransactionID)
+				}
+				continue
+			}
+			updates = append(updates, payloadTransactionPayee{ID: t.ID, PayeeID: keepPayeeID})
+		}
+	}
+
+	summary := &transaction.OperationSummary{}
+	if len(updates) > 0 {
+		payload := struct {
+			Transactions []payloadTransactionPayee `json:"transactions"`
+		}{
+			updates,
+		}
+
+		buf, err := api.Marshal(s.c, &payload)
+		if err != nil {
+			return nil, err
+		}
+
+		resModel := struct {
+			Data *transaction.OperationSummary `json:"data"`
+		}{}
+
+		url := fmt.Sprintf("/budgets/%s/transactions", budgetID)
+		if err := s.c.PATCH(url, &resModel, buf); err != nil {
+			return nil, err
+		}
+		if resModel.Data != nil {
+			summary = resModel.Data
+		}
+	}
+
+	scheduled, err := transaction.NewService(api.FullReads(s.c)).GetScheduledTransactions(budgetID, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, st := range scheduled.ScheduledTransactions {
+		if st.Deleted || st.PayeeID == nil || !merged[*st.PayeeID] {
+			continue
+		}
+		if len(st.SubTransactions) > 0 {
+			skipped = append(skipped, st.ID)
+			continue
+		}
+
+		p := st.ToPayload()
+		p.PayeeID = &keepPayeeID
+		p.PayeeName = nil
+		if _, err := transactions.UpdateScheduledTransaction(budgetID, st.ID, p); err != nil {
+			return nil, err
+		}
+		summary.ScheduledTransactionIDs = append(summary.ScheduledTransactionIDs, st.ID)
+	}
+
+	summary.SkippedSplitIDs = skipped
+	return summary, nil
+}
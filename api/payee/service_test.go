@@ -1,6 +1,7 @@
 package payee_test
 
 import (
+	"io"
 	"net/http"
 	"strconv"
 	"testing"
@@ -8,6 +9,7 @@ import (
 	"github.com/coltoneshaw/ynab.go/api"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/jarcoal/httpmock.v1"
 
 	"github.com/coltoneshaw/ynab.go"
@@ -285,3 +287,87 @@ func TestService_UpdatePayee(t *testing.T) {
 	}
 	assert.Equal(t, expected, p)
 }
+
+func TestService_MergePayees(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	base := "https://api.youneedabudget.com/v1/budgets/aa248caa"
+	httpmock.RegisterResponder(http.MethodGet, base+"/payees/payee-amazon/transactions",
+		httpmock.NewStringResponder(200, `{"data":{"transactions":[
+  {"id": "tx-1", "date": "2024-01-02", "amount": -1000, "account_id": "acc-1", "payee_id": "payee-amazon", "type": "transaction", "deleted": false},
+  {"id": "tx-2", "date": "2024-01-03", "amount": -2000, "account_id": "acc-1", "payee_id": "payee-amazon", "type": "transaction", "deleted": false},
+  {"id": "sub-1", "date": "2024-01-04", "amount": -500, "account_id": "acc-1", "payee_id": "payee-amazon", "type": "subtransaction", "parent_transaction_id": "tx-split", "deleted": false}
+]}}`))
+	httpmock.RegisterResponder(http.MethodGet, base+"/payees/payee-amazon-com/transactions",
+		httpmock.NewStringResponder(200, `{"data":{"transactions":[
+  {"id": "tx-3", "date": "2024-01-05", "amount": -3000, "account_id": "acc-1", "payee_id": "payee-amazon-com", "type": "transaction", "deleted": false},
+  {"id": "tx-4", "date": "2024-01-06", "amount": -4000, "account_id": "acc-1", "payee_id": "payee-amazon-com", "type": "transaction", "deleted": true}
+]}}`))
+
+	var patched string
+	httpmock.RegisterResponder(http.MethodPatch, base+"/transactions",
+		func(req *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			patched = string(body)
+			return httpmock.NewStringResponse(200, `{"data":{"transaction_ids":["tx-1","tx-2","tx-3"],"server_knowledge":20}}`), nil
+		},
+	)
+
+	httpmock.RegisterResponder(http.MethodGet, base+"/scheduled_transactions",
+		httpmock.NewStringResponder(200, `{"data":{"scheduled_transactions":[
+  {"id": "st-1", "date_first": "2024-01-01", "date_next": "2024-02-01", "frequency": "monthly", "amount": -1500, "account_id": "acc-1", "payee_id": "payee-amazon-com", "category_id": "cat-1", "deleted": false},
+  {"id": "st-2", "date_first": "2024-01-01", "date_next": "2024-02-01", "frequency": "monthly", "amount": -900, "account_id": "acc-1", "payee_id": "payee-other", "deleted": false},
+  {"id": "st-3", "date_first": "2024-01-01", "date_next": "2024-02-01", "frequency": "monthly", "amount": -900, "account_id": "acc-1", "payee_id": "payee-amazon",
+   "subtransactions": [{"id": "ss-1", "scheduled_transaction_id": "st-3", "amount": -900, "deleted": false}], "deleted": false}
+],"server_knowledge":20}}`))
+
+	var put string
+	httpmock.RegisterResponder(http.MethodPut, base+"/scheduled_transactions/st-1",
+		func(req *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			put = string(body)
+			return httpmock.NewStringResponse(200, `{"data":{"scheduled_transaction":{"id":"st-1","payee_id":"payee-keep"}}}`), nil
+		},
+	)
+
+	client := ynab.NewClient("")
+	summary, err := client.Payee().MergePayees("aa248caa", "payee-keep",
+		[]string{"payee-amazon", "payee-amazon-com", "payee-keep"})
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"transactions":[
+  {"id":"tx-1","payee_id":"payee-keep"},
+  {"id":"tx-2","payee_id":"payee-keep"},
+  {"id":"tx-3","payee_id":"payee-keep"}
+]}`, patched)
+	assert.JSONEq(t, `{"scheduled_transaction":{"account_id":"acc-1","date":"2024-02-01","amount":-1500,"frequency":"monthly",
+  "payee_id":"payee-keep","payee_name":null,"category_id":"cat-1","memo":null,"flag_color":null}}`, put)
+
+	assert.Equal(t, []string{"tx-1", "tx-2", "tx-3"}, summary.TransactionIDs)
+	assert.Equal(t, []string{"st-1"}, summary.ScheduledTransactionIDs)
+	assert.Equal(t, []string{"tx-split", "st-3"}, summary.SkippedSplitIDs)
+	assert.Equal(t, 5, httpmock.GetTotalCallCount())
+}
+
+func TestService_GetPayeeNameMap(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	url := "https://api.youneedabudget.com/v1/budgets/aa248caa/payees"
+	httpmock.RegisterResponder(http.MethodGet, url, httpmock.NewStringResponder(http.StatusOK, `{"data":{"payees":[
+		{"id":"payee-market","name":"Supermarket"},
+		{"id":"payee-gone","name":"Closed Cafe","deleted":true}
+	],"server_knowledge":1}}`))
+
+	client := ynab.NewClient("")
+	names, err := client.Payee().GetPayeeNameMap("aa248caa")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"payee-market": "Supermarket"}, names)
+
+	names, err = client.Payee().GetPayeeNameMap("aa248caa", api.IncludeDeleted())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"payee-market": "Supermarket", "payee-gone": "Closed Cafe"}, names)
+}
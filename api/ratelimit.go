@@ -5,6 +5,8 @@
 package api
 
 import (
+	"encoding/json"
+	"sort"
 	"sync"
 	"time"
 )
@@ -13,19 +15,34 @@ import (
 // to help users stay within YNAB's 200 requests/hour limit.
 // This is completely optional - users can choose whether to use it.
 type RateLimitTracker struct {
-	requests []time.Time
+	requests []recordedRequest
+	used     int
 	mutex    sync.RWMutex
 	limit    int
 	window   time.Duration
+	clock    Clock
+	disabled bool
 }
 
+// recordedRequest is a request recorded at a time, counting as weight
+// requests against the limit
+type recordedRequest struct {
+	Time   time.Time `json:"time"`
+	Weight int       `json:"weight"`
+}
+
+// RateLimitTrackingDisabled is returned by RequestsRemaining when the
+// tracker was created with NewDisabledRateLimitTracker
+const RateLimitTrackingDisabled = -1
+
 // NewRateLimitTracker creates a new rate limit tracker.
 // For YNAB API, use: NewRateLimitTracker(200, time.Hour)
 func NewRateLimitTracker(limit int, window time.Duration) *RateLimitTracker {
 	return &RateLimitTracker{
-		requests: make([]time.Time, 0),
+		requests: make([]recordedRequest, 0),
 		limit:    limit,
 		window:   window,
+		clock:    SystemClock,
 	}
 }
 
@@ -41,17 +58,112 @@ func NewCustomYNABRateLimitTracker(requestsPerHour int) *RateLimitTracker {
 	return NewRateLimitTracker(requestsPerHour, time.Hour)
 }
 
+// NewDisabledRateLimitTracker creates a tracker that records nothing, for
+// clients behind a gateway enforcing its own limits. With tracking
+// disabled, RequestsRemaining returns RateLimitTrackingDisabled, IsAtLimit
+// is always false and no requests are ever reported in the window.
+func NewDisabledRateLimitTracker() *RateLimitTracker {
+	r := NewYNABRateLimitTracker()
+	r.disabled = true
+	return r
+}
+
+// IsDisabled reports whether request tracking is disabled
+func (r *RateLimitTracker) IsDisabled() bool {
+	return r.disabled
+}
+
+// NewRateLimitTrackerFromState creates a rate limit tracker restoring the
+// requests of a state previously produced by RateLimitTracker.MarshalJSON,
+// so short-lived processes can share one rolling window across runs.
+// Requests already outside the window are dropped.
+func NewRateLimitTrackerFromState(limit int, window time.Duration, state []byte) (*RateLimitTracker, error) {
+	r := NewRateLimitTracker(limit, window)
+	if err := r.UnmarshalJSON(state); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// rateLimitState is the serialized form of a RateLimitTracker
+type rateLimitState struct {
+	Requests []recordedRequest `json:"requests"`
+}
+
+// MarshalJSON serializes the time and weight of the requests in the
+// current window
+func (r *RateLimitTracker) MarshalJSON() ([]byte, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.cleanup()
+	return json.Marshal(rateLimitState{Requests: r.requests})
+}
+
+// UnmarshalJSON replaces the recorded requests with the ones serialized by
+// MarshalJSON, dropping those outside the window. The limit and window
+// of the tracker are kept, so it must be created with a constructor first.
+func (r *RateLimitTracker) UnmarshalJSON(data []byte) error {
+	var state rateLimitState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+
+	sort.Slice(state.Requests, func(i, j int) bool {
+		return state.Requests[i].Time.Before(state.Requests[j].Time)
+	})
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.requests = make([]recordedRequest, 0, len(state.Requests))
+	r.used = 0
+	for _, req := range state.Requests {
+		if req.Weight > 0 {
+			r.requests = append(r.requests, req)
+			r.used += req.Weight
+		}
+	}
+	r.cleanup()
+	return nil
+}
+
+// WithClock sets the clock used to timestamp requests and evaluate the
+// rolling window, which makes the tracker deterministic in tests
+func (r *RateLimitTracker) WithClock(clock Clock) *RateLimitTracker {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.clock = clock
+	return r
+}
+
 // RecordRequest records that an API request was made at the current time.
-// Call this after making any YNAB API request.
+// Call this after making any YNAB API request. It does nothing when
+// tracking is disabled.
 func (r *RateLimitTracker) RecordRequest() {
+	r.RecordRequestWeighted(1)
+}
+
+// RecordRequestWeighted records a request made at the current time that
+// counts as weight requests against the limit, e.g. to reserve headroom for
+// writes by weighting them more than reads. A non-positive weight records
+// nothing, as does a disabled tracker.
+func (r *RateLimitTracker) RecordRequestWeighted(weight int) {
+	if r.disabled || weight <= 0 {
+		return
+	}
+
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	r.requests = append(r.requests, time.Now())
+	r.requests = append(r.requests, recordedRequest{Time: r.now(), Weight: weight})
+	r.used += weight
 	r.cleanup()
 }
 
-// RequestsInWindow returns the number of requests made in the current rolling window
+// RequestsInWindow returns the number of requests made in the current
+// rolling window, each counted by the weight it was recorded with
 func (r *RateLimitTracker) RequestsInWindow() int {
 	r.mutex.RLock()
 
@@ -60,18 +172,22 @@ func (r *RateLimitTracker) RequestsInWindow() int {
 		r.mutex.RUnlock()
 		r.mutex.Lock()
 		r.cleanup()
-		count := len(r.requests)
+		count := r.used
 		r.mutex.Unlock()
 		return count
 	}
 
-	count := len(r.requests)
+	count := r.used
 	r.mutex.RUnlock()
 	return count
 }
 
-// RequestsRemaining returns how many requests can be made before hitting the limit
+// RequestsRemaining returns how many requests can be made before hitting
+// the limit, or RateLimitTrackingDisabled when tracking is disabled
 func (r *RateLimitTracker) RequestsRemaining() int {
+	if r.disabled {
+		return RateLimitTrackingDisabled
+	}
 	remaining := r.limit - r.RequestsInWindow()
 	if remaining < 0 {
 		return 0
@@ -80,7 +196,7 @@ func (r *RateLimitTracker) RequestsRemaining() int {
 }
 
 // TimeUntilReset returns the duration until the oldest request falls out of the rolling window,
-// which would free up one request slot. Returns 0 if no requests are recorded.
+// which frees up as many request slots as its weight. Returns 0 if no requests are recorded.
 //
 // Example: If you made 200 API calls over the last 50 minutes, this returns ~10 minutes
 // (the time until the oldest request will be 1 hour old and fall off the rolling window).
@@ -101,18 +217,70 @@ func (r *RateLimitTracker) TimeUntilReset() time.Duration {
 		return 0
 	}
 
-	oldest := r.requests[0]
+	oldest := r.requests[0].Time
 	resetTime := oldest.Add(r.window)
 
-	if resetTime.Before(time.Now()) {
+	now := r.now()
+	if resetTime.Before(now) {
 		return 0
 	}
 
-	return time.Until(resetTime)
+	return resetTime.Sub(now)
+}
+
+// RateLimitSnapshot is a consistent point-in-time view of a RateLimitTracker
+type RateLimitSnapshot struct {
+	// Used is the number of requests made in the current rolling window
+	Used int
+	// Remaining is the number of requests left before hitting the limit
+	Remaining int
+	// Limit is the configured number of requests per window
+	Limit int
+	// Window is the configured rolling window duration
+	Window time.Duration
+	// OldestRequest is the time of the oldest request still in the window,
+	// zero if no requests are recorded
+	OldestRequest time.Time
+	// TimeUntilReset is the duration until OldestRequest falls out of the window
+	TimeUntilReset time.Duration
+}
+
+// Snapshot returns all rate limit figures captured under a single lock,
+// so they are consistent with each other
+func (r *RateLimitTracker) Snapshot() RateLimitSnapshot {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.cleanup()
+
+	snapshot := RateLimitSnapshot{
+		Used:   r.used,
+		Limit:  r.limit,
+		Window: r.window,
+	}
+
+	snapshot.Remaining = r.limit - snapshot.Used
+	if snapshot.Remaining < 0 {
+		snapshot.Remaining = 0
+	}
+	if r.disabled {
+		snapshot.Remaining = RateLimitTrackingDisabled
+	}
+
+	if len(r.requests) > 0 {
+		snapshot.OldestRequest = r.requests[0].Time
+		snapshot.TimeUntilReset = snapshot.OldestRequest.Add(r.window).Sub(r.now())
+	}
+
+	return snapshot
 }
 
-// IsAtLimit returns true if the rate limit has been reached
+// IsAtLimit returns true if the rate limit has been reached. It is always
+// false when tracking is disabled.
 func (r *RateLimitTracker) IsAtLimit() bool {
+	if r.disabled {
+		return false
+	}
 	return r.RequestsInWindow() >= r.limit
 }
 
@@ -123,6 +291,7 @@ func (r *RateLimitTracker) Reset() {
 	defer r.mutex.Unlock()
 
 	r.requests = r.requests[:0]
+	r.used = 0
 }
 
 // GetLimit returns the configured rate limit (requests per window)
@@ -135,6 +304,15 @@ func (r *RateLimitTracker) GetWindow() time.Duration {
 	return r.window
 }
 
+// now returns the time of the tracker's clock, falling back to
+// SystemClock for a zero-value RateLimitTracker
+func (r *RateLimitTracker) now() time.Time {
+	if r.clock == nil {
+		return SystemClock.Now()
+	}
+	return r.clock.Now()
+}
+
 // needsCleanup checks if cleanup is needed without modifying state.
 // Must be called with at least a read lock held.
 func (r *RateLimitTracker) needsCleanup() bool {
@@ -142,24 +320,26 @@ func (r *RateLimitTracker) needsCleanup() bool {
 		return false
 	}
 
-	cutoff := time.Now().Add(-r.window)
-	return r.requests[0].Before(cutoff) || r.requests[0].Equal(cutoff)
+	cutoff := r.now().Add(-r.window)
+	return !r.requests[0].Time.After(cutoff)
 }
 
 // cleanup removes requests that are outside the rolling window
 // Must be called with a write lock held.
 func (r *RateLimitTracker) cleanup() {
-	cutoff := time.Now().Add(-r.window)
+	cutoff := r.now().Add(-r.window)
 
 	// Find the first request that's still within the window
-	for i, reqTime := range r.requests {
-		if reqTime.After(cutoff) {
+	for i, req := range r.requests {
+		if req.Time.After(cutoff) {
 			// Keep requests from index i onwards
 			r.requests = r.requests[i:]
 			return
 		}
+		r.used -= req.Weight
 	}
 
 	// All requests are outside the window
 	r.requests = r.requests[:0]
+	r.used = 0
 }
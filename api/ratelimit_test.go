@@ -5,10 +5,13 @@
 package api
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/coltoneshaw/ynab.go/ynabtest"
 )
 
 func TestNewRateLimitTracker(t *testing.T) {
@@ -42,6 +45,60 @@ func TestRateLimitTracker_RecordRequest(t *testing.T) {
 	assert.Equal(t, 2, tracker.RequestsRemaining())
 }
 
+func TestRateLimitTracker_RecordRequestWeighted(t *testing.T) {
+	clock := ynabtest.NewClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	tracker := NewRateLimitTracker(10, time.Minute).WithClock(clock)
+
+	tracker.RecordRequest()
+	clock.Advance(10 * time.Second)
+	tracker.RecordRequestWeighted(3)
+	assert.Equal(t, 4, tracker.RequestsInWindow())
+	assert.Equal(t, 6, tracker.RequestsRemaining())
+
+	// Non-positive weights are not recorded
+	tracker.RecordRequestWeighted(0)
+	tracker.RecordRequestWeighted(-2)
+	assert.Equal(t, 4, tracker.RequestsInWindow())
+
+	tracker.RecordRequestWeighted(6)
+	assert.Equal(t, 0, tracker.RequestsRemaining())
+	assert.True(t, tracker.IsAtLimit())
+
+	// A weighted request rolls off the window as a whole
+	clock.Advance(50 * time.Second)
+	assert.Equal(t, 9, tracker.RequestsInWindow())
+	clock.Advance(10 * time.Second)
+	assert.Equal(t, 0, tracker.RequestsInWindow())
+
+	disabled := NewDisabledRateLimitTracker()
+	disabled.RecordRequestWeighted(5)
+	assert.Equal(t, 0, disabled.RequestsInWindow())
+}
+
+func TestRateLimitTracker_WeightedState(t *testing.T) {
+	clock := ynabtest.NewClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	tracker := NewRateLimitTracker(200, time.Hour).WithClock(clock)
+	tracker.RecordRequest()
+	tracker.RecordRequestWeighted(50)
+
+	// A weighted request is stored once with its weight
+	state, err := json.Marshal(tracker)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"requests":[
+		{"time":"2024-01-01T12:00:00Z","weight":1},
+		{"time":"2024-01-01T12:00:00Z","weight":50}
+	]}`, string(state))
+	assert.Len(t, tracker.requests, 2)
+
+	restored := NewRateLimitTracker(200, time.Hour).WithClock(clock)
+	assert.NoError(t, restored.UnmarshalJSON(state))
+	assert.Equal(t, 51, restored.RequestsInWindow())
+	assert.Equal(t, 51, restored.Snapshot().Used)
+
+	tracker.Reset()
+	assert.Equal(t, 0, tracker.Snapshot().Used)
+}
+
 func TestRateLimitTracker_IsAtLimit(t *testing.T) {
 	tracker := NewRateLimitTracker(3, time.Minute)
 
@@ -60,16 +117,16 @@ func TestRateLimitTracker_IsAtLimit(t *testing.T) {
 }
 
 func TestRateLimitTracker_TimeWindow(t *testing.T) {
-	// Use a very short window for testing
-	tracker := NewRateLimitTracker(5, 100*time.Millisecond)
+	clock := ynabtest.NewClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	tracker := NewRateLimitTracker(5, 100*time.Millisecond).WithClock(clock)
 
 	// Record some requests
 	tracker.RecordRequest()
 	tracker.RecordRequest()
 	assert.Equal(t, 2, tracker.RequestsInWindow())
 
-	// Wait for window to expire
-	time.Sleep(150 * time.Millisecond)
+	// Move past the window
+	clock.Advance(150 * time.Millisecond)
 
 	// Requests should be cleaned up
 	assert.Equal(t, 0, tracker.RequestsInWindow())
@@ -78,18 +135,18 @@ func TestRateLimitTracker_TimeWindow(t *testing.T) {
 }
 
 func TestRateLimitTracker_TimeUntilReset(t *testing.T) {
-	tracker := NewRateLimitTracker(5, time.Minute)
+	clock := ynabtest.NewClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	tracker := NewRateLimitTracker(5, time.Minute).WithClock(clock)
 
 	// No requests recorded
 	assert.Equal(t, time.Duration(0), tracker.TimeUntilReset())
 
 	// Record a request
 	tracker.RecordRequest()
+	assert.Equal(t, time.Minute, tracker.TimeUntilReset())
 
-	// Should have some time until reset
-	resetTime := tracker.TimeUntilReset()
-	assert.True(t, resetTime > 59*time.Second)
-	assert.True(t, resetTime <= time.Minute)
+	clock.Advance(20 * time.Second)
+	assert.Equal(t, 40*time.Second, tracker.TimeUntilReset())
 }
 
 func TestRateLimitTracker_Methods(t *testing.T) {
@@ -112,26 +169,24 @@ func TestRateLimitTracker_Methods(t *testing.T) {
 }
 
 func TestRateLimitTracker_Cleanup(t *testing.T) {
-	tracker := NewRateLimitTracker(10, 50*time.Millisecond)
+	clock := ynabtest.NewClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	tracker := NewRateLimitTracker(10, 50*time.Millisecond).WithClock(clock)
 
 	// Record requests over time
 	tracker.RecordRequest()
-	time.Sleep(20 * time.Millisecond)
+	clock.Advance(20 * time.Millisecond)
 	tracker.RecordRequest()
-	time.Sleep(20 * time.Millisecond)
+	clock.Advance(20 * time.Millisecond)
 	tracker.RecordRequest()
 
 	assert.Equal(t, 3, tracker.RequestsInWindow())
 
-	// Wait for some to expire
-	time.Sleep(40 * time.Millisecond)
-
-	// First request should be cleaned up
-	requests := tracker.RequestsInWindow()
-	assert.True(t, requests < 3)
+	// First request expires at 50ms
+	clock.Advance(20 * time.Millisecond)
+	assert.Equal(t, 2, tracker.RequestsInWindow())
 
 	// Wait for all to expire
-	time.Sleep(50 * time.Millisecond)
+	clock.Advance(50 * time.Millisecond)
 	assert.Equal(t, 0, tracker.RequestsInWindow())
 }
 
@@ -245,33 +300,31 @@ func TestRateLimitTracker_ConcurrentReadWrite(t *testing.T) {
 // Time Precision Tests - Phase 1
 
 func TestRateLimitTracker_MicrosecondPrecision(t *testing.T) {
-	// Use a very short window to test precise timing
-	tracker := NewRateLimitTracker(5, 10*time.Millisecond)
+	clock := ynabtest.NewClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	tracker := NewRateLimitTracker(5, 10*time.Millisecond).WithClock(clock)
 
 	// Record a request
 	tracker.RecordRequest()
 	assert.Equal(t, 1, tracker.RequestsInWindow())
 
-	// Wait just under the window - should still be present
-	time.Sleep(8 * time.Millisecond)
+	// Just under the window - should still be present
+	clock.Advance(10*time.Millisecond - time.Microsecond)
 	assert.Equal(t, 1, tracker.RequestsInWindow())
 
-	// Wait past the window boundary - should be cleaned up
-	time.Sleep(5 * time.Millisecond) // Total: 13ms > 10ms window
+	// Past the window boundary - should be cleaned up
+	clock.Advance(2 * time.Microsecond)
 	assert.Equal(t, 0, tracker.RequestsInWindow())
 	assert.Equal(t, 5, tracker.RequestsRemaining())
 }
 
 func TestRateLimitTracker_RapidSequence(t *testing.T) {
-	// Test rapid requests in quick succession
-	tracker := NewRateLimitTracker(10, 50*time.Millisecond)
+	clock := ynabtest.NewClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	tracker := NewRateLimitTracker(10, 50*time.Millisecond).WithClock(clock)
 
 	// Record multiple requests rapidly
 	for i := 0; i < 5; i++ {
 		tracker.RecordRequest()
-		if i < 4 { // Don't sleep after the last request
-			time.Sleep(1 * time.Millisecond)
-		}
+		clock.Advance(time.Millisecond)
 	}
 
 	// All requests should be tracked
@@ -279,8 +332,8 @@ func TestRateLimitTracker_RapidSequence(t *testing.T) {
 	assert.Equal(t, 5, tracker.RequestsRemaining())
 	assert.False(t, tracker.IsAtLimit())
 
-	// Wait for window to expire
-	time.Sleep(55 * time.Millisecond)
+	// Move past the window
+	clock.Advance(50 * time.Millisecond)
 
 	// All should be cleaned up
 	assert.Equal(t, 0, tracker.RequestsInWindow())
@@ -288,28 +341,19 @@ func TestRateLimitTracker_RapidSequence(t *testing.T) {
 }
 
 func TestRateLimitTracker_BoundaryEdge(t *testing.T) {
-	// Test requests exactly at window boundaries
-	tracker := NewRateLimitTracker(5, 100*time.Millisecond)
+	clock := ynabtest.NewClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	tracker := NewRateLimitTracker(5, 100*time.Millisecond).WithClock(clock)
 
-	// Record first request
-	start := time.Now()
 	tracker.RecordRequest()
 	assert.Equal(t, 1, tracker.RequestsInWindow())
 
-	// Wait almost to the boundary
-	elapsed := time.Since(start)
-	waitTime := 100*time.Millisecond - elapsed - 5*time.Millisecond
-	if waitTime > 0 {
-		time.Sleep(waitTime)
-	}
-
-	// Should still be present (just before boundary)
+	// Just before the boundary
+	clock.Advance(100*time.Millisecond - time.Nanosecond)
 	assert.Equal(t, 1, tracker.RequestsInWindow())
+	assert.Equal(t, time.Nanosecond, tracker.TimeUntilReset())
 
-	// Now wait past the boundary
-	time.Sleep(10 * time.Millisecond)
-
-	// Should be cleaned up
+	// Exactly at the boundary the request falls out of the window
+	clock.Advance(time.Nanosecond)
 	assert.Equal(t, 0, tracker.RequestsInWindow())
 	assert.Equal(t, 5, tracker.RequestsRemaining())
 
@@ -318,34 +362,185 @@ func TestRateLimitTracker_BoundaryEdge(t *testing.T) {
 }
 
 func TestRateLimitTracker_CleanupTiming(t *testing.T) {
-	// Test partial vs full cleanup scenarios
-	tracker := NewRateLimitTracker(10, 40*time.Millisecond)
+	clock := ynabtest.NewClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	tracker := NewRateLimitTracker(10, 40*time.Millisecond).WithClock(clock)
 
 	// Record requests at different times
 	tracker.RecordRequest() // Request 1
-	time.Sleep(15 * time.Millisecond)
+	clock.Advance(15 * time.Millisecond)
 
 	tracker.RecordRequest() // Request 2
-	time.Sleep(15 * time.Millisecond)
+	clock.Advance(15 * time.Millisecond)
 
 	tracker.RecordRequest() // Request 3
 
 	// All 3 should be present
 	assert.Equal(t, 3, tracker.RequestsInWindow())
 
-	// Wait for first request to expire (45ms total elapsed)
-	time.Sleep(15 * time.Millisecond)
+	// First request expires (45ms total elapsed)
+	clock.Advance(15 * time.Millisecond)
+	assert.Equal(t, 2, tracker.RequestsInWindow(), "first request should be cleaned up")
 
-	// Should have partial cleanup - first request expired
-	requests := tracker.RequestsInWindow()
-	assert.True(t, requests < 3, "Should have fewer than 3 requests after partial cleanup")
-	assert.True(t, requests > 0, "Should still have some requests")
-
-	// Wait for all to expire
-	time.Sleep(30 * time.Millisecond)
+	// Second request expires (55ms total elapsed)
+	clock.Advance(10 * time.Millisecond)
+	assert.Equal(t, 1, tracker.RequestsInWindow(), "second request should be cleaned up")
 
 	// Full cleanup - all should be gone
+	clock.Advance(20 * time.Millisecond)
 	assert.Equal(t, 0, tracker.RequestsInWindow())
 	assert.Equal(t, 10, tracker.RequestsRemaining())
 	assert.False(t, tracker.IsAtLimit())
 }
+
+func TestRateLimitTracker_WindowRollover(t *testing.T) {
+	clock := ynabtest.NewClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	tracker := NewYNABRateLimitTracker().WithClock(clock)
+
+	// 200 requests spread evenly over 50 minutes
+	for i := 0; i < 200; i++ {
+		tracker.RecordRequest()
+		clock.Advance(15 * time.Second)
+	}
+	clock.Advance(-15 * time.Second)
+
+	assert.True(t, tracker.IsAtLimit())
+	assert.Equal(t, 0, tracker.RequestsRemaining())
+	assert.Equal(t, 10*time.Minute+15*time.Second, tracker.TimeUntilReset())
+
+	// Once the oldest request rolls out of the window one slot frees up
+	clock.Advance(10*time.Minute + 15*time.Second)
+	assert.False(t, tracker.IsAtLimit())
+	assert.Equal(t, 1, tracker.RequestsRemaining())
+	assert.Equal(t, 15*time.Second, tracker.TimeUntilReset())
+
+	// After a full hour of inactivity the window is empty again
+	clock.Advance(time.Hour)
+	assert.Equal(t, 0, tracker.RequestsInWindow())
+	assert.Equal(t, 200, tracker.RequestsRemaining())
+}
+
+func TestRateLimitTracker_Snapshot(t *testing.T) {
+	clock := ynabtest.NewClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	tracker := NewRateLimitTracker(3, time.Minute).WithClock(clock)
+
+	snapshot := tracker.Snapshot()
+	assert.Equal(t, RateLimitSnapshot{
+		Used:      0,
+		Remaining: 3,
+		Limit:     3,
+		Window:    time.Minute,
+	}, snapshot)
+
+	first := clock.Now()
+	tracker.RecordRequest()
+	clock.Advance(10 * time.Second)
+	tracker.RecordRequest()
+	clock.Advance(10 * time.Second)
+	tracker.RecordRequest()
+	tracker.RecordRequest()
+
+	snapshot = tracker.Snapshot()
+	assert.Equal(t, 4, snapshot.Used)
+	assert.Equal(t, 0, snapshot.Remaining)
+	assert.Equal(t, 3, snapshot.Limit)
+	assert.Equal(t, time.Minute, snapshot.Window)
+	assert.Equal(t, first, snapshot.OldestRequest)
+	assert.Equal(t, 40*time.Second, snapshot.TimeUntilReset)
+	assert.Equal(t, snapshot.OldestRequest.Add(snapshot.Window), clock.Now().Add(snapshot.TimeUntilReset))
+
+	// After the oldest request rolls off the snapshot reflects the new oldest
+	clock.Advance(40 * time.Second)
+	snapshot = tracker.Snapshot()
+	assert.Equal(t, 3, snapshot.Used)
+	assert.Equal(t, 0, snapshot.Remaining)
+	assert.Equal(t, first.Add(10*time.Second), snapshot.OldestRequest)
+	assert.Equal(t, 10*time.Second, snapshot.TimeUntilReset)
+	assert.Equal(t, tracker.RequestsInWindow(), snapshot.Used)
+	assert.Equal(t, tracker.TimeUntilReset(), snapshot.TimeUntilReset)
+}
+
+func TestRateLimitTracker_JSONRoundTrip(t *testing.T) {
+	clock := ynabtest.NewClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	tracker := NewRateLimitTracker(5, time.Minute).WithClock(clock)
+
+	tracker.RecordRequest()
+	clock.Advance(20 * time.Second)
+	tracker.RecordRequest()
+	clock.Advance(20 * time.Second)
+	tracker.RecordRequest()
+
+	state, err := json.Marshal(tracker)
+	assert.NoError(t, err)
+
+	restored := NewRateLimitTracker(5, time.Minute).WithClock(clock)
+	assert.NoError(t, json.Unmarshal(state, restored))
+	assert.Equal(t, tracker.Snapshot(), restored.Snapshot())
+	assert.Equal(t, 3, restored.RequestsInWindow())
+	assert.Equal(t, 20*time.Second, restored.TimeUntilReset())
+
+	// Requests that left the window while the state was stored are dropped
+	clock.Advance(45 * time.Second)
+	restored = NewRateLimitTracker(5, time.Minute).WithClock(clock)
+	assert.NoError(t, restored.UnmarshalJSON(state))
+	assert.Equal(t, 1, restored.RequestsInWindow())
+	assert.Equal(t, 4, restored.RequestsRemaining())
+}
+
+func TestNewRateLimitTrackerFromState(t *testing.T) {
+	tracker := NewRateLimitTracker(5, time.Hour)
+	tracker.RecordRequest()
+	tracker.RecordRequest()
+
+	state, err := json.Marshal(tracker)
+	assert.NoError(t, err)
+
+	restored, err := NewRateLimitTrackerFromState(5, time.Hour, state)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, restored.RequestsInWindow())
+	assert.Equal(t, 3, restored.RequestsRemaining())
+	assert.Equal(t, 5, restored.GetLimit())
+	assert.Equal(t, time.Hour, restored.GetWindow())
+
+	// Timestamps older than the window are dropped on load
+	old := []byte(`{"requests":[{"time":"2000-01-01T00:00:00Z","weight":1}]}`)
+	restored, err = NewRateLimitTrackerFromState(5, time.Hour, old)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, restored.RequestsInWindow())
+
+	_, err = NewRateLimitTrackerFromState(5, time.Hour, []byte(`not json`))
+	assert.Error(t, err)
+}
+
+func TestNewDisabledRateLimitTracker(t *testing.T) {
+	tracker := NewDisabledRateLimitTracker()
+	assert.True(t, tracker.IsDisabled())
+	assert.False(t, NewYNABRateLimitTracker().IsDisabled())
+
+	for i := 0; i < 250; i++ {
+		tracker.RecordRequest()
+	}
+
+	assert.Equal(t, 0, tracker.RequestsInWindow())
+	assert.Equal(t, RateLimitTrackingDisabled, tracker.RequestsRemaining())
+	assert.False(t, tracker.IsAtLimit())
+	assert.Equal(t, time.Duration(0), tracker.TimeUntilReset())
+
+	snapshot := tracker.Snapshot()
+	assert.Equal(t, 0, snapshot.Used)
+	assert.Equal(t, RateLimitTrackingDisabled, snapshot.Remaining)
+}
+
+func TestRateLimitTracker_ZeroValue(t *testing.T) {
+	var tracker RateLimitTracker
+
+	assert.NotPanics(t, func() {
+		tracker.RecordRequest()
+		tracker.RecordRequestWeighted(2)
+		assert.Equal(t, 0, tracker.RequestsInWindow())
+		assert.Equal(t, time.Duration(0), tracker.TimeUntilReset())
+		assert.True(t, tracker.IsAtLimit())
+		assert.Equal(t, 0, tracker.Snapshot().Used)
+		_, err := json.Marshal(&tracker)
+		assert.NoError(t, err)
+	})
+}
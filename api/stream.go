@@ -0,0 +1,34 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// StreamFunc consumes the body of a successful response while it is read
+// from the network. Given as the response model of a request, it replaces
+// decoding the whole body with the codec, so large responses are never held
+// in memory. Its error is returned by the request.
+type StreamFunc func(body io.Reader) error
+
+// StreamReader is implemented by clients able to hand the raw body of a
+// GET response to a StreamFunc
+type StreamReader interface {
+	GETStream(url string, fn StreamFunc) error
+}
+
+// GETStream sends a GET request through c and passes the response body to
+// fn. When c does not implement StreamReader the body is read in full
+// first, so fn still receives it but memory is not saved.
+func GETStream(c ClientReader, url string, fn StreamFunc) error {
+	if r, ok := c.(StreamReader); ok {
+		return r.GETStream(url, fn)
+	}
+
+	var body json.RawMessage
+	if err := c.GET(url, &body); err != nil {
+		return err
+	}
+	return fn(bytes.NewReader(body))
+}
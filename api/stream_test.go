@@ -0,0 +1,80 @@
+package api_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coltoneshaw/ynab.go/api"
+)
+
+type getRecorder struct {
+	body string
+	gets int
+}
+
+func (r *getRecorder) GET(url string, responseModel any) error {
+	r.gets++
+	return json.Unmarshal([]byte(r.body), responseModel)
+}
+
+type streamRecorder struct {
+	getRecorder
+	streams int
+}
+
+func (r *streamRecorder) GETStream(url string, fn api.StreamFunc) error {
+	r.streams++
+	return fn(strings.NewReader(r.body))
+}
+
+func TestGETStream(t *testing.T) {
+	var received []string
+	fn := func(body io.Reader) error {
+		data, err := io.ReadAll(body)
+		received = append(received, string(data))
+		return err
+	}
+
+	streaming := &streamRecorder{getRecorder: getRecorder{body: `{"data":{}}`}}
+	require.NoError(t, api.GETStream(streaming, "/foo", fn))
+	assert.Equal(t, 1, streaming.streams)
+	assert.Zero(t, streaming.gets)
+
+	plain := &getRecorder{body: `{"data":{}}`}
+	require.NoError(t, api.GETStream(plain, "/foo", fn))
+	assert.Equal(t, 1, plain.gets)
+
+	assert.Equal(t, []string{`{"data":{}}`, `{"data":{}}`}, received)
+}
+
+func TestHTTPClient_HandleResponse_StreamFunc(t *testing.T) {
+	h := api.NewHTTPClient()
+	response := func(status int, body string) *http.Response {
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}
+	}
+
+	var streamed string
+	fn := api.StreamFunc(func(body io.Reader) error {
+		data, err := io.ReadAll(body)
+		streamed = string(data)
+		return err
+	})
+
+	require.NoError(t, h.HandleResponse(response(http.StatusOK, `{"data":{"transactions":[]}}`), fn))
+	assert.Equal(t, `{"data":{"transactions":[]}}`, streamed)
+
+	// Errors are decoded as usual and never reach the stream
+	streamed = ""
+	err := h.HandleResponse(response(http.StatusNotFound,
+		`{"error":{"id":"404.2","name":"resource_not_found","detail":"Resource not found"}}`), fn)
+	var apiErr *api.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.True(t, apiErr.IsNotFound())
+	assert.Empty(t, streamed)
+}
@@ -24,6 +24,10 @@ type TokenProvider interface {
 	// GetAccessTokenString returns the current token as a string without context.
 	// This is provided for convenience and backward compatibility.
 	GetAccessTokenString() string
+
+	// CanWrite returns true if the token permits write requests.
+	// It returns false for OAuth tokens limited to the read-only scope.
+	CanWrite() bool
 }
 
 // StaticTokenProvider implements TokenProvider for static API keys.
@@ -69,6 +73,11 @@ func (p *StaticTokenProvider) GetAccessTokenString() string {
 	return p.token
 }
 
+// CanWrite always returns true as personal access tokens have full access.
+func (p *StaticTokenProvider) CanWrite() bool {
+	return true
+}
+
 // OAuthTokenProvider implements TokenProvider for OAuth tokens with automatic refresh.
 // This wraps the existing TokenManager to provide the TokenProvider interface.
 type OAuthTokenProvider struct {
@@ -80,6 +89,7 @@ type OAuthTokenProvider struct {
 type OAuthTokenManager interface {
 	GetAccessToken(ctx context.Context) (string, error)
 	IsAuthenticated() bool
+	CanWrite() bool
 }
 
 // NewOAuthTokenProvider creates a new OAuthTokenProvider wrapping a TokenManager.
@@ -114,3 +124,8 @@ func (p *OAuthTokenProvider) GetAccessTokenString() string {
 	}
 	return token
 }
+
+// CanWrite returns true unless the OAuth token is limited to the read-only scope.
+func (p *OAuthTokenProvider) CanWrite() bool {
+	return p.manager.CanWrite()
+}
@@ -0,0 +1,74 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// defaultAccountWorkers the number of accounts GetTransactionsForAccounts
+// fetches at once unless WithAccountConcurrency is used
+const defaultAccountWorkers = 4
+
+// WithAccountConcurrency sets how many accounts GetTransactionsForAccounts
+// fetches at once. A value of zero or less restores the default of four.
+func (s *Service) WithAccountConcurrency(n int) *Service {
+	s.accountWorkers = n
+	return s
+}
+
+// GetTransactionsForAccounts fetches the transactions of several accounts
+// concurrently, returning them by account ID. Each account is requested
+// with GetTransactionsByAccount and the same filter, through the client,
+// so its rate limiting and concurrency limits apply to every request.
+//
+// When some accounts fail, the map still holds the accounts fetched
+// successfully and the returned error joins one error per failed account.
+// https://api.youneedabudget.com/v1#/Transactions/getTransactionsByAccount
+func (s *Service) GetTransactionsForAccounts(budgetID string, accountIDs []string,
+	f *Filter) (map[string][]*Transaction, error) {
+
+	workers := s.accountWorkers
+	if workers <= 0 {
+		workers = defaultAccountWorkers
+	}
+
+	seen := make(map[string]bool, len(accountIDs))
+	jobs := make(chan string, len(accountIDs))
+	for _, id := range accountIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		jobs <- id
+	}
+	close(jobs)
+
+	full := s.fullReads()
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		result = make(map[string][]*Transaction, len(seen))
+		errs   []error
+	)
+	for range min(workers, len(seen)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for accountID := range jobs {
+				res, err := full.GetTransactionsByAccount(budgetID, accountID, f)
+
+				mu.Lock()
+				if err != nil {
+					errs = append(errs, fmt.Errorf("account %q: %w", accountID, err))
+				} else {
+					result[accountID] = res.Transactions
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return result, errors.Join(errs...)
+}
@@ -0,0 +1,98 @@
+package transaction_test
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/jarcoal/httpmock.v1"
+
+	"github.com/coltoneshaw/ynab.go"
+	"github.com/coltoneshaw/ynab.go/api"
+)
+
+func TestService_GetTransactionsForAccounts(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	accountResponder := func(txID string) httpmock.Responder {
+		return func(req *http.Request) (*http.Response, error) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				m := maxInFlight.Load()
+				if n <= m || maxInFlight.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+
+			return httpmock.NewStringResponse(http.StatusOK, fmt.Sprintf(
+				`{"data":{"transactions":[{"id":"%s","amount":-1000}],"server_knowledge":10}}`, txID)), nil
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		inFlight.Store(0)
+		maxInFlight.Store(0)
+
+		httpmock.RegisterResponder(http.MethodGet,
+			"https://api.youneedabudget.com/v1/budgets/aa248caa/accounts/checking/transactions",
+			accountResponder("tx-checking"))
+		httpmock.RegisterResponder(http.MethodGet,
+			"https://api.youneedabudget.com/v1/budgets/aa248caa/accounts/savings/transactions",
+			accountResponder("tx-savings"))
+
+		s := ynab.NewClient("").Transaction().WithAccountConcurrency(1)
+		got, err := s.GetTransactionsForAccounts("aa248caa", []string{"checking", "savings", "checking"}, nil)
+		require.NoError(t, err)
+
+		require.Len(t, got, 2)
+		require.Len(t, got["checking"], 1)
+		assert.Equal(t, "tx-checking", got["checking"][0].ID)
+		require.Len(t, got["savings"], 1)
+		assert.Equal(t, "tx-savings", got["savings"][0].ID)
+
+		assert.Equal(t, int32(1), maxInFlight.Load())
+		assert.Equal(t, 2, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("partial failure", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodGet,
+			"https://api.youneedabudget.com/v1/budgets/aa248caa/accounts/checking/transactions",
+			accountResponder("tx-checking"))
+		httpmock.RegisterResponder(http.MethodGet,
+			"https://api.youneedabudget.com/v1/budgets/aa248caa/accounts/missing/transactions",
+			httpmock.NewStringResponder(http.StatusNotFound,
+				`{"error":{"id":"404.2","name":"resource_not_found","detail":"Account not found"}}`))
+
+		got, err := ynab.NewClient("").Transaction().GetTransactionsForAccounts("aa248caa",
+			[]string{"checking", "missing"}, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `account "missing"`)
+
+		var apiErr *api.Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, "404.2", apiErr.ID)
+
+		require.Len(t, got, 1)
+		assert.Equal(t, "tx-checking", got["checking"][0].ID)
+	})
+
+	t.Run("no accounts", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		got, err := ynab.NewClient("").Transaction().GetTransactionsForAccounts("aa248caa", nil, nil)
+		require.NoError(t, err)
+		assert.Empty(t, got)
+		assert.Zero(t, httpmock.GetTotalCallCount())
+	})
+}
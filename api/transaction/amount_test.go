@@ -0,0 +1,64 @@
+package transaction_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/jarcoal/httpmock.v1"
+
+	"github.com/coltoneshaw/ynab.go"
+	"github.com/coltoneshaw/ynab.go/api"
+	"github.com/coltoneshaw/ynab.go/api/transaction"
+)
+
+func TestTransaction_UnmarshalFloatAmount(t *testing.T) {
+	var integer, float transaction.Transaction
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"tx-1","amount":-43950,"subtransactions":[{"amount":-43950}]}`), &integer))
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"tx-1","amount":-43950.0,"subtransactions":[{"amount":-4.395e4}]}`), &float))
+	assert.Equal(t, api.Milliunits(-43950), integer.Amount)
+	assert.Equal(t, integer, float)
+
+	var s transaction.Scheduled
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":-43950.5,"subtransactions":[{"amount":0.49}]}`), &s))
+	assert.Equal(t, api.Milliunits(-43951), s.Amount)
+	assert.Equal(t, api.Milliunits(0), s.SubTransactions[0].Amount)
+
+	var h transaction.Hybrid
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":43950.5}`), &h))
+	assert.Equal(t, api.Milliunits(43951), h.Amount)
+}
+
+func TestService_FloatAmounts(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets/aa248caa/transactions",
+		httpmock.NewStringResponder(http.StatusOK, `{"data":{"transactions":[
+			{"id":"tx-1","date":"2024-01-01","amount":-43950.0,"cleared":"cleared","subtransactions":[
+				{"id":"sub-1","transaction_id":"tx-1","amount":-43949.5}
+			]},
+			{"id":"tx-2","date":"2024-01-02","amount":-43950,"cleared":"cleared","subtransactions":[]}
+		],"server_knowledge":12}}`))
+
+	client := ynab.NewClient("")
+
+	result, err := client.Transaction().GetTransactions("aa248caa", nil)
+	require.NoError(t, err)
+	require.Len(t, result.Transactions, 2)
+
+	var streamed []*transaction.Transaction
+	require.NoError(t, client.Transaction().StreamTransactions("aa248caa", nil, func(tx *transaction.Transaction) error {
+		streamed = append(streamed, tx)
+		return nil
+	}))
+	assert.Equal(t, result.Transactions, streamed)
+
+	for _, txs := range [][]*transaction.Transaction{result.Transactions, streamed} {
+		assert.Equal(t, api.Milliunits(-43950), txs[0].Amount)
+		assert.Equal(t, api.Milliunits(-43950), txs[0].SubTransactions[0].Amount)
+		assert.Equal(t, api.Milliunits(-43950), txs[1].Amount)
+	}
+}
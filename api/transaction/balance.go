@@ -0,0 +1,51 @@
+package transaction
+
+import (
+	"slices"
+	"strings"
+)
+
+// RunningBalances returns the account balance after each transaction,
+// starting from the opening balance and applying the transactions in the
+// given order, which should be chronological. Deleted transactions do not
+// affect the balance but still get an entry so the result lines up with txs.
+func RunningBalances(opening int64, txs []*Transaction) []int64 {
+	balances := make([]int64, len(txs))
+	balance := opening
+	for i, t := range txs {
+		if t != nil && !t.Deleted {
+			balance += int64(t.Amount)
+		}
+		balances[i] = balance
+	}
+	return balances
+}
+
+// SortedRunningBalances sorts a copy of txs chronologically with
+// SortChronologically and returns it along with the running balances
+// computed by RunningBalances, leaving txs untouched
+func SortedRunningBalances(opening int64, txs []*Transaction) ([]*Transaction, []int64) {
+	sorted := slices.Clone(txs)
+	SortChronologically(sorted)
+	return sorted, RunningBalances(opening, sorted)
+}
+
+// SortChronologically sorts transactions by date, breaking ties between
+// transactions of the same date by ID so the order is deterministic.
+// Nil transactions are moved to the end.
+func SortChronologically(txs []*Transaction) {
+	slices.SortStableFunc(txs, func(a, b *Transaction) int {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return 1
+		case b == nil:
+			return -1
+		}
+		if c := a.Date.Compare(b.Date.Time); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+}
@@ -0,0 +1,72 @@
+package transaction_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coltoneshaw/ynab.go/api"
+	"github.com/coltoneshaw/ynab.go/api/transaction"
+)
+
+func balanceTx(t *testing.T, id, date string, amount int64) *transaction.Transaction {
+	d, err := api.DateFromString(date)
+	require.NoError(t, err)
+	return &transaction.Transaction{ID: id, Date: d, Amount: api.Milliunits(amount)}
+}
+
+func TestRunningBalances(t *testing.T) {
+	deleted := balanceTx(t, "tx-3", "2024-01-03", -99000)
+	deleted.Deleted = true
+
+	txs := []*transaction.Transaction{
+		balanceTx(t, "tx-1", "2024-01-01", 250000),
+		balanceTx(t, "tx-2", "2024-01-02", -42500),
+		deleted,
+		balanceTx(t, "tx-4", "2024-01-04", -7500),
+		balanceTx(t, "tx-5", "2024-01-05", 1000),
+	}
+
+	balances := transaction.RunningBalances(100000, txs)
+	assert.Equal(t, []int64{350000, 307500, 307500, 300000, 301000}, balances)
+
+	assert.Empty(t, transaction.RunningBalances(100000, nil))
+	assert.Equal(t, []int64{-5000}, transaction.RunningBalances(0,
+		[]*transaction.Transaction{balanceTx(t, "tx", "2024-01-01", -5000)}))
+}
+
+func TestSortedRunningBalances(t *testing.T) {
+	txs := []*transaction.Transaction{
+		balanceTx(t, "c", "2024-01-02", -1000),
+		balanceTx(t, "b", "2024-01-01", 5000),
+		balanceTx(t, "a", "2024-01-02", -2000),
+		balanceTx(t, "d", "2023-12-31", 10000),
+	}
+
+	sorted, balances := transaction.SortedRunningBalances(0, txs)
+
+	var ids []string
+	for _, tx := range sorted {
+		ids = append(ids, tx.ID)
+	}
+	assert.Equal(t, []string{"d", "b", "a", "c"}, ids)
+	assert.Equal(t, []int64{10000, 15000, 13000, 12000}, balances)
+
+	// The input order is left untouched
+	assert.Equal(t, "c", txs[0].ID)
+}
+
+func TestSortChronologically(t *testing.T) {
+	txs := []*transaction.Transaction{
+		nil,
+		balanceTx(t, "b", "2024-01-01", 0),
+		balanceTx(t, "a", "2024-01-01", 0),
+	}
+
+	transaction.SortChronologically(txs)
+	require.Len(t, txs, 3)
+	assert.Equal(t, "a", txs[0].ID)
+	assert.Equal(t, "b", txs[1].ID)
+	assert.Nil(t, txs[2])
+}
@@ -0,0 +1,117 @@
+package transaction
+
+import (
+	"fmt"
+)
+
+// DefaultChunkSize the number of transactions CreateTransactionsChunked and
+// UpdateTransactionsChunked send per request when given a chunk size of
+// zero or less
+const DefaultChunkSize = 100
+
+// BatchOption configures a chunked batch method
+type BatchOption func(*BatchOptions)
+
+// BatchOptions holds the options of a chunked batch method
+type BatchOptions struct {
+	// Progress is called with the number of transactions processed so far
+	// and the total, see WithProgress
+	Progress func(done, total int)
+}
+
+// WithProgress makes a chunked batch method call fn after each chunk
+// completes, with the cumulative number of transactions processed and the
+// total. fn is always called a final time when the batch ends: after the
+// last chunk with done equal to total, or with the transactions processed
+// before a failed chunk, or with zeros for an empty batch. It is called
+// from the calling goroutine and never while the service holds a lock.
+func WithProgress(fn func(done, total int)) BatchOption {
+	return func(o *BatchOptions) {
+		o.Progress = fn
+	}
+}
+
+// NewBatchOptions applies opts in order
+func NewBatchOptions(opts ...BatchOption) BatchOptions {
+	var o BatchOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// report calls the progress callback, if any
+func (o BatchOptions) report(done, total int) {
+	if o.Progress != nil {
+		o.Progress(done, total)
+	}
+}
+
+// CreateTransactionsChunked creates transactions for a budget with one
+// CreateTransactions request per chunk of chunkSize transactions, merging
+// the summaries. Every payload is validated before the first request.
+// When a chunk fails, the summary of the chunks created so far is returned
+// along with the error.
+// https://api.youneedabudget.com/v1#/Transactions/createTransaction
+func (s *Service) CreateTransactionsChunked(budgetID string, ps []PayloadTransaction,
+	chunkSize int, opts ...BatchOption) (*OperationSummary, error) {
+
+	if err := validatePayloads(s.prepare(ps), PayloadTransaction.Validate); err != nil {
+		return nil, err
+	}
+	return chunked(ps, chunkSize, NewBatchOptions(opts...), func(chunk []PayloadTransaction) (*OperationSummary, error) {
+		return s.CreateTransactions(budgetID, chunk)
+	})
+}
+
+// UpdateTransactionsChunked updates transactions of a budget with one
+// UpdateTransactions request per chunk of chunkSize transactions, merging
+// the summaries. Every payload is validated before the first request.
+// When a chunk fails, the summary of the chunks updated so far is returned
+// along with the error.
+// https://api.youneedabudget.com/v1#/Transactions/updateTransactions
+func (s *Service) UpdateTransactionsChunked(budgetID string, ps []PayloadTransaction,
+	chunkSize int, opts ...BatchOption) (*OperationSummary, error) {
+
+	if err := validatePayloads(s.prepare(ps), PayloadTransaction.ValidateUpdate); err != nil {
+		return nil, err
+	}
+	return chunked(ps, chunkSize, NewBatchOptions(opts...), func(chunk []PayloadTransaction) (*OperationSummary, error) {
+		return s.UpdateTransactions(budgetID, chunk)
+	})
+}
+
+// chunked sends ps through send in chunks of chunkSize, merging the
+// summaries and reporting progress after each chunk and at the end
+func chunked(ps []PayloadTransaction, chunkSize int, o BatchOptions,
+	send func([]PayloadTransaction) (*OperationSummary, error)) (*OperationSummary, error) {
+
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+
+	summary := &OperationSummary{}
+	done := 0
+	defer func() { o.report(done, len(ps)) }()
+
+	for start := 0; start < len(ps); start += chunkSize {
+		end := min(start+chunkSize, len(ps))
+		chunk, err := send(ps[start:end])
+		if err != nil {
+			return summary, fmt.Errorf("chunk %d: %w", start/chunkSize, err)
+		}
+		if chunk != nil {
+			summary.TransactionIDs = append(summary.TransactionIDs, chunk.TransactionIDs...)
+			summary.DuplicateImportIDs = append(summary.DuplicateImportIDs, chunk.DuplicateImportIDs...)
+			summary.Transactions = append(summary.Transactions, chunk.Transactions...)
+		}
+
+		done = end
+		if end < len(ps) {
+			o.report(done, len(ps))
+		}
+	}
+	return summary, nil
+}
@@ -0,0 +1,168 @@
+package transaction_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/jarcoal/httpmock.v1"
+
+	"github.com/coltoneshaw/ynab.go"
+	"github.com/coltoneshaw/ynab.go/api"
+	"github.com/coltoneshaw/ynab.go/api/transaction"
+)
+
+// chunkResponder answers each batch request with the IDs of its
+// transactions, failing the request number failAt when positive
+func chunkResponder(t *testing.T, sizes *[]int, failAt int) httpmock.Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		payload := struct {
+			Transactions []struct {
+				ID       string  `json:"id"`
+				ImportID *string `json:"import_id"`
+			} `json:"transactions"`
+		}{}
+		require.NoError(t, json.Unmarshal(body, &payload))
+
+		*sizes = append(*sizes, len(payload.Transactions))
+		if len(*sizes) == failAt {
+			return httpmock.NewStringResponse(http.StatusInternalServerError,
+				`{"error":{"id":"500","name":"internal_server_error","detail":"Internal Server Error"}}`), nil
+		}
+
+		ids := make([]string, 0, len(payload.Transactions))
+		for _, p := range payload.Transactions {
+			id := p.ID
+			if id == "" {
+				id = *p.ImportID
+			}
+			ids = append(ids, id)
+		}
+		return httpmock.NewJsonResponse(http.StatusOK, map[string]any{
+			"data": map[string]any{"transaction_ids": ids},
+		})
+	}
+}
+
+func chunkPayloads(t *testing.T, n int) []transaction.PayloadTransaction {
+	date, err := api.DateFromString("2024-01-15")
+	require.NoError(t, err)
+
+	ps := make([]transaction.PayloadTransaction, n)
+	for i := range ps {
+		importID := fmt.Sprintf("import-%d", i)
+		ps[i] = transaction.PayloadTransaction{
+			AccountID: "account-id",
+			Date:      date,
+			Amount:    -1000,
+			Cleared:   transaction.ClearingStatusUncleared,
+			ImportID:  &importID,
+		}.WithNewPayee("Supermarket")
+	}
+	return ps
+}
+
+func TestService_CreateTransactionsChunked(t *testing.T) {
+	url := "https://api.youneedabudget.com/v1/budgets/aa248caa/transactions"
+
+	t.Run("reports progress after each chunk", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var sizes []int
+		httpmock.RegisterResponder(http.MethodPost, url, chunkResponder(t, &sizes, 0))
+
+		var progress [][2]int
+		summary, err := ynab.NewClient("").Transaction().CreateTransactionsChunked("aa248caa", chunkPayloads(t, 7), 3,
+			transaction.WithProgress(func(done, total int) {
+				progress = append(progress, [2]int{done, total})
+			}))
+		require.NoError(t, err)
+
+		assert.Equal(t, []int{3, 3, 1}, sizes)
+		assert.Equal(t, [][2]int{{3, 7}, {6, 7}, {7, 7}}, progress)
+		for i := 1; i < len(progress); i++ {
+			assert.Greater(t, progress[i][0], progress[i-1][0])
+		}
+		assert.Len(t, summary.TransactionIDs, 7)
+		assert.Equal(t, "import-0", summary.TransactionIDs[0])
+		assert.Equal(t, "import-6", summary.TransactionIDs[6])
+	})
+
+	t.Run("stops on a failed chunk", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var sizes []int
+		httpmock.RegisterResponder(http.MethodPost, url, chunkResponder(t, &sizes, 2))
+
+		var progress [][2]int
+		summary, err := ynab.NewClient("").Transaction().CreateTransactionsChunked("aa248caa", chunkPayloads(t, 7), 3,
+			transaction.WithProgress(func(done, total int) {
+				progress = append(progress, [2]int{done, total})
+			}))
+		var apiErr *api.Error
+		assert.ErrorAs(t, err, &apiErr)
+		assert.ErrorContains(t, err, "chunk 1:")
+
+		assert.Equal(t, []int{3, 3}, sizes)
+		assert.Equal(t, [][2]int{{3, 7}, {3, 7}}, progress)
+		assert.Len(t, summary.TransactionIDs, 3)
+	})
+
+	t.Run("validates every payload first", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		ps := chunkPayloads(t, 7)
+		ps[5].AccountID = ""
+
+		_, err := ynab.NewClient("").Transaction().CreateTransactionsChunked("aa248caa", ps, 3)
+		assert.ErrorIs(t, err, transaction.ErrAccountIDRequired)
+		assert.ErrorContains(t, err, "transaction 5:")
+		assert.Zero(t, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("empty batch", func(t *testing.T) {
+		var progress [][2]int
+		summary, err := ynab.NewClient("").Transaction().CreateTransactionsChunked("aa248caa", nil, 0,
+			transaction.WithProgress(func(done, total int) {
+				progress = append(progress, [2]int{done, total})
+			}))
+		require.NoError(t, err)
+		assert.Empty(t, summary.TransactionIDs)
+		assert.Equal(t, [][2]int{{0, 0}}, progress)
+	})
+}
+
+func TestService_UpdateTransactionsChunked(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var sizes []int
+	httpmock.RegisterResponder(http.MethodPatch, "https://api.youneedabudget.com/v1/budgets/aa248caa/transactions",
+		chunkResponder(t, &sizes, 0))
+
+	memo := "reviewed"
+	ps := make([]transaction.PayloadTransaction, 5)
+	for i := range ps {
+		ps[i] = transaction.PayloadTransaction{ID: fmt.Sprintf("tx-%d", i), Memo: &memo}
+	}
+
+	var progress [][2]int
+	summary, err := ynab.NewClient("").Transaction().UpdateTransactionsChunked("aa248caa", ps, 2,
+		transaction.WithProgress(func(done, total int) {
+			progress = append(progress, [2]int{done, total})
+		}))
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{2, 2, 1}, sizes)
+	assert.Equal(t, [][2]int{{2, 5}, {4, 5}, {5, 5}}, progress)
+	assert.Equal(t, []string{"tx-0", "tx-1", "tx-2", "tx-3", "tx-4"}, summary.TransactionIDs)
+}
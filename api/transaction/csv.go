@@ -0,0 +1,244 @@
+package transaction
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/coltoneshaw/ynab.go/api"
+	"github.com/coltoneshaw/ynab.go/internal/ascii"
+)
+
+// csvHeader is the YNAB-compatible column order used by WriteCSV
+var csvHeader = []string{"Date", "Payee", "Category", "Memo", "Outflow", "Inflow"}
+
+// CSVOptions configures the CSV encoding of transactions
+type CSVOptions struct {
+	// DecimalDigits the number of decimal digits amounts are formatted with,
+	// usually the budget's CurrencyFormat.DecimalDigits. Zero formats whole units.
+	DecimalDigits uint64
+	// SplitSubTransactions emits one row per sub-transaction for split
+	// transactions instead of a single row for the parent
+	SplitSubTransactions bool
+}
+
+// DefaultCSVOptions formats amounts with two decimal digits and keeps
+// split transactions on a single row
+var DefaultCSVOptions = CSVOptions{DecimalDigits: 2}
+
+// WriteCSV writes the transactions to w as a YNAB-compatible CSV with the
+// columns Date, Payee, Category, Memo, Outflow and Inflow. Amounts are
+// split by sign into the Outflow and Inflow columns.
+func WriteCSV(w io.Writer, txs []*Transaction, opts CSVOptions) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, t := range txs {
+		for _, row := range csvRows(t, opts) {
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvRows returns the CSV rows for a single transaction
+func csvRows(t *Transaction, opts CSVOptions) [][]string {
+	date := api.DateFormat(t.Date)
+
+	if !opts.SplitSubTransactions || len(t.SubTransactions) == 0 {
+		return [][]string{
+			csvRow(date, t.PayeeName, t.CategoryName, t.Memo, int64(t.Amount), opts.DecimalDigits),
+		}
+	}
+
+	rows := make([][]string, 0, len(t.SubTransactions))
+	for _, sub := range t.SubTransactions {
+		if sub.Deleted {
+			continue
+		}
+
+		payeeName := sub.PayeeName
+		if payeeName == nil {
+			payeeName = t.PayeeName
+		}
+		rows = append(rows, csvRow(date, payeeName, sub.CategoryName, sub.Memo, int64(sub.Amount), opts.DecimalDigits))
+	}
+	return rows
+}
+
+func csvRow(date string, payee, category, memo *string, amount int64, digits uint64) []string {
+	var outflow, inflow string
+	if amount < 0 {
+		outflow = formatMilliunits(-amount, digits)
+	} else {
+		inflow = formatMilliunits(amount, digits)
+	}
+	return []string{date, stringValue(payee), stringValue(category), stringValue(memo), outflow, inflow}
+}
+
+// ParseCSV reads a YNAB-format CSV with the columns Date, Payee, Category,
+// Memo, Outflow and Inflow and returns one payload per row for accountID.
+// Columns are matched by header name, case-insensitively; Date, Payee,
+// Outflow and Inflow are required. Outflow and Inflow are unsigned decimal
+// amounts combined into a single signed milliunit Amount, and dates must be
+// formatted as YYYY-MM-DD. The payee is kept as PayeeName so the API can
+// resolve it, and a row without one fails with ErrPayeeRequired, so every
+// payload returned passes Validate; category names cannot be resolved from
+// the CSV and are ignored, leaving CategoryID unset.
+func ParseCSV(r io.Reader, accountID string) ([]PayloadTransaction, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("csv: missing header")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("csv: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"date", "payee", "outflow", "inflow"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("csv: missing required column %q", name)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	payloads := make([]PayloadTransaction, 0)
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("csv: %w", err)
+		}
+		line, _ := cr.FieldPos(0)
+
+		date, err := api.DateFromString(field(record, "date"))
+		if err != nil {
+			return nil, fmt.Errorf("csv: line %d: invalid date %q: %w", line, field(record, "date"), err)
+		}
+
+		outflow, err := parseMilliunits(field(record, "outflow"))
+		if err != nil {
+			return nil, fmt.Errorf("csv: line %d: invalid outflow %q: %w", line, field(record, "outflow"), err)
+		}
+
+		inflow, err := parseMilliunits(field(record, "inflow"))
+		if err != nil {
+			return nil, fmt.Errorf("csv: line %d: invalid inflow %q: %w", line, field(record, "inflow"), err)
+		}
+
+		p := PayloadTransaction{
+			AccountID: accountID,
+			Date:      date,
+			Amount:    inflow - outflow,
+			Cleared:   ClearingStatusUncleared,
+			PayeeName: optionalString(field(record, "payee")),
+			Memo:      optionalString(field(record, "memo")),
+		}
+		if err := p.Validate(); err != nil {
+			return nil, fmt.Errorf("csv: line %d: %w", line, err)
+		}
+		payloads = append(payloads, p)
+	}
+
+	return payloads, nil
+}
+
+// parseMilliunits parses an unsigned decimal amount with at most three
+// decimal digits into milliunits. An empty string parses as zero.
+func parseMilliunits(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return 0, fmt.Errorf("no digits")
+	}
+	if !ascii.IsDigits(whole) || !ascii.IsDigits(frac) {
+		return 0, fmt.Errorf("not a decimal number")
+	}
+	if len(frac) > 3 {
+		return 0, fmt.Errorf("more than 3 decimal digits")
+	}
+
+	var units, milli int64
+	var err error
+	if whole != "" {
+		if units, err = strconv.ParseInt(whole, 10, 64); err != nil || units > math.MaxInt64/1000-1 {
+			return 0, fmt.Errorf("out of range")
+		}
+	}
+	if frac != "" {
+		if milli, err = strconv.ParseInt(frac+strings.Repeat("0", 3-len(frac)), 10, 64); err != nil {
+			return 0, fmt.Errorf("not a decimal number")
+		}
+	}
+	return units*1000 + milli, nil
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// formatMilliunits formats a non-negative milliunit amount as a decimal
+// string with the given number of decimal digits, rounding half up
+func formatMilliunits(amount int64, digits uint64) string {
+	var value int64
+	if digits <= 3 {
+		div := pow10(3 - digits)
+		value = (amount + div/2) / div
+	} else {
+		value = amount * pow10(digits-3)
+	}
+
+	if digits == 0 {
+		return fmt.Sprintf("%d", value)
+	}
+
+	scale := pow10(digits)
+	frac := fmt.Sprintf("%d", value%scale)
+	return fmt.Sprintf("%d.%s%s", value/scale, strings.Repeat("0", int(digits)-len(frac)), frac)
+}
+
+func pow10(n uint64) int64 {
+	p := int64(1)
+	for i := uint64(0); i < n; i++ {
+		p *= 10
+	}
+	return p
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,215 @@
+package transaction_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coltoneshaw/ynab.go/api"
+	"github.com/coltoneshaw/ynab.go/api/transaction"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func csvTestTransactions(t *testing.T) []*transaction.Transaction {
+	date, err := api.DateFromString("2018-03-10")
+	assert.NoError(t, err)
+
+	return []*transaction.Transaction{
+		{
+			ID:           "e6ad88f5-6f16-4480-9515-5377012750dd",
+			Date:         date,
+			Amount:       -43950,
+			PayeeName:    strPtr("Supermarket"),
+			CategoryName: strPtr("Groceries"),
+			Memo:         strPtr("Weekly, big shop"),
+		},
+		{
+			ID:           "9b1ad8ea-b3f1-4f4c-8a4c-5b3a5fd0c75e",
+			Date:         date,
+			Amount:       2500000,
+			PayeeName:    strPtr("Employer"),
+			CategoryName: strPtr("Inflow: Ready to Assign"),
+		},
+		{
+			ID:           "5bd56248-a6ee-4e2a-ac9b-d46a8b4b1d0e",
+			Date:         date,
+			Amount:       -120005,
+			PayeeName:    strPtr("Department Store"),
+			CategoryName: strPtr("Split (Multiple Categories)..."),
+			SubTransactions: []*transaction.SubTransaction{
+				{
+					ID:           "a1",
+					Amount:       -100005,
+					CategoryName: strPtr("Clothing"),
+					Memo:         strPtr("Shoes"),
+				},
+				{
+					ID:           "a2",
+					Amount:       -20000,
+					PayeeName:    strPtr("Gift Shop"),
+					CategoryName: strPtr("Gifts"),
+				},
+				{
+					ID:      "a3",
+					Amount:  -5000,
+					Deleted: true,
+				},
+			},
+		},
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	t.Run("default options", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := transaction.WriteCSV(&buf, csvTestTransactions(t), transaction.DefaultCSVOptions)
+		assert.NoError(t, err)
+
+		expected := `Date,Payee,Category,Memo,Outflow,Inflow
+2018-03-10,Supermarket,Groceries,"Weekly, big shop",43.95,
+2018-03-10,Employer,Inflow: Ready to Assign,,,2500.00
+2018-03-10,Department Store,Split (Multiple Categories)...,,120.01,
+`
+		assert.Equal(t, expected, buf.String())
+	})
+
+	t.Run("split sub-transactions", func(t *testing.T) {
+		var buf bytes.Buffer
+		opts := transaction.CSVOptions{DecimalDigits: 2, SplitSubTransactions: true}
+		err := transaction.WriteCSV(&buf, csvTestTransactions(t)[2:], opts)
+		assert.NoError(t, err)
+
+		expected := `Date,Payee,Category,Memo,Outflow,Inflow
+2018-03-10,Department Store,Clothing,Shoes,100.01,
+2018-03-10,Gift Shop,Gifts,,20.00,
+`
+		assert.Equal(t, expected, buf.String())
+	})
+
+	t.Run("currency decimal digits", func(t *testing.T) {
+		txs := csvTestTransactions(t)[:1]
+
+		var buf bytes.Buffer
+		err := transaction.WriteCSV(&buf, txs, transaction.CSVOptions{DecimalDigits: 0})
+		assert.NoError(t, err)
+		assert.Equal(t, "Date,Payee,Category,Memo,Outflow,Inflow\n2018-03-10,Supermarket,Groceries,\"Weekly, big shop\",44,\n", buf.String())
+
+		buf.Reset()
+		err = transaction.WriteCSV(&buf, txs, transaction.CSVOptions{DecimalDigits: 3})
+		assert.NoError(t, err)
+		assert.Equal(t, "Date,Payee,Category,Memo,Outflow,Inflow\n2018-03-10,Supermarket,Groceries,\"Weekly, big shop\",43.950,\n", buf.String())
+	})
+}
+
+func TestParseCSV(t *testing.T) {
+	t.Run("valid file", func(t *testing.T) {
+		input := `Date,Payee,Category,Memo,Outflow,Inflow
+2018-03-10,Supermarket,Groceries,"Weekly, big shop",43.95,
+2018-03-11,Employer,Inflow: Ready to Assign,,,2500
+2018-03-12,Refund Store,,,.5,1.25
+`
+		payloads, err := transaction.ParseCSV(strings.NewReader(input), "account-id")
+		assert.NoError(t, err)
+		assert.Len(t, payloads, 3)
+
+		date, err := api.DateFromString("2018-03-10")
+		assert.NoError(t, err)
+		assert.Equal(t, transaction.PayloadTransaction{
+			AccountID: "account-id",
+			Date:      date,
+			Amount:    -43950,
+			Cleared:   transaction.ClearingStatusUncleared,
+			PayeeName: strPtr("Supermarket"),
+			Memo:      strPtr("Weekly, big shop"),
+		}, payloads[0])
+
+		assert.Equal(t, int64(2500000), payloads[1].Amount)
+		assert.Nil(t, payloads[1].Memo)
+		assert.Nil(t, payloads[1].CategoryID)
+		assert.Equal(t, int64(750), payloads[2].Amount)
+	})
+
+	t.Run("round trip with WriteCSV", func(t *testing.T) {
+		var buf bytes.Buffer
+		txs := csvTestTransactions(t)
+		assert.NoError(t, transaction.WriteCSV(&buf, txs[:2], transaction.DefaultCSVOptions))
+
+		payloads, err := transaction.ParseCSV(&buf, "account-id")
+		assert.NoError(t, err)
+		assert.Len(t, payloads, 2)
+		assert.Equal(t, int64(txs[0].Amount), payloads[0].Amount)
+		assert.Equal(t, int64(txs[1].Amount), payloads[1].Amount)
+	})
+
+	t.Run("empty payee", func(t *testing.T) {
+		input := "Date,Payee,Category,Memo,Outflow,Inflow\n2018-03-10,Shop,,,1.00,\n2018-03-10,,Groceries,,10.00,\n"
+		payloads, err := transaction.ParseCSV(strings.NewReader(input), "account-id")
+		assert.Nil(t, payloads)
+		assert.ErrorIs(t, err, transaction.ErrPayeeRequired)
+		assert.ErrorContains(t, err, "csv: line 3:")
+	})
+
+	t.Run("payloads pass validation", func(t *testing.T) {
+		input := "Date,Payee,Category,Memo,Outflow,Inflow\n2018-03-10,Shop,,,10.00,\n2018-03-11,Employer,,,,2500\n"
+		payloads, err := transaction.ParseCSV(strings.NewReader(input), "account-id")
+		require.NoError(t, err)
+		for _, p := range payloads {
+			assert.NoError(t, p.Validate())
+		}
+	})
+
+	t.Run("malformed amount", func(t *testing.T) {
+		input := "Date,Payee,Category,Memo,Outflow,Inflow\n2018-03-10,Shop,,,1.00,\n2018-03-11,Shop,,,12.3x,\n"
+		payloads, err := transaction.ParseCSV(strings.NewReader(input), "account-id")
+		assert.Nil(t, payloads)
+		assert.EqualError(t, err, `csv: line 3: invalid outflow "12.3x": not a decimal number`)
+	})
+
+	t.Run("signs and malformed fractions", func(t *testing.T) {
+		for amount, want := range map[string]string{
+			"1.+5":  "not a decimal number",
+			"1.-5":  "not a decimal number",
+			"-5.00": "not a decimal number",
+			"+5.00": "not a decimal number",
+			"1 000": "not a decimal number",
+			".":     "no digits",
+		} {
+			input := "Date,Payee,Outflow,Inflow\n2018-03-10,Shop," + amount + ",\n"
+			_, err := transaction.ParseCSV(strings.NewReader(input), "account-id")
+			assert.EqualError(t, err, `csv: line 2: invalid outflow "`+amount+`": `+want, amount)
+
+			input = "Date,Payee,Outflow,Inflow\n2018-03-10,Shop,," + amount + "\n"
+			_, err = transaction.ParseCSV(strings.NewReader(input), "account-id")
+			assert.EqualError(t, err, `csv: line 2: invalid inflow "`+amount+`": `+want, amount)
+		}
+	})
+
+	t.Run("too many decimal digits", func(t *testing.T) {
+		input := "Date,Payee,Outflow,Inflow\n2018-03-10,Shop,,1.2345\n"
+		_, err := transaction.ParseCSV(strings.NewReader(input), "account-id")
+		assert.EqualError(t, err, `csv: line 2: invalid inflow "1.2345": more than 3 decimal digits`)
+	})
+
+	t.Run("malformed date", func(t *testing.T) {
+		input := "Date,Payee,Outflow,Inflow\n03/10/2018,Shop,1.00,\n"
+		_, err := transaction.ParseCSV(strings.NewReader(input), "account-id")
+		assert.ErrorContains(t, err, `csv: line 2: invalid date "03/10/2018"`)
+	})
+
+	t.Run("missing required column", func(t *testing.T) {
+		input := "Date,Payee,Outflow\n2018-03-10,Shop,1.00\n"
+		_, err := transaction.ParseCSV(strings.NewReader(input), "account-id")
+		assert.EqualError(t, err, `csv: missing required column "inflow"`)
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		_, err := transaction.ParseCSV(strings.NewReader(""), "account-id")
+		assert.EqualError(t, err, "csv: missing header")
+	})
+}
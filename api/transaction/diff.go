@@ -0,0 +1,131 @@
+package transaction
+
+import "reflect"
+
+// TransactionDiff is the difference between two snapshots of transactions
+type TransactionDiff struct {
+	// Added holds the transactions of the after snapshot missing from before
+	Added []*Transaction
+	// Removed holds the transactions of the before snapshot missing from
+	// after or marked as deleted in it
+	Removed []*Transaction
+	// Changed holds the transactions present in both snapshots whose fields
+	// differ
+	Changed []TransactionChange
+}
+
+// TransactionChange describes a transaction present in both snapshots
+type TransactionChange struct {
+	ID     string
+	Before *Transaction
+	After  *Transaction
+	// Fields lists the fields that differ, in the order of the Transaction
+	// struct
+	Fields []FieldChange
+}
+
+// FieldChange is the value of a field before and after a change. Field is
+// the JSON name of the field, and pointer fields are dereferenced so an
+// unset value is nil.
+type FieldChange struct {
+	Field  string
+	Before any
+	After  any
+}
+
+// Diff compares two snapshots of transactions, matching them by ID.
+// A transaction marked as deleted in after counts as removed, and deleted
+// transactions in before are ignored. Added and Changed follow the order of
+// after, Removed the order of before.
+func Diff(before, after []*Transaction) TransactionDiff {
+	previous := make(map[string]*Transaction, len(before))
+	for _, t := range before {
+		if t != nil && !t.Deleted {
+			previous[t.ID] = t
+		}
+	}
+
+	var diff TransactionDiff
+	current := make(map[string]bool, len(after))
+	for _, t := range after {
+		if t == nil || t.Deleted {
+			continue
+		}
+		current[t.ID] = true
+
+		old, ok := previous[t.ID]
+		if !ok {
+			diff.Added = append(diff.Added, t)
+			continue
+		}
+		if fields := diffFields(old, t); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, TransactionChange{ID: t.ID, Before: old, After: t, Fields: fields})
+		}
+	}
+
+	for _, t := range before {
+		if t != nil && !t.Deleted && !current[t.ID] {
+			diff.Removed = append(diff.Removed, t)
+		}
+	}
+	return diff
+}
+
+// diffFields returns the fields of a and b holding different values
+func diffFields(a, b *Transaction) []FieldChange {
+	var fields []FieldChange
+	add := func(field string, before, after any) {
+		if !reflect.DeepEqual(before, after) {
+			fields = append(fields, FieldChange{Field: field, Before: before, After: after})
+		}
+	}
+
+	if !a.Date.Equal(b.Date.Time) {
+		fields = append(fields, FieldChange{Field: "date", Before: a.Date, After: b.Date})
+	}
+	add("amount", a.Amount, b.Amount)
+	add("cleared", a.Cleared, b.Cleared)
+	add("approved", a.Approved, b.Approved)
+	add("account_id", a.AccountID, b.AccountID)
+	add("account_name", a.AccountName, b.AccountName)
+	if !subTransactionsEqual(a.SubTransactions, b.SubTransactions) {
+		fields = append(fields, FieldChange{Field: "subtransactions", Before: a.SubTransactions, After: b.SubTransactions})
+	}
+	add("memo", deref(a.Memo), deref(b.Memo))
+	add("flag_color", deref(a.FlagColor), deref(b.FlagColor))
+	add("flag_name", deref(a.FlagName), deref(b.FlagName))
+	add("payee_id", deref(a.PayeeID), deref(b.PayeeID))
+	add("category_id", deref(a.CategoryID), deref(b.CategoryID))
+	add("transfer_account_id", deref(a.TransferAccountID), deref(b.TransferAccountID))
+	add("transfer_transaction_id", deref(a.TransferTransactionID), deref(b.TransferTransactionID))
+	add("matched_transaction_id", deref(a.MatchedTransactionID), deref(b.MatchedTransactionID))
+	add("import_id", deref(a.ImportID), deref(b.ImportID))
+	add("import_payee_name", deref(a.ImportPayeeName), deref(b.ImportPayeeName))
+	add("import_payee_name_original", deref(a.ImportPayeeNameOriginal), deref(b.ImportPayeeNameOriginal))
+	add("debt_transaction_type", deref(a.DebtTransactionType), deref(b.DebtTransactionType))
+	add("payee_name", deref(a.PayeeName), deref(b.PayeeName))
+	add("category_name", deref(a.CategoryName), deref(b.CategoryName))
+	return fields
+}
+
+// subTransactionsEqual reports whether a and b hold equal subtransactions
+// in the same order, a nil slice being equal to an empty one
+func subTransactionsEqual(a, b []*SubTransaction) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !reflect.DeepEqual(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// deref returns the value p points to, or nil when p is nil
+func deref[T any](p *T) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
@@ -0,0 +1,87 @@
+package transaction_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coltoneshaw/ynab.go/api"
+	"github.com/coltoneshaw/ynab.go/api/transaction"
+)
+
+func TestDiff(t *testing.T) {
+	groceries, dining := "cat-groceries", "cat-dining"
+
+	unchanged := balanceTx(t, "tx-1", "2024-01-01", -1000)
+	removed := balanceTx(t, "tx-2", "2024-01-02", -2000)
+	deleted := balanceTx(t, "tx-3", "2024-01-03", -3000)
+	changed := balanceTx(t, "tx-4", "2024-01-04", -4000)
+	changed.Cleared = transaction.ClearingStatusUncleared
+	changed.CategoryID = &groceries
+
+	deletedAfter := *deleted
+	deletedAfter.Deleted = true
+	changedAfter := *changed
+	changedAfter.Amount = -4500
+	changedAfter.Cleared = transaction.ClearingStatusCleared
+	changedAfter.CategoryID = &dining
+	unchangedAfter := *unchanged
+	added := balanceTx(t, "tx-5", "2024-01-05", -5000)
+
+	diff := transaction.Diff(
+		[]*transaction.Transaction{unchanged, removed, deleted, changed},
+		[]*transaction.Transaction{&unchangedAfter, &deletedAfter, &changedAfter, added},
+	)
+
+	assert.Equal(t, []*transaction.Transaction{added}, diff.Added)
+	assert.Equal(t, []*transaction.Transaction{removed, deleted}, diff.Removed)
+
+	require.Len(t, diff.Changed, 1)
+	change := diff.Changed[0]
+	assert.Equal(t, "tx-4", change.ID)
+	assert.Same(t, changed, change.Before)
+	assert.Same(t, &changedAfter, change.After)
+	assert.Equal(t, []transaction.FieldChange{
+		{Field: "amount", Before: api.Milliunits(-4000), After: api.Milliunits(-4500)},
+		{Field: "cleared", Before: transaction.ClearingStatusUncleared, After: transaction.ClearingStatusCleared},
+		{Field: "category_id", Before: groceries, After: dining},
+	}, change.Fields)
+}
+
+func TestDiff_NilAndDateFields(t *testing.T) {
+	memo := "weekly shop"
+	before := balanceTx(t, "tx-1", "2024-01-01", -1000)
+	after := balanceTx(t, "tx-1", "2024-01-02", -1000)
+	after.Memo = &memo
+
+	diff := transaction.Diff([]*transaction.Transaction{before}, []*transaction.Transaction{after})
+	require.Len(t, diff.Changed, 1)
+	fields := diff.Changed[0].Fields
+	require.Len(t, fields, 2)
+	assert.Equal(t, "date", fields[0].Field)
+	assert.Equal(t, transaction.FieldChange{Field: "memo", Before: nil, After: memo}, fields[1])
+
+	assert.Empty(t, transaction.Diff(nil, nil))
+}
+
+func TestDiff_SubTransactions(t *testing.T) {
+	groceries, dining := "cat-groceries", "cat-dining"
+
+	before := balanceTx(t, "tx-1", "2024-01-01", -1000)
+	after := balanceTx(t, "tx-1", "2024-01-01", -1000)
+	before.SubTransactions = nil
+	after.SubTransactions = []*transaction.SubTransaction{}
+	assert.Empty(t, transaction.Diff([]*transaction.Transaction{before}, []*transaction.Transaction{after}).Changed)
+
+	before.SubTransactions = []*transaction.SubTransaction{{ID: "sub-1", Amount: -1000, CategoryID: &groceries}}
+	after.SubTransactions = []*transaction.SubTransaction{{ID: "sub-1", Amount: -1000, CategoryID: &groceries}}
+	assert.Empty(t, transaction.Diff([]*transaction.Transaction{before}, []*transaction.Transaction{after}).Changed)
+
+	after.SubTransactions = []*transaction.SubTransaction{{ID: "sub-1", Amount: -1000, CategoryID: &dining}}
+	diff := transaction.Diff([]*transaction.Transaction{before}, []*transaction.Transaction{after})
+	require.Len(t, diff.Changed, 1)
+	assert.Equal(t, []transaction.FieldChange{
+		{Field: "subtransactions", Before: before.SubTransactions, After: after.SubTransactions},
+	}, diff.Changed[0].Fields)
+}
@@ -1,14 +1,18 @@
 // Package transaction implements transaction entities and services
 package transaction // import "github.com/coltoneshaw/ynab.go/api/transaction"
 
-import "github.com/coltoneshaw/ynab.go/api"
+import (
+	"time"
+
+	"github.com/coltoneshaw/ynab.go/api"
+)
 
 // Transaction represents a full transaction for a budget
 type Transaction struct {
 	ID   string   `json:"id"`
 	Date api.Date `json:"date"`
 	// Amount Transaction amount in milliunits format
-	Amount    int64          `json:"amount"`
+	Amount    api.Milliunits `json:"amount"`
 	Cleared   ClearingStatus `json:"cleared"`
 	Approved  bool           `json:"approved"`
 	AccountID string         `json:"account_id"`
@@ -41,12 +45,118 @@ type Transaction struct {
 	CategoryName            *string              `json:"category_name"`
 }
 
+// HasUnknownEnums returns the JSON names of the fields holding an enum
+// value this package does not recognize, such as a flag color added to the
+// API after its release. It helps detect API drift; the values themselves
+// are preserved and sent back unchanged.
+func (t *Transaction) HasUnknownEnums() []string {
+	var fields []string
+	if !t.Cleared.IsValid() {
+		fields = append(fields, "cleared")
+	}
+	if t.FlagColor != nil && !t.FlagColor.IsValid() {
+		fields = append(fields, "flag_color")
+	}
+	if t.DebtTransactionType != nil && !t.DebtTransactionType.IsValid() {
+		fields = append(fields, "debt_transaction_type")
+	}
+	return fields
+}
+
+// ToPayload returns an update-ready payload carrying the transaction ID and
+// a deep copy of its editable fields, including the subtransactions that
+// are not deleted
+func (t *Transaction) ToPayload() PayloadTransaction {
+	p := PayloadTransaction{
+		ID:         t.ID,
+		AccountID:  t.AccountID,
+		Date:       t.Date,
+		Amount:     int64(t.Amount),
+		Cleared:    t.Cleared,
+		Approved:   t.Approved,
+		PayeeID:    clonePtr(t.PayeeID),
+		PayeeName:  clonePtr(t.PayeeName),
+		CategoryID: clonePtr(t.CategoryID),
+		Memo:       clonePtr(t.Memo),
+		FlagColor:  clonePtr(t.FlagColor),
+		ImportID:   clonePtr(t.ImportID),
+	}
+
+	for _, sub := range t.SubTransactions {
+		if sub == nil || sub.Deleted {
+			continue
+		}
+		p.SubTransactions = append(p.SubTransactions, &PayloadSubTransaction{
+			ID:         sub.ID,
+			Amount:     int64(sub.Amount),
+			PayeeID:    clonePtr(sub.PayeeID),
+			PayeeName:  clonePtr(sub.PayeeName),
+			CategoryID: clonePtr(sub.CategoryID),
+			Memo:       clonePtr(sub.Memo),
+		})
+	}
+
+	return p
+}
+
+// IsSplit returns true if the transaction is split across categories, its
+// categorization then lives in its subtransactions
+func (t *Transaction) IsSplit() bool {
+	return len(t.SubTransactions) > 0
+}
+
+// Flatten returns one transaction per subtransaction of a split, so
+// reports can treat splits like any other transaction. Each child carries
+// the ID, amount, memo, category and transfer of its subtransaction, and
+// the date, account, status and flag of the parent; the payee falls back
+// to the parent's when the subtransaction has none. Deleted subtransactions
+// are skipped. Non-split transactions are returned on their own.
+func (t *Transaction) Flatten() []*Transaction {
+	children := make([]*Transaction, 0, len(t.SubTransactions))
+	for _, sub := range t.SubTransactions {
+		if sub == nil || sub.Deleted {
+			continue
+		}
+
+		child := &Transaction{
+			ID:                    sub.ID,
+			Date:                  t.Date,
+			Amount:                sub.Amount,
+			Cleared:               t.Cleared,
+			Approved:              t.Approved,
+			AccountID:             t.AccountID,
+			Deleted:               t.Deleted,
+			AccountName:           t.AccountName,
+			Memo:                  clonePtr(sub.Memo),
+			FlagColor:             clonePtr(t.FlagColor),
+			FlagName:              clonePtr(t.FlagName),
+			PayeeID:               clonePtr(t.PayeeID),
+			PayeeName:             clonePtr(t.PayeeName),
+			CategoryID:            clonePtr(sub.CategoryID),
+			CategoryName:          clonePtr(sub.CategoryName),
+			TransferAccountID:     clonePtr(sub.TransferAccountID),
+			TransferTransactionID: clonePtr(sub.TransferTransactionID),
+			ImportID:              clonePtr(t.ImportID),
+		}
+		if sub.PayeeID != nil || sub.PayeeName != nil {
+			child.PayeeID = clonePtr(sub.PayeeID)
+			child.PayeeName = clonePtr(sub.PayeeName)
+		}
+		children = append(children, child)
+	}
+
+	if len(children) == 0 {
+		return []*Transaction{t}
+	}
+	return children
+}
+
 // Summary represents the summary of a transaction for a budget
 type Summary struct {
 	ID   string   `json:"id"`
 	Date api.Date `json:"date"`
 	// Amount Transaction amount in milliunits format
-	Amount    int64          `json:"amount"`
+	Amount    api.Milliunits `json:"amount"`
 	Cleared   ClearingStatus `json:"cleared"`
 	Approved  bool           `json:"approved"`
 	AccountID string         `json:"account_id"`
@@ -81,7 +191,7 @@ type SubTransaction struct {
 	ID            string `json:"id"`
 	TransactionID string `json:"transaction_id"`
 	// Amount sub-transaction amount in milliunits format
-	Amount int64 `json:"amount"`
+	Amount api.Milliunits `json:"amount"`
 	// Deleted Deleted sub-transactions will only be included in delta requests.
 	Deleted bool `json:"deleted"`
 
@@ -101,7 +211,7 @@ type Hybrid struct {
 	ID   string   `json:"id"`
 	Date api.Date `json:"date"`
 	// Amount Transaction amount in milliunits format
-	Amount      int64          `json:"amount"`
+	Amount      api.Milliunits `json:"amount"`
 	Cleared     ClearingStatus `json:"cleared"`
 	Approved    bool           `json:"approved"`
 	AccountID   string         `json:"account_id"`
@@ -144,8 +254,8 @@ type Scheduled struct {
 	DateNext  api.Date           `json:"date_next"`
 	Frequency ScheduledFrequency `json:"frequency"`
 	// Amount The scheduled transaction amount in milliunits format
-	Amount    int64  `json:"amount"`
-	AccountID string `json:"account_id"`
+	Amount    api.Milliunits `json:"amount"`
+	AccountID string         `json:"account_id"`
 	// Deleted Deleted scheduled transactions will only be included in delta requests.
 	Deleted         bool                       `json:"deleted"`
 	AccountName     string                     `json:"account_name"`
@@ -168,6 +278,181 @@ type Scheduled struct {
 	CategoryName            *string              `json:"category_name"`
 }
 
+// ToPayload returns an update-ready payload carrying a deep copy of the
+// editable fields of the scheduled transaction, dated on its next
+// occurrence. The payload has no room for subtransactions.
+func (s *Scheduled) ToPayload() PayloadScheduledTransaction {
+	return PayloadScheduledTransaction{
+		AccountID:  s.AccountID,
+		Date:       s.DateNext,
+		Amount:     int64(s.Amount),
+		Frequency:  s.Frequency,
+		PayeeID:    clonePtr(s.PayeeID),
+		PayeeName:  clonePtr(s.PayeeName),
+		CategoryID: clonePtr(s.CategoryID),
+		Memo:       clonePtr(s.Memo),
+		FlagColor:  clonePtr(s.FlagColor),
+	}
+}
+
+// DaysUntilNext returns the number of calendar days from the given date
+// until the next occurrence of the scheduled transaction, negative when
+// DateNext is already in the past
+func (s *Scheduled) DaysUntilNext(from api.Date) int {
+	return int(calendarDay(s.DateNext).Sub(calendarDay(from)).Hours() / 24)
+}
+
+// FilterDueWithin returns the scheduled transactions whose next occurrence
+// falls within the given number of days from the given date, in their
+// original order. Past due ones are included since they are still owed,
+// deleted ones are not. One-time (FrequencyNever) and repeating schedules
+// alike are judged by DateNext, as the API advances it past every entered
+// occurrence.
+func FilterDueWithin(scheduled []*Scheduled, from api.Date, days int) []*Scheduled {
+	var due []*Scheduled
+	for _, st := range scheduled {
+		if st == nil || st.Deleted || st.DateNext.IsZero() {
+			continue
+		}
+		if st.DaysUntilNext(from) <= days {
+			due = append(due, st)
+		}
+	}
+	return due
+}
+
+// NextOccurrences projects the next count dates the scheduled transaction
+// fires on, starting with DateNext. Monthly based frequencies keep the day
+// of DateNext, clamped to the length of shorter months, so a schedule on
+// the 31st falls on the last day of February. FrequencyTwiceAMonth fires
+// on the day of DateNext and 15 days apart from it within each month.
+// FrequencyNever and unknown frequencies yield DateNext alone.
+func (s *Scheduled) NextOccurrences(count int) []api.Date {
+	if count <= 0 || s.DateNext.IsZero() {
+		return nil
+	}
+
+	start := calendarDay(s.DateNext)
+	if s.Frequency == FrequencyTwiceAMonth {
+		return twiceAMonthOccurrences(start, count)
+	}
+
+	days, months := frequencyStep(s.Frequency)
+	if days == 0 && months == 0 {
+		return []api.Date{{Time: start}}
+	}
+
+	occurrences := make([]api.Date, count)
+	for i := range occurrences {
+		if months > 0 {
+			occurrences[i] = api.Date{Time: addMonthsClamped(start, start.Day(), i*months)}
+		} else {
+			occurrences[i] = api.Date{Time: start.AddDate(0, 0, i*days)}
+		}
+	}
+	return occurrences
+}
+
+// OccurrencesBetween returns the dates the scheduled transaction fires on
+// from one date to another, both included, as projected by NextOccurrences
+func (s *Scheduled) OccurrencesBetween(from, to api.Date) []api.Date {
+	if s.DateNext.IsZero() {
+		return nil
+	}
+
+	start, first, last := calendarDay(s.DateNext), calendarDay(from), calendarDay(to)
+	if last.Before(start) {
+		return nil
+	}
+
+	// Bound the projection by the number of intervals up to the last date
+	// rather than projecting one date per day
+	daySpan := int(last.Sub(start).Hours() / 24)
+	monthSpan := (last.Year()-start.Year())*12 + int(last.Month()-start.Month())
+	count := 1
+	days, months := frequencyStep(s.Frequency)
+	switch {
+	case s.Frequency == FrequencyTwiceAMonth:
+		count = 2 * (monthSpan + 1)
+	case days > 0:
+		count = daySpan/days + 1
+	case months > 0:
+		count = monthSpan/months + 1
+	}
+
+	var occurrences []api.Date
+	for _, d := range s.NextOccurrences(count) {
+		if !d.Before(first) && !d.After(last) {
+			occurrences = append(occurrences, d)
+		}
+	}
+	return occurrences
+}
+
+// frequencyStep returns the interval between occurrences of a frequency in
+// either days or months, both zero for a frequency that does not repeat
+func frequencyStep(f ScheduledFrequency) (days, months int) {
+	switch f {
+	case FrequencyDaily:
+		return 1, 0
+	case FrequencyWeekly:
+		return 7, 0
+	case FrequencyEveryOtherWeek:
+		return 14, 0
+	case FrequencyEveryFourWeeks:
+		return 28, 0
+	case FrequencyMonthly:
+		return 0, 1
+	case FrequencyEveryOtherMonth:
+		return 0, 2
+	case FrequencyEveryThreeMonths:
+		return 0, 3
+	case FrequencyEveryFourMonths:
+		return 0, 4
+	case FrequencyTwiceAYear:
+		return 0, 6
+	case FrequencyYearly:
+		return 0, 12
+	}
+	return 0, 0
+}
+
+// twiceAMonthOccurrences returns count dates from start on the pair of
+// days of the month 15 days apart that includes the day of start
+func twiceAMonthOccurrences(start time.Time, count int) []api.Date {
+	first, second := start.Day(), start.Day()+15
+	if first > 15 {
+		first, second = first-15, first
+	}
+
+	occurrences := make([]api.Date, 0, count)
+	for month := 0; len(occurrences) < count; month++ {
+		for _, day := range []int{first, second} {
+			date := addMonthsClamped(start, day, month)
+			if date.Before(start) || len(occurrences) == count {
+				continue
+			}
+			occurrences = append(occurrences, api.Date{Time: date})
+		}
+	}
+	return occurrences
+}
+
+// addMonthsClamped returns the given day of the month n months after t,
+// clamped to the last day of that month
+func addMonthsClamped(t time.Time, day, n int) time.Time {
+	firstOfMonth := time.Date(t.Year(), t.Month()+time.Month(n), 1, 0, 0, 0, 0, time.UTC)
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	return firstOfMonth.AddDate(0, 0, min(day, lastDay)-1)
+}
+
+// calendarDay returns the date at midnight UTC, so day differences are not
+// affected by the time of day or daylight saving changes
+func calendarDay(d api.Date) time.Time {
+	year, month, day := d.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
 // ScheduledSummary represents the summary of a scheduled transaction for a budget
 type ScheduledSummary struct {
 	ID        string             `json:"id"`
@@ -175,8 +460,8 @@ type ScheduledSummary struct {
 	DateNext  api.Date           `json:"date_next"`
 	Frequency ScheduledFrequency `json:"frequency"`
 	// Amount The scheduled transaction amount in milliunits format
-	Amount    int64  `json:"amount"`
-	AccountID string `json:"account_id"`
+	Amount    api.Milliunits `json:"amount"`
+	AccountID string         `json:"account_id"`
 	// Deleted Deleted scheduled transactions will only be included in delta requests.
 	Deleted bool `json:"deleted"`
 
@@ -201,7 +486,7 @@ type ScheduledSubTransaction struct {
 	ID                     string `json:"id"`
 	ScheduledTransactionID string `json:"scheduled_transaction_id"`
 	// Amount The scheduled sub-transaction amount in milliunits format
-	Amount int64 `json:"amount"`
+	Amount api.Milliunits `json:"amount"`
 	// Deleted Deleted scheduled sub-transactions will only be included in delta requests
 	Deleted bool `json:"deleted"`
 
@@ -239,6 +524,18 @@ type OperationSummary struct {
 	Transactions []*Transaction `json:"transactions"`
 	// Transactions If a single transaction was specified, the transaction that was saved
 	Transaction *Transaction `json:"transaction"`
+	// SkippedSplitIDs The IDs of split transactions left unchanged by
+	// Service.RecategorizeByPayee, Service.ApproveAndCategorize or
+	// payee.Service.MergePayees. Not part of the API response.
+	SkippedSplitIDs []string `json:"-"`
+	// MissingIDs The IDs of transactions Service.ApproveTransactions or
+	// Service.ApproveAndCategorize could not find, including deleted ones.
+	// Not part of the API response.
+	MissingIDs []string `json:"-"`
+	// ScheduledTransactionIDs The IDs of scheduled transactions updated
+	// alongside the transactions, e.g. by payee.Service.MergePayees. Not
+	// part of the API response.
+	ScheduledTransactionIDs []string `json:"-"`
 }
 
 // ImportResult represents the output of importing transactions from linked accounts
@@ -246,3 +543,11 @@ type ImportResult struct {
 	// TransactionIDs The list of Transaction IDs that were imported
 	TransactionIDs []string `json:"transaction_ids"`
 }
+
+// IsEmpty returns true if no transaction was imported. The API reports
+// this the same way whether the linked accounts had nothing new or
+// returned nothing at all; a budget without linked accounts fails with
+// ErrNoLinkedAccounts instead.
+func (r *ImportResult) IsEmpty() bool {
+	return r == nil || len(r.TransactionIDs) == 0
+}
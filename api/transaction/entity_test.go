@@ -0,0 +1,414 @@
+package transaction_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coltoneshaw/ynab.go/api"
+	"github.com/coltoneshaw/ynab.go/api/transaction"
+)
+
+func TestTransaction_ToPayload(t *testing.T) {
+	date, err := api.DateFromString("2024-01-15")
+	require.NoError(t, err)
+
+	flagColor := transaction.FlagColorPurple
+	source := &transaction.Transaction{
+		ID:          "tx-1",
+		Date:        date,
+		Amount:      -1000,
+		Cleared:     transaction.ClearingStatusCleared,
+		Approved:    true,
+		AccountID:   "account-id",
+		AccountName: "Checking",
+		Memo:        strPtr("Weekly shop"),
+		FlagColor:   &flagColor,
+		PayeeID:     strPtr("payee-id"),
+		PayeeName:   strPtr("Grocery Store"),
+		CategoryID:  strPtr("category-id"),
+		ImportID:    strPtr("YNAB:-1000:2024-01-15:1"),
+		SubTransactions: []*transaction.SubTransaction{
+			{ID: "sub-1", Amount: -600, Memo: strPtr("Food"), CategoryID: strPtr("cat-food")},
+			{ID: "sub-2", Amount: -400, Deleted: true},
+		},
+	}
+
+	p := source.ToPayload()
+	assert.Equal(t, transaction.PayloadTransaction{
+		ID:         "tx-1",
+		AccountID:  "account-id",
+		Date:       date,
+		Amount:     -1000,
+		Cleared:    transaction.ClearingStatusCleared,
+		Approved:   true,
+		PayeeID:    strPtr("payee-id"),
+		PayeeName:  strPtr("Grocery Store"),
+		CategoryID: strPtr("category-id"),
+		Memo:       strPtr("Weekly shop"),
+		FlagColor:  &flagColor,
+		ImportID:   strPtr("YNAB:-1000:2024-01-15:1"),
+		SubTransactions: []*transaction.PayloadSubTransaction{
+			{ID: "sub-1", Amount: -600, Memo: strPtr("Food"), CategoryID: strPtr("cat-food")},
+		},
+	}, p)
+
+	*p.Memo = "Changed"
+	*p.FlagColor = transaction.FlagColorNone
+	*p.SubTransactions[0].Memo = "Changed"
+
+	assert.Equal(t, "Weekly shop", *source.Memo)
+	assert.Equal(t, transaction.FlagColorPurple, *source.FlagColor)
+	assert.Equal(t, "Food", *source.SubTransactions[0].Memo)
+}
+
+func TestTransaction_Flatten(t *testing.T) {
+	date, err := api.DateFromString("2024-01-15")
+	require.NoError(t, err)
+
+	t.Run("split", func(t *testing.T) {
+		split := &transaction.Transaction{
+			ID:           "tx-1",
+			Date:         date,
+			Amount:       -1000,
+			Cleared:      transaction.ClearingStatusCleared,
+			Approved:     true,
+			AccountID:    "account-id",
+			AccountName:  "Checking",
+			Memo:         strPtr("Weekly shop"),
+			PayeeID:      strPtr("payee-id"),
+			PayeeName:    strPtr("Grocery Store"),
+			CategoryName: strPtr("Split (Multiple Categories)..."),
+			SubTransactions: []*transaction.SubTransaction{
+				{ID: "sub-1", TransactionID: "tx-1", Amount: -600, Memo: strPtr("Food"),
+					CategoryID: strPtr("cat-food"), CategoryName: strPtr("Groceries")},
+				{ID: "sub-2", TransactionID: "tx-1", Amount: -300, CategoryID: strPtr("cat-home"),
+					PayeeID: strPtr("payee-2"), PayeeName: strPtr("Hardware Store")},
+				{ID: "sub-3", TransactionID: "tx-1", Amount: -100, Deleted: true},
+			},
+		}
+		require.True(t, split.IsSplit())
+
+		flat := split.Flatten()
+		require.Len(t, flat, 2)
+
+		assert.Equal(t, &transaction.Transaction{
+			ID:           "sub-1",
+			Date:         date,
+			Amount:       -600,
+			Cleared:      transaction.ClearingStatusCleared,
+			Approved:     true,
+			AccountID:    "account-id",
+			AccountName:  "Checking",
+			Memo:         strPtr("Food"),
+			PayeeID:      strPtr("payee-id"),
+			PayeeName:    strPtr("Grocery Store"),
+			CategoryID:   strPtr("cat-food"),
+			CategoryName: strPtr("Groceries"),
+		}, flat[0])
+
+		assert.Equal(t, "sub-2", flat[1].ID)
+		assert.Equal(t, api.Milliunits(-300), flat[1].Amount)
+		assert.Equal(t, "payee-2", *flat[1].PayeeID)
+		assert.Equal(t, "Hardware Store", *flat[1].PayeeName)
+		assert.Nil(t, flat[1].Memo)
+		assert.Empty(t, flat[1].SubTransactions)
+	})
+
+	t.Run("plain", func(t *testing.T) {
+		plain := &transaction.Transaction{ID: "tx-2", Date: date, Amount: -500, CategoryID: strPtr("cat-food")}
+		assert.False(t, plain.IsSplit())
+		assert.Equal(t, []*transaction.Transaction{plain}, plain.Flatten())
+	})
+}
+
+func TestTransaction_UnmarshalDebtTransactionType(t *testing.T) {
+	var tx transaction.Transaction
+	err := json.Unmarshal([]byte(`{
+  "id": "8a3d2f1e-5b6c-4d7e-8f90-a1b2c3d4e5f6",
+  "date": "2024-03-01",
+  "amount": -152340,
+  "cleared": "cleared",
+  "approved": true,
+  "account_id": "mortgage-account-id",
+  "account_name": "Mortgage",
+  "memo": "March interest",
+  "debt_transaction_type": "interest",
+  "deleted": false,
+  "subtransactions": []
+}`), &tx)
+	require.NoError(t, err)
+
+	require.NotNil(t, tx.DebtTransactionType)
+	assert.Equal(t, transaction.DebtTransactionTypeInterest, *tx.DebtTransactionType)
+	assert.Equal(t, api.Milliunits(-152340), tx.Amount)
+
+	var regular transaction.Transaction
+	err = json.Unmarshal([]byte(`{"id": "tx", "debt_transaction_type": null}`), &regular)
+	require.NoError(t, err)
+	assert.Nil(t, regular.DebtTransactionType)
+}
+
+func TestTransaction_HasUnknownEnums(t *testing.T) {
+	data := `{
+		"id": "e6ad88f5",
+		"date": "2024-01-15",
+		"amount": -1000,
+		"cleared": "cleared",
+		"approved": true,
+		"account_id": "09eaca5e",
+		"flag_color": "teal",
+		"debt_transaction_type": "payment",
+		"deleted": false,
+		"subtransactions": []
+	}`
+
+	var tx transaction.Transaction
+	require.NoError(t, json.Unmarshal([]byte(data), &tx))
+
+	// The unknown color is preserved and survives a round trip
+	require.NotNil(t, tx.FlagColor)
+	assert.Equal(t, transaction.FlagColor("teal"), *tx.FlagColor)
+	assert.False(t, tx.FlagColor.IsValid())
+	assert.Equal(t, transaction.FlagColorUnknown, tx.FlagColor.Known())
+	assert.Equal(t, []string{"flag_color"}, tx.HasUnknownEnums())
+
+	payload, err := json.Marshal(tx.ToPayload())
+	require.NoError(t, err)
+	assert.Contains(t, string(payload), `"flag_color":"teal"`)
+
+	tx.Cleared = "pending"
+	debtType := transaction.DebtTransactionType("rebate")
+	tx.DebtTransactionType = &debtType
+	assert.Equal(t, []string{"cleared", "flag_color", "debt_transaction_type"}, tx.HasUnknownEnums())
+
+	blue := transaction.FlagColorBlue
+	known := transaction.Transaction{Cleared: transaction.ClearingStatusCleared, FlagColor: &blue}
+	assert.Empty(t, known.HasUnknownEnums())
+}
+
+func TestScheduled_DaysUntilNext(t *testing.T) {
+	from, err := api.DateFromString("2024-03-10")
+	require.NoError(t, err)
+
+	tests := []struct {
+		next     string
+		expected int
+	}{
+		{"2024-03-10", 0},
+		{"2024-03-11", 1},
+		{"2024-04-01", 22},
+		{"2024-03-05", -5},
+		{"2023-03-10", -366},
+	}
+
+	for _, test := range tests {
+		t.Run(test.next, func(t *testing.T) {
+			next, err := api.DateFromString(test.next)
+			require.NoError(t, err)
+
+			st := transaction.Scheduled{DateNext: next}
+			assert.Equal(t, test.expected, st.DaysUntilNext(from))
+		})
+	}
+
+	// The time of day of from does not shift the count
+	late := api.Date{Time: from.Add(23 * time.Hour)}
+	next, err := api.DateFromString("2024-03-11")
+	require.NoError(t, err)
+	assert.Equal(t, 1, (&transaction.Scheduled{DateNext: next}).DaysUntilNext(late))
+}
+
+func TestFilterDueWithin(t *testing.T) {
+	from, err := api.DateFromString("2024-03-10")
+	require.NoError(t, err)
+
+	scheduled := func(id, next string, frequency transaction.ScheduledFrequency) *transaction.Scheduled {
+		date, err := api.DateFromString(next)
+		require.NoError(t, err)
+		return &transaction.Scheduled{ID: id, DateNext: date, Frequency: frequency}
+	}
+
+	deleted := scheduled("deleted", "2024-03-12", transaction.FrequencyMonthly)
+	deleted.Deleted = true
+
+	all := []*transaction.Scheduled{
+		scheduled("rent", "2024-04-01", transaction.FrequencyMonthly),
+		scheduled("gym", "2024-03-12", transaction.FrequencyWeekly),
+		scheduled("overdue", "2024-03-08", transaction.FrequencyNever),
+		scheduled("insurance", "2024-03-17", transaction.FrequencyYearly),
+		scheduled("refund", "2024-03-20", transaction.FrequencyNever),
+		scheduled("coffee", "2024-03-10", transaction.FrequencyDaily),
+		deleted,
+		nil,
+	}
+
+	var ids []string
+	for _, st := range transaction.FilterDueWithin(all, from, 7) {
+		ids = append(ids, st.ID)
+	}
+	assert.Equal(t, []string{"gym", "overdue", "insurance", "coffee"}, ids)
+
+	assert.Empty(t, transaction.FilterDueWithin(nil, from, 7))
+}
+
+func TestScheduled_NextOccurrences(t *testing.T) {
+	tests := []struct {
+		name      string
+		next      string
+		frequency transaction.ScheduledFrequency
+		count     int
+		expected  []string
+	}{
+		{
+			name: "never", next: "2024-03-10", frequency: transaction.FrequencyNever, count: 3,
+			expected: []string{"2024-03-10"},
+		},
+		{
+			name: "daily", next: "2024-02-28", frequency: transaction.FrequencyDaily, count: 3,
+			expected: []string{"2024-02-28", "2024-02-29", "2024-03-01"},
+		},
+		{
+			name: "weekly", next: "2024-12-25", frequency: transaction.FrequencyWeekly, count: 3,
+			expected: []string{"2024-12-25", "2025-01-01", "2025-01-08"},
+		},
+		{
+			name: "every other week", next: "2024-03-01", frequency: transaction.FrequencyEveryOtherWeek, count: 3,
+			expected: []string{"2024-03-01", "2024-03-15", "2024-03-29"},
+		},
+		{
+			name: "every four weeks", next: "2024-01-05", frequency: transaction.FrequencyEveryFourWeeks, count: 2,
+			expected: []string{"2024-01-05", "2024-02-02"},
+		},
+		{
+			name: "monthly on the 31st", next: "2024-01-31", frequency: transaction.FrequencyMonthly, count: 5,
+			expected: []string{"2024-01-31", "2024-02-29", "2024-03-31", "2024-04-30", "2024-05-31"},
+		},
+		{
+			name: "every other month", next: "2024-12-31", frequency: transaction.FrequencyEveryOtherMonth, count: 3,
+			expected: []string{"2024-12-31", "2025-02-28", "2025-04-30"},
+		},
+		{
+			name: "every three months", next: "2024-01-15", frequency: transaction.FrequencyEveryThreeMonths, count: 3,
+			expected: []string{"2024-01-15", "2024-04-15", "2024-07-15"},
+		},
+		{
+			name: "every four months", next: "2024-10-30", frequency: transaction.FrequencyEveryFourMonths, count: 3,
+			expected: []string{"2024-10-30", "2025-02-28", "2025-06-30"},
+		},
+		{
+			name: "twice a year", next: "2024-08-31", frequency: transaction.FrequencyTwiceAYear, count: 3,
+			expected: []string{"2024-08-31", "2025-02-28", "2025-08-31"},
+		},
+		{
+			name: "yearly on leap day", next: "2024-02-29", frequency: transaction.FrequencyYearly, count: 2,
+			expected: []string{"2024-02-29", "2025-02-28"},
+		},
+		{
+			name: "twice a month from the 1st", next: "2024-01-01", frequency: transaction.FrequencyTwiceAMonth, count: 4,
+			expected: []string{"2024-01-01", "2024-01-16", "2024-02-01", "2024-02-16"},
+		},
+		{
+			name: "twice a month from the 20th", next: "2024-01-20", frequency: transaction.FrequencyTwiceAMonth, count: 4,
+			expected: []string{"2024-01-20", "2024-02-05", "2024-02-20", "2024-03-05"},
+		},
+		{
+			name: "twice a month from the 31st", next: "2024-01-31", frequency: transaction.FrequencyTwiceAMonth, count: 4,
+			expected: []string{"2024-01-31", "2024-02-16", "2024-02-29", "2024-03-16"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			next, err := api.DateFromString(test.next)
+			require.NoError(t, err)
+
+			st := transaction.Scheduled{DateNext: next, Frequency: test.frequency}
+
+			var dates []string
+			for _, d := range st.NextOccurrences(test.count) {
+				dates = append(dates, api.DateFormat(d))
+			}
+			assert.Equal(t, test.expected, dates)
+		})
+	}
+
+	assert.Nil(t, (&transaction.Scheduled{Frequency: transaction.FrequencyDaily}).NextOccurrences(3))
+
+	next, err := api.DateFromString("2024-01-01")
+	require.NoError(t, err)
+	assert.Nil(t, (&transaction.Scheduled{DateNext: next, Frequency: transaction.FrequencyDaily}).NextOccurrences(0))
+}
+
+func TestScheduled_OccurrencesBetween(t *testing.T) {
+	date := func(s string) api.Date {
+		d, err := api.DateFromString(s)
+		require.NoError(t, err)
+		return d
+	}
+	format := func(dates []api.Date) []string {
+		out := make([]string, 0, len(dates))
+		for _, d := range dates {
+			out = append(out, api.DateFormat(d))
+		}
+		return out
+	}
+
+	weekly := &transaction.Scheduled{DateNext: date("2024-01-03"), Frequency: transaction.FrequencyWeekly}
+	assert.Equal(t, []string{"2024-01-10", "2024-01-17", "2024-01-24"},
+		format(weekly.OccurrencesBetween(date("2024-01-08"), date("2024-01-24"))))
+
+	assert.Empty(t, weekly.OccurrencesBetween(date("2023-12-01"), date("2024-01-02")))
+	assert.Empty(t, (&transaction.Scheduled{}).OccurrencesBetween(date("2024-01-01"), date("2024-12-31")))
+
+	yearly := &transaction.Scheduled{DateNext: date("2024-02-29"), Frequency: transaction.FrequencyYearly}
+	assert.Equal(t, []string{"2024-02-29", "2025-02-28", "2026-02-28"},
+		format(yearly.OccurrencesBetween(date("2024-01-01"), date("2026-12-31"))))
+
+	monthly := &transaction.Scheduled{DateNext: date("2024-01-31"), Frequency: transaction.FrequencyMonthly}
+	assert.Equal(t, []string{"2024-01-31", "2024-02-29"},
+		format(monthly.OccurrencesBetween(date("2024-01-01"), date("2024-03-30"))))
+
+	twice := &transaction.Scheduled{DateNext: date("2024-01-20"), Frequency: transaction.FrequencyTwiceAMonth}
+	assert.Equal(t, []string{"2024-01-20", "2024-02-05", "2024-02-20"},
+		format(twice.OccurrencesBetween(date("2024-01-01"), date("2024-02-29"))))
+
+	once := &transaction.Scheduled{DateNext: date("2024-01-20"), Frequency: transaction.FrequencyNever}
+	assert.Equal(t, []string{"2024-01-20"}, format(once.OccurrencesBetween(date("2024-01-01"), date("2124-01-01"))))
+
+	// The projection steps by the interval, so a long range stays cheap
+	assert.Len(t, yearly.OccurrencesBetween(date("2024-01-01"), date("9999-12-31")), 7976)
+}
+
+func TestScheduled_ToPayload(t *testing.T) {
+	next, err := api.DateFromString("2024-02-01")
+	require.NoError(t, err)
+
+	source := &transaction.Scheduled{
+		ID:         "st-1",
+		DateNext:   next,
+		Frequency:  transaction.FrequencyMonthly,
+		Amount:     -1500,
+		AccountID:  "account-id",
+		PayeeID:    strPtr("payee-id"),
+		CategoryID: strPtr("category-id"),
+		Memo:       strPtr("Rent"),
+	}
+
+	p := source.ToPayload()
+	assert.Equal(t, transaction.PayloadScheduledTransaction{
+		AccountID:  "account-id",
+		Date:       next,
+		Amount:     -1500,
+		Frequency:  transaction.FrequencyMonthly,
+		PayeeID:    strPtr("payee-id"),
+		CategoryID: strPtr("category-id"),
+		Memo:       strPtr("Rent"),
+	}, p)
+
+	*p.Memo = "Changed"
+	assert.Equal(t, "Rent", *source.Memo)
+}
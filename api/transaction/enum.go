@@ -1,5 +1,11 @@
 package transaction
 
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
 // Status represents the type of a transaction
 type Status string
 
@@ -45,6 +51,9 @@ const (
 	FlagColorPurple FlagColor = "purple"
 	// FlagColorNone identifies a transaction with no flag (empty string)
 	FlagColorNone FlagColor = ""
+	// FlagColorUnknown is the catch-all returned by Known for a flag color
+	// this package does not recognize. It is never sent by the API.
+	FlagColorUnknown FlagColor = "unknown"
 )
 
 // ScheduledFrequency represents the frequency of a scheduled transaction
@@ -116,3 +125,199 @@ const (
 	// DebtTransactionTypeCharge identifies a debt charge transaction
 	DebtTransactionTypeCharge DebtTransactionType = "charge"
 )
+
+// ErrInvalidEnum is returned when parsing a value that matches none of
+// the known constants of a transaction enum.
+//
+// Decoding API responses never fails on such values: an enum the API
+// extends after this package was released is preserved as is, so it can be
+// sent back unchanged, and its IsValid method reports false.
+var ErrInvalidEnum = errors.New("transaction: invalid enum value")
+
+var (
+	statuses = []Status{StatusUncategorized, StatusUnapproved}
+
+	clearingStatuses = []ClearingStatus{
+		ClearingStatusUncleared, ClearingStatusCleared, ClearingStatusReconciled,
+	}
+
+	flagColors = []FlagColor{
+		FlagColorRed, FlagColorOrange, FlagColorYellow, FlagColorGreen,
+		FlagColorBlue, FlagColorPurple, FlagColorNone,
+	}
+
+	scheduledFrequencies = []ScheduledFrequency{
+		FrequencyNever, FrequencyDaily, FrequencyWeekly, FrequencyEveryOtherWeek,
+		FrequencyTwiceAMonth, FrequencyEveryFourWeeks, FrequencyMonthly,
+		FrequencyEveryOtherMonth, FrequencyEveryThreeMonths, FrequencyEveryFourMonths,
+		FrequencyTwiceAYear, FrequencyYearly,
+	}
+
+	types = []Type{TypeTransaction, TypeSubTransaction}
+
+	debtTransactionTypes = []DebtTransactionType{
+		DebtTransactionTypePayment, DebtTransactionTypeRefund, DebtTransactionTypeFee,
+		DebtTransactionTypeInterest, DebtTransactionTypeEscrow,
+		DebtTransactionTypeBalanceAdjustment, DebtTransactionTypeCredit,
+		DebtTransactionTypeCharge,
+	}
+)
+
+// String returns the API value of the status
+func (s Status) String() string {
+	return string(s)
+}
+
+// String returns the API value of the clearing status
+func (s ClearingStatus) String() string {
+	return string(s)
+}
+
+// String returns the API value of the flag color, or "none" for FlagColorNone
+func (f FlagColor) String() string {
+	if f == FlagColorNone {
+		return "none"
+	}
+	return string(f)
+}
+
+// String returns the API value of the frequency
+func (f ScheduledFrequency) String() string {
+	return string(f)
+}
+
+// frequencyLabels holds the label YNAB displays for each frequency
+var frequencyLabels = map[ScheduledFrequency]string{
+	FrequencyNever:            "Never",
+	FrequencyDaily:            "Daily",
+	FrequencyWeekly:           "Weekly",
+	FrequencyEveryOtherWeek:   "Every Other Week",
+	FrequencyTwiceAMonth:      "Twice a Month",
+	FrequencyEveryFourWeeks:   "Every 4 Weeks",
+	FrequencyMonthly:          "Monthly",
+	FrequencyEveryOtherMonth:  "Every Other Month",
+	FrequencyEveryThreeMonths: "Every 3 Months",
+	FrequencyEveryFourMonths:  "Every 4 Months",
+	FrequencyTwiceAYear:       "Twice a Year",
+	FrequencyYearly:           "Yearly",
+}
+
+// Label returns the frequency as YNAB displays it, e.g. "Every Other Week"
+// for FrequencyEveryOtherWeek. A frequency this package does not recognize
+// is returned as its API value.
+func (f ScheduledFrequency) Label() string {
+	if label, ok := frequencyLabels[f]; ok {
+		return label
+	}
+	return string(f)
+}
+
+// ApproxDays returns the approximate number of days between occurrences of
+// the frequency, for sorting or rough forecasts. Month based frequencies
+// count 365/12 days per month, rounded down, so FrequencyMonthly is 30 days
+// and FrequencyYearly 365. It is zero for FrequencyNever and for
+// frequencies this package does not recognize.
+func (f ScheduledFrequency) ApproxDays() int {
+	if f == FrequencyTwiceAMonth {
+		return 365 / 24
+	}
+	days, months := frequencyStep(f)
+	return days + months*365/12
+}
+
+// String returns the API value of the hybrid transaction type
+func (t Type) String() string {
+	return string(t)
+}
+
+// String returns the API value of the debt transaction type
+func (t DebtTransactionType) String() string {
+	return string(t)
+}
+
+// IsValid returns true if the status is one of the known constants
+func (s Status) IsValid() bool {
+	return slices.Contains(statuses, s)
+}
+
+// IsValid returns true if the clearing status is one of the known constants
+func (s ClearingStatus) IsValid() bool {
+	return slices.Contains(clearingStatuses, s)
+}
+
+// IsValid returns true if the flag color is one of the known constants,
+// including FlagColorNone. FlagColorUnknown is not valid.
+func (f FlagColor) IsValid() bool {
+	return slices.Contains(flagColors, f)
+}
+
+// Known returns the flag color if it is valid and FlagColorUnknown
+// otherwise, so a switch can handle colors added to the API in one case
+func (f FlagColor) Known() FlagColor {
+	if f.IsValid() {
+		return f
+	}
+	return FlagColorUnknown
+}
+
+// IsValid returns true if the frequency is one of the known constants
+func (f ScheduledFrequency) IsValid() bool {
+	return slices.Contains(scheduledFrequencies, f)
+}
+
+// IsValid returns true if the hybrid transaction type is one of the known
+// constants
+func (t Type) IsValid() bool {
+	return slices.Contains(types, t)
+}
+
+// IsValid returns true if the debt transaction type is one of the known
+// constants
+func (t DebtTransactionType) IsValid() bool {
+	return slices.Contains(debtTransactionTypes, t)
+}
+
+// ParseStatus returns the Status matching s
+func ParseStatus(s string) (Status, error) {
+	return parseEnum("status", s, statuses)
+}
+
+// ParseClearingStatus returns the ClearingStatus matching s
+func ParseClearingStatus(s string) (ClearingStatus, error) {
+	return parseEnum("clearing status", s, clearingStatuses)
+}
+
+// ParseFlagColor returns the FlagColor matching s. An empty string and
+// "none", as returned by FlagColorNone.String, parse as FlagColorNone.
+func ParseFlagColor(s string) (FlagColor, error) {
+	if s == FlagColorNone.String() {
+		return FlagColorNone, nil
+	}
+	return parseEnum("flag color", s, flagColors)
+}
+
+// ParseScheduledFrequency returns the ScheduledFrequency matching s
+func ParseScheduledFrequency(s string) (ScheduledFrequency, error) {
+	return parseEnum("scheduled frequency", s, scheduledFrequencies)
+}
+
+// ParseType returns the hybrid transaction Type matching s
+func ParseType(s string) (Type, error) {
+	return parseEnum("type", s, types)
+}
+
+// ParseDebtTransactionType returns the DebtTransactionType matching s
+func ParseDebtTransactionType(s string) (DebtTransactionType, error) {
+	return parseEnum("debt transaction type", s, debtTransactionTypes)
+}
+
+// parseEnum returns the value of known equal to s, or an error wrapping
+// ErrInvalidEnum naming the kind of enum
+func parseEnum[T ~string](kind, s string, known []T) (T, error) {
+	for _, v := range known {
+		if string(v) == s {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("%w: unknown %s %q", ErrInvalidEnum, kind, s)
+}
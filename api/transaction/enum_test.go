@@ -0,0 +1,150 @@
+package transaction_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coltoneshaw/ynab.go/api/transaction"
+)
+
+func TestParseEnums(t *testing.T) {
+	parsers := map[string]func(string) (string, error){
+		"status": func(s string) (string, error) {
+			v, err := transaction.ParseStatus(s)
+			return v.String(), err
+		},
+		"clearing status": func(s string) (string, error) {
+			v, err := transaction.ParseClearingStatus(s)
+			return v.String(), err
+		},
+		"flag color": func(s string) (string, error) {
+			v, err := transaction.ParseFlagColor(s)
+			return string(v), err
+		},
+		"scheduled frequency": func(s string) (string, error) {
+			v, err := transaction.ParseScheduledFrequency(s)
+			return v.String(), err
+		},
+		"type": func(s string) (string, error) {
+			v, err := transaction.ParseType(s)
+			return v.String(), err
+		},
+		"debt transaction type": func(s string) (string, error) {
+			v, err := transaction.ParseDebtTransactionType(s)
+			return v.String(), err
+		},
+	}
+
+	valid := map[string][]string{
+		"status":          {"uncategorized", "unapproved"},
+		"clearing status": {"uncleared", "cleared", "reconciled"},
+		"flag color":      {"red", "orange", "yellow", "green", "blue", "purple", ""},
+		"scheduled frequency": {
+			"never", "daily", "weekly", "everyOtherWeek", "twiceAMonth", "every4Weeks",
+			"monthly", "everyOtherMonth", "every3Months", "every4Months", "twiceAYear", "yearly",
+		},
+		"type": {"transaction", "subtransaction"},
+		"debt transaction type": {
+			"payment", "refund", "fee", "interest", "escrow", "balanceAdjustment", "credit", "charge",
+		},
+	}
+
+	for kind, parse := range parsers {
+		t.Run(kind, func(t *testing.T) {
+			for _, s := range valid[kind] {
+				v, err := parse(s)
+				assert.NoError(t, err, s)
+				assert.Equal(t, s, v)
+			}
+
+			for _, s := range []string{"bogus", "RED", " cleared"} {
+				_, err := parse(s)
+				assert.ErrorIs(t, err, transaction.ErrInvalidEnum, s)
+				assert.Contains(t, err.Error(), kind)
+			}
+		})
+	}
+}
+
+func TestEnums_String(t *testing.T) {
+	assert.Equal(t, "unapproved", transaction.StatusUnapproved.String())
+	assert.Equal(t, "reconciled", transaction.ClearingStatusReconciled.String())
+	assert.Equal(t, "purple", transaction.FlagColorPurple.String())
+	assert.Equal(t, "none", transaction.FlagColorNone.String())
+	assert.Equal(t, "every4Weeks", transaction.FrequencyEveryFourWeeks.String())
+	assert.Equal(t, "subtransaction", transaction.TypeSubTransaction.String())
+	assert.Equal(t, "balanceAdjustment", transaction.DebtTransactionTypeBalanceAdjustment.String())
+}
+
+func TestFlagColor_StringRoundTrip(t *testing.T) {
+	for _, f := range []transaction.FlagColor{
+		transaction.FlagColorRed, transaction.FlagColorOrange, transaction.FlagColorYellow,
+		transaction.FlagColorGreen, transaction.FlagColorBlue, transaction.FlagColorPurple,
+		transaction.FlagColorNone,
+	} {
+		parsed, err := transaction.ParseFlagColor(f.String())
+		assert.NoError(t, err, f.String())
+		assert.Equal(t, f, parsed)
+	}
+}
+
+func TestScheduledFrequency_LabelAndApproxDays(t *testing.T) {
+	tests := []struct {
+		frequency transaction.ScheduledFrequency
+		label     string
+		days      int
+	}{
+		{transaction.FrequencyNever, "Never", 0},
+		{transaction.FrequencyDaily, "Daily", 1},
+		{transaction.FrequencyWeekly, "Weekly", 7},
+		{transaction.FrequencyEveryOtherWeek, "Every Other Week", 14},
+		{transaction.FrequencyTwiceAMonth, "Twice a Month", 15},
+		{transaction.FrequencyEveryFourWeeks, "Every 4 Weeks", 28},
+		{transaction.FrequencyMonthly, "Monthly", 30},
+		{transaction.FrequencyEveryOtherMonth, "Every Other Month", 60},
+		{transaction.FrequencyEveryThreeMonths, "Every 3 Months", 91},
+		{transaction.FrequencyEveryFourMonths, "Every 4 Months", 121},
+		{transaction.FrequencyTwiceAYear, "Twice a Year", 182},
+		{transaction.FrequencyYearly, "Yearly", 365},
+	}
+
+	for _, test := range tests {
+		t.Run(test.frequency.String(), func(t *testing.T) {
+			assert.Equal(t, test.label, test.frequency.Label())
+			assert.Equal(t, test.days, test.frequency.ApproxDays())
+		})
+	}
+
+	unknown := transaction.ScheduledFrequency("fortnightly")
+	assert.Equal(t, "fortnightly", unknown.Label())
+	assert.Zero(t, unknown.ApproxDays())
+}
+
+func TestEnums_IsValid(t *testing.T) {
+	assert.True(t, transaction.StatusUnapproved.IsValid())
+	assert.False(t, transaction.Status("flagged").IsValid())
+
+	assert.True(t, transaction.ClearingStatusReconciled.IsValid())
+	assert.False(t, transaction.ClearingStatus("pending").IsValid())
+
+	assert.True(t, transaction.FlagColorPurple.IsValid())
+	assert.True(t, transaction.FlagColorNone.IsValid())
+	assert.False(t, transaction.FlagColor("teal").IsValid())
+	assert.False(t, transaction.FlagColorUnknown.IsValid())
+
+	assert.True(t, transaction.FrequencyEveryFourWeeks.IsValid())
+	assert.False(t, transaction.ScheduledFrequency("fortnightly").IsValid())
+
+	assert.True(t, transaction.TypeSubTransaction.IsValid())
+	assert.False(t, transaction.Type("split").IsValid())
+
+	assert.True(t, transaction.DebtTransactionTypeEscrow.IsValid())
+	assert.False(t, transaction.DebtTransactionType("rebate").IsValid())
+}
+
+func TestFlagColor_Known(t *testing.T) {
+	assert.Equal(t, transaction.FlagColorRed, transaction.FlagColorRed.Known())
+	assert.Equal(t, transaction.FlagColorNone, transaction.FlagColorNone.Known())
+	assert.Equal(t, transaction.FlagColorUnknown, transaction.FlagColor("teal").Known())
+}
@@ -0,0 +1,194 @@
+package transaction
+
+import (
+	"fmt"
+
+	"github.com/coltoneshaw/ynab.go/api"
+)
+
+// nameLookup maps the category and payee IDs of a budget to their names
+type nameLookup struct {
+	categories map[string]string
+	payees     map[string]string
+}
+
+// WithNameCache makes ResolveNames keep the category and payee names it
+// fetches for each budget, so later calls on the service make no request.
+// Categories and payees renamed or created afterwards are not seen.
+func (s *Service) WithNameCache() *Service {
+	s.namesMu.Lock()
+	defer s.namesMu.Unlock()
+
+	if s.names == nil {
+		s.names = map[string]*nameLookup{}
+	}
+	return s
+}
+
+// ResolveNames fills in the missing CategoryName and PayeeName of the
+// transactions and their subtransactions in place, from their CategoryID
+// and PayeeID. The categories and payees of the budget are fetched once
+// per call, and only when a name is missing; see WithNameCache to reuse
+// them across calls.
+// https://api.youneedabudget.com/v1#/Categories/getCategories
+// https://api.youneedabudget.com/v1#/Payees/getPayees
+func (s *Service) ResolveNames(budgetID string, txs []*Transaction) error {
+	needCategories, needPayees := false, false
+	for _, t := range txs {
+		if t == nil {
+			continue
+		}
+		needCategories = needCategories || missingName(t.CategoryID, t.CategoryName)
+		needPayees = needPayees || missingName(t.PayeeID, t.PayeeName)
+		for _, sub := range t.SubTransactions {
+			if sub == nil {
+				continue
+			}
+			needCategories = needCategories || missingName(sub.CategoryID, sub.CategoryName)
+			needPayees = needPayees || missingName(sub.PayeeID, sub.PayeeName)
+		}
+	}
+	if !needCategories && !needPayees {
+		return nil
+	}
+
+	lookup, err := s.nameLookup(budgetID, needCategories, needPayees)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range txs {
+		if t == nil {
+			continue
+		}
+		t.CategoryName = resolveName(t.CategoryID, t.CategoryName, lookup.categories)
+		t.PayeeName = resolveName(t.PayeeID, t.PayeeName, lookup.payees)
+		for _, sub := range t.SubTransactions {
+			if sub == nil {
+				continue
+			}
+			sub.CategoryName = resolveName(sub.CategoryID, sub.CategoryName, lookup.categories)
+			sub.PayeeName = resolveName(sub.PayeeID, sub.PayeeName, lookup.payees)
+		}
+	}
+	return nil
+}
+
+// nameLookup returns the name lookup of a budget, from the cache when
+// enabled, fetching the categories and payees it is missing. The lock is
+// only held to read and fill the cache, never during the requests, so
+// concurrent callers may fetch the same names and the last one is kept.
+func (s *Service) nameLookup(budgetID string, categories, payees bool) (*nameLookup, error) {
+	lookup := &nameLookup{}
+	s.namesMu.Lock()
+	if cached, ok := s.names[budgetID]; ok {
+		*lookup = *cached
+	}
+	s.namesMu.Unlock()
+
+	fetchCategories := categories && lookup.categories == nil
+	fetchPayees := payees && lookup.payees == nil
+	if !fetchCategories && !fetchPayees {
+		return lookup, nil
+	}
+
+	if fetchCategories {
+		names, err := s.categoryNames(budgetID)
+		if err != nil {
+			return nil, err
+		}
+		lookup.categories = names
+	}
+	if fetchPayees {
+		names, err := s.payeeNames(budgetID)
+		if err != nil {
+			return nil, err
+		}
+		lookup.payees = names
+	}
+
+	s.namesMu.Lock()
+	defer s.namesMu.Unlock()
+
+	if s.names != nil {
+		cached := &nameLookup{}
+		if current, ok := s.names[budgetID]; ok {
+			*cached = *current
+		}
+		if fetchCategories {
+			cached.categories = lookup.categories
+		}
+		if fetchPayees {
+			cached.payees = lookup.payees
+		}
+		s.names[budgetID] = cached
+	}
+	return lookup, nil
+}
+
+// categoryNames fetches the names of the categories of a budget by ID
+func (s *Service) categoryNames(budgetID string) (map[string]string, error) {
+	resModel := struct {
+		Data struct {
+			CategoryGroups []struct {
+				Categories []struct {
+					ID   string `json:"id"`
+					Name string `json:"name"`
+				} `json:"categories"`
+			} `json:"category_groups"`
+		} `json:"data"`
+	}{}
+
+	url := fmt.Sprintf("/budgets/%s/categories", budgetID)
+	if err := api.FullReads(s.c).GET(url, &resModel); err != nil {
+		return nil, err
+	}
+
+	names := map[string]string{}
+	for _, group := range resModel.Data.CategoryGroups {
+		for _, c := range group.Categories {
+			names[c.ID] = c.Name
+		}
+	}
+	return names, nil
+}
+
+// payeeNames fetches the names of the payees of a budget by ID
+func (s *Service) payeeNames(budgetID string) (map[string]string, error) {
+	resModel := struct {
+		Data struct {
+			Payees []struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			} `json:"payees"`
+		} `json:"data"`
+	}{}
+
+	url := fmt.Sprintf("/budgets/%s/payees", budgetID)
+	if err := api.FullReads(s.c).GET(url, &resModel); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string, len(resModel.Data.Payees))
+	for _, p := range resModel.Data.Payees {
+		names[p.ID] = p.Name
+	}
+	return names, nil
+}
+
+// missingName reports whether an entity is referenced by id without name
+func missingName(id, name *string) bool {
+	return id != nil && *id != "" && (name == nil || *name == "")
+}
+
+// resolveName returns the name of id in names when name is missing, and
+// name otherwise
+func resolveName(id, name *string, names map[string]string) *string {
+	if !missingName(id, name) {
+		return name
+	}
+	if resolved, ok := names[*id]; ok {
+		return &resolved
+	}
+	return name
+}
@@ -0,0 +1,183 @@
+package transaction_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/jarcoal/httpmock.v1"
+
+	"github.com/coltoneshaw/ynab.go"
+	"github.com/coltoneshaw/ynab.go/api/transaction"
+	"github.com/coltoneshaw/ynab.go/ynabtest"
+)
+
+func registerNameResponders() {
+	httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets/aa248caa/categories",
+		httpmock.NewStringResponder(http.StatusOK, `{"data":{"category_groups":[
+			{"id":"group-1","name":"Everyday","categories":[
+				{"id":"cat-groceries","name":"Groceries"},
+				{"id":"cat-dining","name":"Dining Out"}
+			]},
+			{"id":"group-2","name":"Bills","categories":[{"id":"cat-rent","name":"Rent"}]}
+		],"server_knowledge":3}}`))
+	httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets/aa248caa/payees",
+		httpmock.NewStringResponder(http.StatusOK, `{"data":{"payees":[
+			{"id":"payee-market","name":"Supermarket"},
+			{"id":"payee-landlord","name":"Landlord"}
+		],"server_knowledge":3}}`))
+}
+
+func TestService_ResolveNames(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	registerNameResponders()
+
+	kept := "Kept Name"
+	txs := []*transaction.Transaction{
+		{ID: "tx-1", CategoryID: strPtr("cat-groceries"), PayeeID: strPtr("payee-market")},
+		{ID: "tx-2", CategoryID: strPtr("cat-rent"), CategoryName: &kept, PayeeID: strPtr("payee-landlord"), PayeeName: strPtr("")},
+		{ID: "tx-3", CategoryID: strPtr("cat-unknown"), PayeeID: strPtr("payee-unknown")},
+		{ID: "tx-4", SubTransactions: []*transaction.SubTransaction{
+			{ID: "sub-1", CategoryID: strPtr("cat-dining"), PayeeID: strPtr("payee-market")},
+			{ID: "sub-2", CategoryID: strPtr("cat-groceries")},
+		}},
+		nil,
+	}
+
+	require.NoError(t, ynab.NewClient("").Transaction().ResolveNames("aa248caa", txs))
+
+	assert.Equal(t, "Groceries", *txs[0].CategoryName)
+	assert.Equal(t, "Supermarket", *txs[0].PayeeName)
+	assert.Equal(t, "Kept Name", *txs[1].CategoryName)
+	assert.Equal(t, "Landlord", *txs[1].PayeeName)
+	assert.Nil(t, txs[2].CategoryName)
+	assert.Nil(t, txs[2].PayeeName)
+	assert.Nil(t, txs[3].CategoryName)
+	assert.Equal(t, "Dining Out", *txs[3].SubTransactions[0].CategoryName)
+	assert.Equal(t, "Supermarket", *txs[3].SubTransactions[0].PayeeName)
+	assert.Equal(t, "Groceries", *txs[3].SubTransactions[1].CategoryName)
+	assert.Nil(t, txs[3].SubTransactions[1].PayeeName)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestService_ResolveNames_Requests(t *testing.T) {
+	t.Run("nothing missing", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		registerNameResponders()
+
+		txs := []*transaction.Transaction{
+			{ID: "tx-1", CategoryID: strPtr("cat-groceries"), CategoryName: strPtr("Groceries")},
+			{ID: "tx-2"},
+		}
+		require.NoError(t, ynab.NewClient("").Transaction().ResolveNames("aa248caa", txs))
+		assert.Zero(t, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("only payees missing", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		registerNameResponders()
+
+		txs := []*transaction.Transaction{{ID: "tx-1", PayeeID: strPtr("payee-market")}}
+		require.NoError(t, ynab.NewClient("").Transaction().ResolveNames("aa248caa", txs))
+		assert.Equal(t, "Supermarket", *txs[0].PayeeName)
+		assert.Equal(t, map[string]int{
+			"GET https://api.youneedabudget.com/v1/budgets/aa248caa/payees": 1,
+		}, nonZeroCalls())
+	})
+
+	t.Run("without cache every call fetches", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		registerNameResponders()
+
+		service := ynab.NewClient("").Transaction()
+		for i := 0; i < 2; i++ {
+			txs := []*transaction.Transaction{{ID: "tx-1", CategoryID: strPtr("cat-rent"), PayeeID: strPtr("payee-landlord")}}
+			require.NoError(t, service.ResolveNames("aa248caa", txs))
+		}
+		assert.Equal(t, 4, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("with cache names are fetched once", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		registerNameResponders()
+
+		service := ynab.NewClient("").Transaction().WithNameCache()
+		for i := 0; i < 3; i++ {
+			txs := []*transaction.Transaction{{ID: "tx-1", CategoryID: strPtr("cat-rent"), PayeeID: strPtr("payee-landlord")}}
+			require.NoError(t, service.ResolveNames("aa248caa", txs))
+			assert.Equal(t, "Rent", *txs[0].CategoryName)
+			assert.Equal(t, "Landlord", *txs[0].PayeeName)
+		}
+		assert.Equal(t, 2, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("cache is not locked during requests", func(t *testing.T) {
+		// httpmock runs one responder at a time, so serve the budgets from
+		// a transport that lets the requests overlap
+		started, release := make(chan struct{}), make(chan struct{})
+		transport := ynabtest.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			body := `{"data":{"category_groups":[{"id":"group-1","name":"Bills","categories":[{"id":"cat-rent","name":"Rent"}]}]}}`
+			if strings.Contains(req.URL.Path, "/budgets/bb248caa/") {
+				close(started)
+				<-release
+			}
+			return httpmock.NewStringResponse(http.StatusOK, body), nil
+		})
+
+		c := ynab.NewClient("")
+		c.WithHTTPClient(&http.Client{Transport: transport})
+		service := c.Transaction().WithNameCache()
+
+		slow := make(chan error)
+		go func() {
+			slow <- service.ResolveNames("bb248caa", []*transaction.Transaction{{ID: "tx-1", CategoryID: strPtr("cat-rent")}})
+		}()
+		<-started
+
+		fast := make(chan error)
+		txs := []*transaction.Transaction{{ID: "tx-1", CategoryID: strPtr("cat-rent")}}
+		go func() { fast <- service.ResolveNames("aa248caa", txs) }()
+		select {
+		case err := <-fast:
+			require.NoError(t, err)
+			assert.Equal(t, "Rent", *txs[0].CategoryName)
+		case <-time.After(time.Second):
+			t.Fatal("ResolveNames waited for the request of another budget")
+		}
+
+		close(release)
+		assert.NoError(t, <-slow)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets/aa248caa/categories",
+			httpmock.NewStringResponder(http.StatusNotFound,
+				`{"error":{"id":"404.2","name":"resource_not_found","detail":"Resource not found"}}`))
+
+		txs := []*transaction.Transaction{{ID: "tx-1", CategoryID: strPtr("cat-rent")}}
+		assert.Error(t, ynab.NewClient("").Transaction().ResolveNames("aa248caa", txs))
+		assert.Nil(t, txs[0].CategoryName)
+	})
+}
+
+// nonZeroCalls returns the httpmock call counts of the responders called
+func nonZeroCalls() map[string]int {
+	calls := map[string]int{}
+	for key, count := range httpmock.GetCallCountInfo() {
+		if count > 0 {
+			calls[key] = count
+		}
+	}
+	return calls
+}
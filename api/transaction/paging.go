@@ -0,0 +1,111 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/coltoneshaw/ynab.go/api"
+)
+
+// ErrDataLimitUnsplittable is returned by GetTransactions with data limit
+// paging enabled when a request exceeds the API data limits and cannot be
+// narrowed any further
+var ErrDataLimitUnsplittable = errors.New("transaction: data limit reached and the request cannot be narrowed")
+
+// WithDataLimitPaging makes GetTransactions recover from a data limit
+// error (api.ErrorDataLimitReached, 403.4) by fetching the since_date
+// range in narrower windows and stitching the pages together.
+//
+// The API only bounds a transactions request from below, so the range is
+// halved by month: the later half is requested from its first day through
+// today, halving again while it still exceeds the limits, and each month of
+// the earlier half is requested through the month transactions endpoint.
+// A request without a since date, or a single month that still exceeds the
+// limits, fails with ErrDataLimitUnsplittable wrapping the API error.
+func (s *Service) WithDataLimitPaging() *Service {
+	s.dataLimitPaging = true
+	return s
+}
+
+// WithClock sets the clock used to find the current month when narrowing
+// requests with data limit paging
+func (s *Service) WithClock(clock api.Clock) *Service {
+	s.clock = clock
+	return s
+}
+
+// pageByDataLimit fetches the transactions matching f after its request
+// failed with the data limit error cause
+func (s *Service) pageByDataLimit(budgetID string, f *Filter, cause error) (*SearchResultSnapshot, error) {
+	if f == nil || f.Since == nil || f.Since.IsZero() {
+		return nil, fmt.Errorf("%w: %w", ErrDataLimitUnsplittable, cause)
+	}
+
+	clock := s.clock
+	if clock == nil {
+		clock = api.SystemClock
+	}
+
+	from := monthStart(f.Since.Time)
+	current := monthStart(clock.Now())
+	months := (current.Year()-from.Year())*12 + int(current.Month()-from.Month()) + 1
+	if months <= 1 {
+		return nil, fmt.Errorf("%w: %w", ErrDataLimitUnsplittable, cause)
+	}
+	mid := from.AddDate(0, months/2, 0)
+
+	// Every month of the earlier half is bounded by the month endpoint; the
+	// first keeps the original since date
+	result := &SearchResultSnapshot{}
+	for month := from; month.Before(mid); month = month.AddDate(0, 1, 0) {
+		monthFilter := *f
+		if !month.Equal(from) {
+			monthFilter.Since = nil
+		}
+
+		page, err := s.GetTransactionsByMonth(budgetID, api.DateFormat(api.Date{Time: month}), monthFilter.orNil())
+		if err != nil {
+			var apiErr *api.Error
+			if errors.As(err, &apiErr) && apiErr.IsDataLimitReached() {
+				return nil, fmt.Errorf("%w: %w", ErrDataLimitUnsplittable, err)
+			}
+			return nil, err
+		}
+		appendPage(result, page)
+	}
+
+	// The later half runs through today and is narrowed again if needed
+	tailFilter := *f
+	tailFilter.Since = &api.Date{Time: mid}
+	tail, err := s.GetTransactions(budgetID, &tailFilter)
+	if err != nil {
+		return nil, err
+	}
+	appendPage(result, tail)
+
+	return result, nil
+}
+
+// appendPage appends the items of page to the stitched result, keeping the
+// lowest server knowledge so a follow-up delta request misses no change
+func appendPage(result, page *SearchResultSnapshot) {
+	result.Transactions = append(result.Transactions, page.Transactions...)
+	if result.ServerKnowledge == 0 || page.ServerKnowledge < result.ServerKnowledge {
+		result.ServerKnowledge = page.ServerKnowledge
+	}
+}
+
+// orNil returns nil when no filter is set, so no empty query is sent
+func (f *Filter) orNil() *Filter {
+	if (f.Since == nil || f.Since.IsZero()) && f.Type == nil && f.LastKnowledgeOfServer == nil &&
+		!f.hasAmountRange() {
+		return nil
+	}
+	return f
+}
+
+// monthStart returns the first day of the month of t
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
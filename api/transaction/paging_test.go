@@ -0,0 +1,155 @@
+package transaction_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/jarcoal/httpmock.v1"
+
+	"github.com/coltoneshaw/ynab.go"
+	"github.com/coltoneshaw/ynab.go/api"
+	"github.com/coltoneshaw/ynab.go/api/transaction"
+)
+
+const dataLimitError = `{"error":{"id":"403.4","name":"data_limit_reached","detail":"Request will exceed data limits"}}`
+
+// fixedClock is an api.Clock stopped at a given time
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+func transactionsPage(knowledge uint64, ids ...string) string {
+	items := ""
+	for i, id := range ids {
+		if i > 0 {
+			items += ","
+		}
+		items += fmt.Sprintf(`{"id":%q,"date":"2024-01-01","amount":-1000,"cleared":"cleared","approved":true,"account_id":"acc","deleted":false,"subtransactions":[]}`, id)
+	}
+	return fmt.Sprintf(`{"data":{"transactions":[%s],"server_knowledge":%d}}`, items, knowledge)
+}
+
+func TestService_GetTransactions_DataLimitPaging(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	base := "https://api.youneedabudget.com/v1/budgets/aa248caa"
+	var requests []string
+	record := func(req *http.Request) {
+		requests = append(requests, req.URL.Path+"?"+req.URL.RawQuery)
+	}
+
+	httpmock.RegisterResponder(http.MethodGet, base+"/transactions",
+		func(req *http.Request) (*http.Response, error) {
+			record(req)
+			switch req.URL.Query().Get("since_date") {
+			case "2024-01-10":
+				return httpmock.NewStringResponse(http.StatusForbidden, dataLimitError), nil
+			case "2024-03-01":
+				return httpmock.NewStringResponse(http.StatusOK, transactionsPage(30, "march", "april")), nil
+			}
+			return httpmock.NewStringResponse(http.StatusBadRequest, `{"error":{"id":"400","name":"bad_request","detail":"unexpected"}}`), nil
+		})
+	httpmock.RegisterResponder(http.MethodGet, base+"/months/2024-01-01/transactions",
+		func(req *http.Request) (*http.Response, error) {
+			record(req)
+			return httpmock.NewStringResponse(http.StatusOK, transactionsPage(28, "january")), nil
+		})
+	httpmock.RegisterResponder(http.MethodGet, base+"/months/2024-02-01/transactions",
+		func(req *http.Request) (*http.Response, error) {
+			record(req)
+			return httpmock.NewStringResponse(http.StatusOK, transactionsPage(29, "february")), nil
+		})
+
+	since, err := api.DateFromString("2024-01-10")
+	require.NoError(t, err)
+	filter := &transaction.Filter{Since: &since}
+
+	client := ynab.NewClient("")
+
+	// Without paging the data limit error is returned as is
+	_, err = client.Transaction().GetTransactions("aa248caa", filter)
+	var apiErr *api.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.True(t, apiErr.IsDataLimitReached())
+
+	requests = nil
+	service := client.Transaction().
+		WithDataLimitPaging().
+		WithClock(fixedClock(time.Date(2024, time.April, 15, 12, 0, 0, 0, time.UTC)))
+
+	result, err := service.GetTransactions("aa248caa", filter)
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(result.Transactions))
+	for _, tx := range result.Transactions {
+		ids = append(ids, tx.ID)
+	}
+	assert.Equal(t, []string{"january", "february", "march", "april"}, ids)
+	assert.Equal(t, uint64(28), result.ServerKnowledge)
+	assert.Equal(t, []string{
+		"/v1/budgets/aa248caa/transactions?since_date=2024-01-10",
+		"/v1/budgets/aa248caa/months/2024-01-01/transactions?since_date=2024-01-10",
+		"/v1/budgets/aa248caa/months/2024-02-01/transactions?",
+		"/v1/budgets/aa248caa/transactions?since_date=2024-03-01",
+	}, requests)
+}
+
+func TestService_GetTransactions_DataLimitUnsplittable(t *testing.T) {
+	clock := fixedClock(time.Date(2024, time.April, 15, 12, 0, 0, 0, time.UTC))
+
+	setup := func(t *testing.T) *transaction.Service {
+		httpmock.Activate()
+		t.Cleanup(httpmock.DeactivateAndReset)
+
+		httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets/aa248caa/transactions",
+			httpmock.NewStringResponder(http.StatusForbidden, dataLimitError))
+		httpmock.RegisterNoResponder(httpmock.NewStringResponder(http.StatusOK, transactionsPage(1, "month")))
+
+		return ynab.NewClient("").Transaction().WithDataLimitPaging().WithClock(clock)
+	}
+
+	t.Run("single month", func(t *testing.T) {
+		service := setup(t)
+
+		since, err := api.DateFromString("2024-04-02")
+		require.NoError(t, err)
+
+		_, err = service.GetTransactions("aa248caa", &transaction.Filter{Since: &since})
+		assert.ErrorIs(t, err, transaction.ErrDataLimitUnsplittable)
+
+		var apiErr *api.Error
+		require.True(t, errors.As(err, &apiErr))
+		assert.True(t, apiErr.IsDataLimitReached())
+		assert.Equal(t, 1, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("no since date", func(t *testing.T) {
+		service := setup(t)
+
+		_, err := service.GetTransactions("aa248caa", nil)
+		assert.ErrorIs(t, err, transaction.ErrDataLimitUnsplittable)
+		assert.Equal(t, 1, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("tail too large down to a single month", func(t *testing.T) {
+		service := setup(t)
+
+		since, err := api.DateFromString("2023-01-01")
+		require.NoError(t, err)
+
+		_, err = service.GetTransactions("aa248caa", &transaction.Filter{Since: &since})
+		assert.ErrorIs(t, err, transaction.ErrDataLimitUnsplittable)
+
+		// 2023-01, 2023-09, 2024-01, 2024-03 and 2024-04 are tried as
+		// tails, and the 15 months before 2024-04 through the month endpoint
+		assert.Equal(t, 5+15, httpmock.GetTotalCallCount())
+	})
+}
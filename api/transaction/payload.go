@@ -1,10 +1,37 @@
 package transaction
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
 	"github.com/coltoneshaw/ynab.go/api"
+	"github.com/coltoneshaw/ynab.go/api/account"
+)
+
+// Validation errors reported by PayloadTransaction.Validate
+var (
+	ErrAccountIDRequired = errors.New("transaction: account_id is required")
+	ErrDateRequired      = errors.New("transaction: date is required")
+	ErrClearedRequired   = errors.New("transaction: cleared is required")
+	ErrPayeeRequired     = errors.New("transaction: payee_id or payee_name is required")
+	ErrSplitAmount       = errors.New("transaction: subtransaction amounts must sum to the transaction amount")
+	ErrMemoTooLong       = errors.New("transaction: memo is too long")
 )
 
+// MaxMemoLength is the maximum number of characters YNAB accepts in the memo
+// of a transaction or subtransaction
+const MaxMemoLength = 500
+
 // PayloadTransaction is the payload contract for saving a transaction, new or existent
+//
+// A nil Memo or FlagColor omits the field so the existing value is left
+// untouched. Set ClearMemo or ClearFlagColor to explicitly clear them.
+//
+// On update, setting SubTransactions turns the transaction into a split,
+// or updates the subtransactions carrying an ID, and ClearSubTransactions
+// turns a split back into a single category transaction.
 type PayloadTransaction struct {
 	ID        string   `json:"id"`
 	AccountID string   `json:"account_id"`
@@ -14,18 +41,27 @@ type PayloadTransaction struct {
 	Cleared  ClearingStatus `json:"cleared"`
 	Approved bool           `json:"approved"`
 
-	// PayeeID Transfer payees are not permitted and will be ignored if supplied
+	// PayeeID To create a transfer between two accounts, use the transfer
+	// payee of the target account, see NewTransfer. When set, it takes
+	// precedence and PayeeName is not sent.
 	PayeeID *string `json:"payee_id"`
 	// PayeeName If the payee name is provided and payee ID has a null value, the
 	// payee name value will be used to resolve the payee by either (1) a matching
 	// payee rename rule (only if import_id is also specified) or (2) a payee with
-	// the same name or (3) creation of a new payee
+	// the same name or (3) creation of a new payee. Prefer WithNewPayee and
+	// WithExistingPayee, which set exactly one of the two fields.
 	PayeeName *string `json:"payee_name"`
 	// CategoryID Split and Credit Card Payment categories are not permitted and
 	// will be ignored if supplied.
 	CategoryID *string    `json:"category_id"`
 	Memo       *string    `json:"memo"`
 	FlagColor  *FlagColor `json:"flag_color"`
+	// ClearMemo sends an empty memo, clearing the existing one. It takes
+	// precedence over Memo.
+	ClearMemo bool `json:"-"`
+	// ClearFlagColor sends a null flag color, removing the existing flag.
+	// It takes precedence over FlagColor.
+	ClearFlagColor bool `json:"-"`
 	// ImportID If the Transaction was imported, this field is a unique (by account) import
 	// identifier. If this transaction was imported through File Based Import or
 	// Direct Import and not through the API, the import_id will have the format:
@@ -36,12 +72,311 @@ type PayloadTransaction struct {
 	// be 'YNAB:-294230:2015-12-30:2’.
 	ImportID *string `json:"import_id"`
 	// SubTransactions An array of subtransactions to configure a transaction as a split.
-	// Updating subtransactions on an existing split transaction is not supported.
+	// An empty array is omitted; see ClearSubTransactions.
 	SubTransactions []*PayloadSubTransaction `json:"subtransactions,omitempty"`
+	// ClearSubTransactions sends an empty subtransactions array, unsplitting
+	// an existing split transaction; set CategoryID along with it. It takes
+	// precedence over SubTransactions.
+	ClearSubTransactions bool `json:"-"`
+}
+
+// Validate checks a payload creating a transaction before it is sent,
+// returning every problem found joined into a single error. The account,
+// date, clearing status and payee are required, and the other fields set
+// must be valid, see ValidateUpdate.
+func (p PayloadTransaction) Validate() error {
+	var errs []error
+	if p.AccountID == "" {
+		errs = append(errs, ErrAccountIDRequired)
+	}
+	if p.Date.IsZero() {
+		errs = append(errs, ErrDateRequired)
+	}
+	if p.Cleared == "" {
+		errs = append(errs, ErrClearedRequired)
+	}
+	if p.PayeeID == nil && p.PayeeName == nil {
+		errs = append(errs, ErrPayeeRequired)
+	}
+	return errors.Join(append(errs, p.fieldErrors()...)...)
+}
+
+// ValidateUpdate checks a payload updating a transaction before it is sent,
+// returning every problem found joined into a single error. Only the fields
+// set are checked, so a partial update passes. Enum values this package does
+// not know are accepted when well formed, so a value decoded from the API is
+// sent back unchanged; a malformed one wraps ErrInvalidEnum.
+func (p PayloadTransaction) ValidateUpdate() error {
+	return errors.Join(p.fieldErrors()...)
+}
+
+// fieldErrors returns the problems of the fields set in the payload
+func (p PayloadTransaction) fieldErrors() []error {
+	var errs []error
+	if p.Cleared != "" && !p.Cleared.IsValid() && !wellFormedEnum(string(p.Cleared)) {
+		errs = append(errs, fmt.Errorf("%w: malformed clearing status %q", ErrInvalidEnum, string(p.Cleared)))
+	}
+	if p.FlagColor != nil && !p.FlagColor.IsValid() && !wellFormedEnum(string(*p.FlagColor)) {
+		errs = append(errs, fmt.Errorf("%w: malformed flag color %q", ErrInvalidEnum, string(*p.FlagColor)))
+	}
+	if !p.ClearSubTransactions && len(p.SubTransactions) > 0 {
+		var sum int64
+		for _, sub := range p.SubTransactions {
+			if sub != nil {
+				sum += sub.Amount
+			}
+		}
+		if sum != p.Amount {
+			errs = append(errs, fmt.Errorf("%w: %d != %d", ErrSplitAmount, sum, p.Amount))
+		}
+	}
+	if !p.ClearMemo {
+		if err := validateMemo(p.Memo); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for i, sub := range p.SubTransactions {
+		if sub == nil {
+			continue
+		}
+		if err := validateMemo(sub.Memo); err != nil {
+			errs = append(errs, fmt.Errorf("subtransaction %d: %w", i, err))
+		}
+	}
+	return errs
+}
+
+// wellFormedEnum reports whether s looks like an enum value of the API, made
+// of ASCII letters, digits and underscores
+func wellFormedEnum(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// validateMemo reports a memo longer than MaxMemoLength characters
+func validateMemo(memo *string) error {
+	if memo == nil {
+		return nil
+	}
+	if n := utf8.RuneCountInString(*memo); n > MaxMemoLength {
+		return fmt.Errorf("%w: %d characters, limit %d", ErrMemoTooLong, n, MaxMemoLength)
+	}
+	return nil
+}
+
+// truncateMemos returns a copy of the payload with every memo longer than
+// MaxMemoLength characters cut to that length, never splitting a
+// multibyte character. The payload itself is left unchanged.
+func (p PayloadTransaction) truncateMemos() PayloadTransaction {
+	c := p.Clone()
+	c.Memo = truncateMemo(c.Memo)
+	for _, sub := range c.SubTransactions {
+		if sub != nil {
+			sub.Memo = truncateMemo(sub.Memo)
+		}
+	}
+	return c
+}
+
+// truncateMemo cuts memo to MaxMemoLength characters in place
+func truncateMemo(memo *string) *string {
+	if memo == nil || utf8.RuneCountInString(*memo) <= MaxMemoLength {
+		return memo
+	}
+
+	n := 0
+	for i := range *memo {
+		if n == MaxMemoLength {
+			*memo = (*memo)[:i]
+			break
+		}
+		n++
+	}
+	return memo
+}
+
+// validatePayloads validates every payload with validate, identifying the
+// first invalid one by its index
+func validatePayloads(ps []PayloadTransaction, validate func(PayloadTransaction) error) error {
+	for i, p := range ps {
+		if err := validate(p); err != nil {
+			return fmt.Errorf("transaction %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// WithNewPayee returns a copy of the payload resolving its payee by name,
+// which YNAB matches to an existing payee or uses to create a new one.
+// Any PayeeID is cleared.
+func (p PayloadTransaction) WithNewPayee(name string) PayloadTransaction {
+	p.PayeeID = nil
+	p.PayeeName = &name
+	return p
+}
+
+// WithExistingPayee returns a copy of the payload assigned to the payee
+// with the given ID. Any PayeeName is cleared.
+func (p PayloadTransaction) WithExistingPayee(id string) PayloadTransaction {
+	p.PayeeID = &id
+	p.PayeeName = nil
+	return p
+}
+
+// NewTransfer returns an uncleared payload transferring amount from the
+// account with ID fromAccountID to toAccount, by using the transfer payee
+// of toAccount. The amount is seen from the source account, so a negative
+// amount moves money into toAccount. YNAB creates the matching transaction
+// in toAccount. The payee is left unset when toAccount has no transfer
+// payee, which Validate reports.
+func NewTransfer(fromAccountID string, toAccount *account.Account, amount int64, date api.Date) PayloadTransaction {
+	p := PayloadTransaction{
+		AccountID: fromAccountID,
+		Date:      date,
+		Amount:    amount,
+		Cleared:   ClearingStatusUncleared,
+	}
+	if toAccount != nil && toAccount.TransferPayeeID != nil {
+		p = p.WithExistingPayee(*toAccount.TransferPayeeID)
+	}
+	return p
+}
+
+// MarshalJSON omits a nil Memo or FlagColor and encodes the explicit
+// clears requested through ClearMemo, ClearFlagColor and
+// ClearSubTransactions. PayeeName is omitted when PayeeID is set, as YNAB
+// ignores it then.
+func (p PayloadTransaction) MarshalJSON() ([]byte, error) {
+	// payload has the same fields as PayloadTransaction without its methods
+	type payload PayloadTransaction
+	out := struct {
+		payload
+		PayeeName       json.RawMessage `json:"payee_name,omitempty"`
+		Memo            json.RawMessage `json:"memo,omitempty"`
+		FlagColor       json.RawMessage `json:"flag_color,omitempty"`
+		SubTransactions json.RawMessage `json:"subtransactions,omitempty"`
+	}{payload: payload(p)}
+
+	if p.PayeeID == nil {
+		payeeName, err := json.Marshal(p.PayeeName)
+		if err != nil {
+			return nil, err
+		}
+		out.PayeeName = payeeName
+	}
+
+	switch {
+	case p.ClearMemo:
+		out.Memo = json.RawMessage(`""`)
+	case p.Memo != nil:
+		memo, err := json.Marshal(*p.Memo)
+		if err != nil {
+			return nil, err
+		}
+		out.Memo = memo
+	}
+
+	switch {
+	case p.ClearFlagColor:
+		out.FlagColor = json.RawMessage(`null`)
+	case p.FlagColor != nil:
+		flagColor, err := json.Marshal(*p.FlagColor)
+		if err != nil {
+			return nil, err
+		}
+		out.FlagColor = flagColor
+	}
+
+	switch {
+	case p.ClearSubTransactions:
+		out.SubTransactions = json.RawMessage(`[]`)
+	case len(p.SubTransactions) > 0:
+		subTransactions, err := json.Marshal(p.SubTransactions)
+		if err != nil {
+			return nil, err
+		}
+		out.SubTransactions = subTransactions
+	}
+
+	// Marshal through a pointer so api.Date's pointer MarshalJSON applies
+	return json.Marshal(&out)
+}
+
+// Clone returns a deep copy of the payload so pointer fields and
+// subtransactions can be modified without affecting p
+func (p PayloadTransaction) Clone() PayloadTransaction {
+	c := p
+	c.PayeeID = clonePtr(p.PayeeID)
+	c.PayeeName = clonePtr(p.PayeeName)
+	c.CategoryID = clonePtr(p.CategoryID)
+	c.Memo = clonePtr(p.Memo)
+	c.FlagColor = clonePtr(p.FlagColor)
+	c.ImportID = clonePtr(p.ImportID)
+
+	if p.SubTransactions != nil {
+		c.SubTransactions = make([]*PayloadSubTransaction, len(p.SubTransactions))
+		for i, sub := range p.SubTransactions {
+			if sub == nil {
+				continue
+			}
+			c.SubTransactions[i] = &PayloadSubTransaction{
+				ID:         sub.ID,
+				Amount:     sub.Amount,
+				PayeeID:    clonePtr(sub.PayeeID),
+				PayeeName:  clonePtr(sub.PayeeName),
+				CategoryID: clonePtr(sub.CategoryID),
+				Memo:       clonePtr(sub.Memo),
+			}
+		}
+	}
+
+	return c
+}
+
+// clonePtr returns a pointer to a copy of *p, or nil if p is nil
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+// payloadTransactionCategory is the minimal payload updating only the
+// category of an existing transaction
+type payloadTransactionCategory struct {
+	ID         string `json:"id"`
+	CategoryID string `json:"category_id"`
+}
+
+// payloadTransactionApproval is the minimal payload approving an existing
+// transaction
+type payloadTransactionApproval struct {
+	ID        string `json:"id"`
+	AccountID string `json:"account_id"`
+	Approved  bool   `json:"approved"`
+}
+
+// payloadTransactionAssignment is the minimal payload approving an
+// existing transaction and assigning it to a category
+type payloadTransactionAssignment struct {
+	ID         string `json:"id"`
+	AccountID  string `json:"account_id"`
+	CategoryID string `json:"category_id"`
+	Approved   bool   `json:"approved"`
 }
 
 // PayloadSubTransaction is the payload contract for saving a subtransaction as part of a split transaction
 type PayloadSubTransaction struct {
+	// ID identifies the existing subtransaction to update, empty for a new one
+	ID string `json:"id,omitempty"`
 	// Amount The subtransaction amount in milliunits format
 	Amount int64 `json:"amount"`
 	// PayeeID The payee for the subtransaction
@@ -0,0 +1,363 @@
+package transaction_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coltoneshaw/ynab.go/api"
+	"github.com/coltoneshaw/ynab.go/api/account"
+	"github.com/coltoneshaw/ynab.go/api/transaction"
+)
+
+func TestPayloadTransaction_MarshalJSON(t *testing.T) {
+	date, err := api.DateFromString("2024-01-15")
+	require.NoError(t, err)
+
+	memo := "Groceries"
+	flagColor := transaction.FlagColorRed
+
+	tests := []struct {
+		name      string
+		payload   transaction.PayloadTransaction
+		memo      any
+		flagColor any
+		present   bool
+	}{
+		{
+			name:    "omit",
+			payload: transaction.PayloadTransaction{},
+		},
+		{
+			name:      "set value",
+			payload:   transaction.PayloadTransaction{Memo: &memo, FlagColor: &flagColor},
+			memo:      "Groceries",
+			flagColor: "red",
+			present:   true,
+		},
+		{
+			name: "explicit clear",
+			payload: transaction.PayloadTransaction{
+				Memo:           &memo,
+				FlagColor:      &flagColor,
+				ClearMemo:      true,
+				ClearFlagColor: true,
+			},
+			memo:      "",
+			flagColor: nil,
+			present:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.payload.AccountID = "account-id"
+			test.payload.Date = date
+
+			buf, err := json.Marshal(test.payload)
+			require.NoError(t, err)
+
+			body := map[string]any{}
+			require.NoError(t, json.Unmarshal(buf, &body))
+
+			assert.Equal(t, "2024-01-15", body["date"])
+			assert.NotContains(t, body, "ClearMemo")
+			assert.NotContains(t, body, "ClearFlagColor")
+
+			memoValue, hasMemo := body["memo"]
+			flagValue, hasFlag := body["flag_color"]
+			assert.Equal(t, test.present, hasMemo)
+			assert.Equal(t, test.present, hasFlag)
+			if test.present {
+				assert.Equal(t, test.memo, memoValue)
+				assert.Equal(t, test.flagColor, flagValue)
+			}
+		})
+	}
+}
+
+func TestPayloadTransaction_PayeePrecedence(t *testing.T) {
+	date, err := api.DateFromString("2024-01-15")
+	require.NoError(t, err)
+	base := transaction.PayloadTransaction{AccountID: "account-id", Date: date}
+
+	marshal := func(t *testing.T, p transaction.PayloadTransaction) map[string]any {
+		buf, err := json.Marshal(p)
+		require.NoError(t, err)
+
+		body := map[string]any{}
+		require.NoError(t, json.Unmarshal(buf, &body))
+		return body
+	}
+
+	t.Run("existing payee", func(t *testing.T) {
+		p := base.WithNewPayee("Corner Shop").WithExistingPayee("payee-id")
+		assert.Nil(t, p.PayeeName)
+
+		body := marshal(t, p)
+		assert.Equal(t, "payee-id", body["payee_id"])
+		assert.NotContains(t, body, "payee_name")
+	})
+
+	t.Run("new payee", func(t *testing.T) {
+		p := base.WithExistingPayee("payee-id").WithNewPayee("Corner Shop")
+		assert.Nil(t, p.PayeeID)
+
+		body := marshal(t, p)
+		assert.Nil(t, body["payee_id"])
+		assert.Equal(t, "Corner Shop", body["payee_name"])
+	})
+
+	t.Run("conflicting fields send the ID only", func(t *testing.T) {
+		id, name := "payee-id", "Corner Shop"
+		p := base
+		p.PayeeID, p.PayeeName = &id, &name
+
+		body := marshal(t, p)
+		assert.Equal(t, "payee-id", body["payee_id"])
+		assert.NotContains(t, body, "payee_name")
+	})
+
+	t.Run("no payee", func(t *testing.T) {
+		body := marshal(t, base)
+		assert.Contains(t, body, "payee_id")
+		assert.Contains(t, body, "payee_name")
+		assert.Nil(t, body["payee_name"])
+	})
+
+	t.Run("builders copy the payload", func(t *testing.T) {
+		original := base.WithExistingPayee("payee-id")
+		_ = original.WithNewPayee("Corner Shop")
+		require.NotNil(t, original.PayeeID)
+		assert.Equal(t, "payee-id", *original.PayeeID)
+		assert.Nil(t, original.PayeeName)
+	})
+}
+
+func TestNewTransfer(t *testing.T) {
+	date, err := api.DateFromString("2024-01-15")
+	require.NoError(t, err)
+
+	var savings account.Account
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"acc-savings","transfer_payee_id":"payee-transfer-savings"}`), &savings))
+
+	p := transaction.NewTransfer("acc-checking", &savings, -50000, date)
+	require.NoError(t, p.Validate())
+	assert.Equal(t, "acc-checking", p.AccountID)
+	assert.Equal(t, int64(-50000), p.Amount)
+	assert.Equal(t, transaction.ClearingStatusUncleared, p.Cleared)
+	require.NotNil(t, p.PayeeID)
+	assert.Equal(t, "payee-transfer-savings", *p.PayeeID)
+	assert.Nil(t, p.PayeeName)
+
+	buf, err := json.Marshal(p)
+	require.NoError(t, err)
+	assert.Contains(t, string(buf), `"payee_id":"payee-transfer-savings"`)
+
+	// Without a transfer payee the payload does not validate
+	p = transaction.NewTransfer("acc-checking", &account.Account{ID: "acc-closed"}, -50000, date)
+	assert.ErrorIs(t, p.Validate(), transaction.ErrPayeeRequired)
+	p = transaction.NewTransfer("acc-checking", nil, -50000, date)
+	assert.ErrorIs(t, p.Validate(), transaction.ErrPayeeRequired)
+}
+
+func TestPayloadTransaction_Clone(t *testing.T) {
+	flagColor := transaction.FlagColorRed
+	source := transaction.PayloadTransaction{
+		ID:         "tx-1",
+		AccountID:  "account-id",
+		Amount:     -1000,
+		PayeeID:    strPtr("payee-id"),
+		PayeeName:  strPtr("Grocery Store"),
+		CategoryID: strPtr("category-id"),
+		Memo:       strPtr("Weekly shop"),
+		FlagColor:  &flagColor,
+		ImportID:   strPtr("YNAB:-1000:2024-01-15:1"),
+		SubTransactions: []*transaction.PayloadSubTransaction{
+			{Amount: -600, Memo: strPtr("Food"), CategoryID: strPtr("cat-food")},
+			{Amount: -400, Memo: strPtr("Soap"), CategoryID: strPtr("cat-home")},
+		},
+	}
+
+	clone := source.Clone()
+	assert.Equal(t, source, clone)
+
+	*clone.Memo = "Changed"
+	*clone.FlagColor = transaction.FlagColorBlue
+	*clone.PayeeName = "Changed"
+	*clone.CategoryID = "changed"
+	*clone.SubTransactions[0].Memo = "Changed"
+	clone.SubTransactions[1].Amount = 0
+	clone.SubTransactions = append(clone.SubTransactions, &transaction.PayloadSubTransaction{})
+
+	assert.Equal(t, "Weekly shop", *source.Memo)
+	assert.Equal(t, transaction.FlagColorRed, *source.FlagColor)
+	assert.Equal(t, "Grocery Store", *source.PayeeName)
+	assert.Equal(t, "category-id", *source.CategoryID)
+	assert.Equal(t, "Food", *source.SubTransactions[0].Memo)
+	assert.Equal(t, int64(-400), source.SubTransactions[1].Amount)
+	assert.Len(t, source.SubTransactions, 2)
+
+	empty := transaction.PayloadTransaction{}.Clone()
+	assert.Nil(t, empty.Memo)
+	assert.Nil(t, empty.FlagColor)
+	assert.Nil(t, empty.SubTransactions)
+}
+
+func TestPayloadTransaction_Validate(t *testing.T) {
+	date, err := api.DateFromString("2024-01-15")
+	require.NoError(t, err)
+
+	valid := func() transaction.PayloadTransaction {
+		return transaction.PayloadTransaction{
+			AccountID: "account-id",
+			Date:      date,
+			Cleared:   transaction.ClearingStatusCleared,
+			PayeeName: strPtr("Grocery Store"),
+		}
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		assert.NoError(t, valid().Validate())
+
+		p := valid()
+		p.PayeeName = nil
+		p.PayeeID = strPtr("payee-id")
+		flagColor := transaction.FlagColorNone
+		p.FlagColor = &flagColor
+		assert.NoError(t, p.Validate())
+
+		p.Amount = -1000
+		p.SubTransactions = []*transaction.PayloadSubTransaction{{Amount: -600}, {Amount: -400}}
+		assert.NoError(t, p.Validate())
+
+		p.SubTransactions = p.SubTransactions[:1]
+		p.ClearSubTransactions = true
+		assert.NoError(t, p.Validate())
+
+		// The limit counts characters, not bytes
+		p.Memo = strPtr(strings.Repeat("é", transaction.MaxMemoLength))
+		assert.NoError(t, p.Validate())
+
+		// Values the API added after this package are sent back unchanged
+		p.Cleared = "pending"
+		flagColor = "pink"
+		assert.NoError(t, p.Validate())
+	})
+
+	tests := []struct {
+		name   string
+		modify func(p *transaction.PayloadTransaction)
+		want   error
+	}{
+		{
+			name:   "missing account id",
+			modify: func(p *transaction.PayloadTransaction) { p.AccountID = "" },
+			want:   transaction.ErrAccountIDRequired,
+		},
+		{
+			name:   "missing date",
+			modify: func(p *transaction.PayloadTransaction) { p.Date = api.Date{} },
+			want:   transaction.ErrDateRequired,
+		},
+		{
+			name:   "missing cleared",
+			modify: func(p *transaction.PayloadTransaction) { p.Cleared = "" },
+			want:   transaction.ErrClearedRequired,
+		},
+		{
+			name:   "malformed cleared",
+			modify: func(p *transaction.PayloadTransaction) { p.Cleared = "not cleared" },
+			want:   transaction.ErrInvalidEnum,
+		},
+		{
+			name: "malformed flag color",
+			modify: func(p *transaction.PayloadTransaction) {
+				flagColor := transaction.FlagColor("pink!")
+				p.FlagColor = &flagColor
+			},
+			want: transaction.ErrInvalidEnum,
+		},
+		{
+			name:   "missing payee",
+			modify: func(p *transaction.PayloadTransaction) { p.PayeeName = nil },
+			want:   transaction.ErrPayeeRequired,
+		},
+		{
+			name: "split amounts not summing to the amount",
+			modify: func(p *transaction.PayloadTransaction) {
+				p.Amount = -1000
+				p.SubTransactions = []*transaction.PayloadSubTransaction{{Amount: -600}, {Amount: -300}}
+			},
+			want: transaction.ErrSplitAmount,
+		},
+		{
+			name: "memo too long",
+			modify: func(p *transaction.PayloadTransaction) {
+				p.Memo = strPtr(strings.Repeat("é", transaction.MaxMemoLength+1))
+			},
+			want: transaction.ErrMemoTooLong,
+		},
+		{
+			name: "subtransaction memo too long",
+			modify: func(p *transaction.PayloadTransaction) {
+				p.SubTransactions = []*transaction.PayloadSubTransaction{
+					{Memo: strPtr(strings.Repeat("a", transaction.MaxMemoLength+1))},
+				}
+			},
+			want: transaction.ErrMemoTooLong,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := valid()
+			test.modify(&p)
+			assert.ErrorIs(t, p.Validate(), test.want)
+		})
+	}
+
+	t.Run("joined", func(t *testing.T) {
+		err := transaction.PayloadTransaction{
+			Cleared: transaction.ClearingStatusUncleared,
+		}.Validate()
+		assert.ErrorIs(t, err, transaction.ErrAccountIDRequired)
+		assert.ErrorIs(t, err, transaction.ErrDateRequired)
+		assert.ErrorIs(t, err, transaction.ErrPayeeRequired)
+		assert.NotErrorIs(t, err, transaction.ErrInvalidEnum)
+
+		joined, ok := err.(interface{ Unwrap() []error })
+		require.True(t, ok)
+		assert.Len(t, joined.Unwrap(), 3)
+	})
+}
+
+func TestPayloadTransaction_ValidateUpdate(t *testing.T) {
+	// A partial update only sets the fields it changes
+	memo := "updated"
+	assert.NoError(t, transaction.PayloadTransaction{Memo: &memo}.ValidateUpdate())
+
+	flagColor := transaction.FlagColor("pink")
+	assert.NoError(t, transaction.PayloadTransaction{FlagColor: &flagColor}.ValidateUpdate())
+
+	// A transaction decoded with a flag color added by the API round-trips
+	var tx transaction.Transaction
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"tx-1","date":"2024-01-15","cleared":"cleared","flag_color":"teal"}`), &tx))
+	assert.NoError(t, tx.ToPayload().ValidateUpdate())
+
+	flagColor = "pink!"
+	assert.ErrorIs(t, transaction.PayloadTransaction{FlagColor: &flagColor}.ValidateUpdate(), transaction.ErrInvalidEnum)
+
+	err := transaction.PayloadTransaction{
+		Amount:          -1000,
+		SubTransactions: []*transaction.PayloadSubTransaction{{Amount: -600}},
+		Memo:            strPtr(strings.Repeat("a", transaction.MaxMemoLength+1)),
+	}.ValidateUpdate()
+	assert.ErrorIs(t, err, transaction.ErrSplitAmount)
+	assert.ErrorIs(t, err, transaction.ErrMemoTooLong)
+	assert.NotErrorIs(t, err, transaction.ErrAccountIDRequired)
+	assert.NotErrorIs(t, err, transaction.ErrPayeeRequired)
+}
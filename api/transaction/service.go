@@ -1,21 +1,55 @@
 package transaction
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/coltoneshaw/ynab.go/api"
 )
 
 // NewService facilitates the creation of a new transaction service instance
 func NewService(c api.ClientReaderWriter) *Service {
-	return &Service{c}
+	return &Service{c: c}
 }
 
 // Service wraps YNAB transaction API endpoints
 type Service struct {
 	c api.ClientReaderWriter
+
+	dataLimitPaging bool
+	clock           api.Clock
+
+	// accountWorkers bounds the requests of GetTransactionsForAccounts,
+	// defaultAccountWorkers unless WithAccountConcurrency is used
+	accountWorkers int
+
+	// autoApprove approves every created transaction, false unless
+	// WithAutoApprove is used
+	autoApprove bool
+
+	// truncateMemos cuts memos to MaxMemoLength before sending them, false
+	// unless WithMemoTruncation is used
+	truncateMemos bool
+
+	// names caches the name lookups of ResolveNames by budget, nil unless
+	// WithNameCache is used
+	namesMu sync.Mutex
+	names   map[string]*nameLookup
+}
+
+// fullReads returns a view of s whose requests bypass the knowledge cache
+// of the client, for the methods returning transactions without their
+// server knowledge. Only its read methods are used.
+func (s *Service) fullReads() *Service {
+	return &Service{
+		c:               api.FullReads(s.c),
+		dataLimitPaging: s.dataLimitPaging,
+		clock:           s.clock,
+		accountWorkers:  s.accountWorkers,
+	}
 }
 
 // SearchResultSnapshot represents the result of a search with server knowledge
@@ -24,10 +58,37 @@ type SearchResultSnapshot struct {
 	ServerKnowledge uint64
 }
 
+// List returns the snapshot as a generic api.ListResult
+func (s *SearchResultSnapshot) List() *api.ListResult[*Transaction] {
+	return &api.ListResult[*Transaction]{Items: s.Transactions, ServerKnowledge: s.ServerKnowledge}
+}
+
+// HybridSearchResultSnapshot represents the result of a category or payee
+// transaction search with server knowledge
+type HybridSearchResultSnapshot struct {
+	Transactions    []*Hybrid
+	ServerKnowledge uint64
+}
+
+// List returns the snapshot as a generic api.ListResult
+func (s *HybridSearchResultSnapshot) List() *api.ListResult[*Hybrid] {
+	return &api.ListResult[*Hybrid]{Items: s.Transactions, ServerKnowledge: s.ServerKnowledge}
+}
+
 // GetTransactions fetches the list of transactions from
 // a budget with filtering capabilities
 // https://api.youneedabudget.com/v1#/Transactions/getTransactions
 func (s *Service) GetTransactions(budgetID string, f *Filter) (*SearchResultSnapshot, error) {
+	result, err := s.getTransactions(budgetID, f)
+	var apiErr *api.Error
+	if err != nil && s.dataLimitPaging && errors.As(err, &apiErr) && apiErr.IsDataLimitReached() {
+		return s.pageByDataLimit(budgetID, f, err)
+	}
+	return result, err
+}
+
+// getTransactions issues a single request to the transactions endpoint
+func (s *Service) getTransactions(budgetID string, f *Filter) (*SearchResultSnapshot, error) {
 	resModel := struct {
 		Data struct {
 			Transactions    []*Transaction `json:"transactions"`
@@ -45,11 +106,174 @@ func (s *Service) GetTransactions(budgetID string, f *Filter) (*SearchResultSnap
 	}
 
 	return &SearchResultSnapshot{
-		Transactions:    resModel.Data.Transactions,
+		Transactions:    filterAmount(f, resModel.Data.Transactions, transactionAmount),
 		ServerKnowledge: resModel.Data.ServerKnowledge,
 	}, nil
 }
 
+// GetTransactionsByFlag fetches the transactions of a budget matching the
+// filter and keeps those flagged with color. The API has no flag filter, so
+// the transactions are filtered client-side; FlagColorNone selects the
+// unflagged ones. A color that is not valid fails with ErrInvalidEnum.
+// https://api.youneedabudget.com/v1#/Transactions/getTransactions
+func (s *Service) GetTransactionsByFlag(budgetID string, color FlagColor, f *Filter) ([]*Transaction, error) {
+	if !color.IsValid() {
+		return nil, fmt.Errorf("%w: unknown flag color %q", ErrInvalidEnum, string(color))
+	}
+
+	snapshot, err := s.fullReads().GetTransactions(budgetID, f)
+	if err != nil {
+		return nil, err
+	}
+
+	flagged := make([]*Transaction, 0, len(snapshot.Transactions))
+	for _, t := range snapshot.Transactions {
+		current := FlagColorNone
+		if t.FlagColor != nil {
+			current = *t.FlagColor
+		}
+		if current == color {
+			flagged = append(flagged, t)
+		}
+	}
+	return flagged, nil
+}
+
+// FindByImportIDs returns the existing transactions of a budget carrying
+// one of the given import IDs, keyed by import ID. Import IDs without a
+// match are absent from the map, and no request is made for an empty list.
+//
+// When every import ID has the YNAB:[amount]:[date]:[occurrence] format its
+// dates bound the request to the oldest of them; otherwise every
+// transaction of the budget is fetched. Import IDs are unique by account,
+// so when several accounts share one the first transaction found is kept.
+// https://api.youneedabudget.com/v1#/Transactions/getTransactions
+func (s *Service) FindByImportIDs(budgetID string, importIDs []string) (map[string]*Transaction, error) {
+	found := make(map[string]*Transaction, len(importIDs))
+	if len(importIDs) == 0 {
+		return found, nil
+	}
+
+	wanted := make(map[string]struct{}, len(importIDs))
+	var since *api.Date
+	bounded := true
+	for _, id := range importIDs {
+		wanted[id] = struct{}{}
+
+		date, ok := importIDDate(id)
+		if !ok {
+			bounded = false
+			continue
+		}
+		if since == nil || date.Before(since.Time) {
+			since = &date
+		}
+	}
+
+	var f *Filter
+	if bounded {
+		f = &Filter{Since: since}
+	}
+
+	snapshot, err := s.fullReads().GetTransactions(budgetID, f)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range snapshot.Transactions {
+		if t.Deleted || t.ImportID == nil {
+			continue
+		}
+		if _, ok := wanted[*t.ImportID]; !ok {
+			continue
+		}
+		if _, ok := found[*t.ImportID]; !ok {
+			found[*t.ImportID] = t
+		}
+	}
+	return found, nil
+}
+
+// importIDDate returns the date of an import ID in the
+// YNAB:[milliunit_amount]:[iso_date]:[occurrence] format
+func importIDDate(importID string) (api.Date, bool) {
+	parts := strings.Split(importID, ":")
+	if len(parts) != 4 || parts[0] != "YNAB" {
+		return api.Date{}, false
+	}
+
+	date, err := api.DateFromString(parts[2])
+	if err != nil {
+		return api.Date{}, false
+	}
+	return date, true
+}
+
+// CountNeedingAttention returns the number of uncategorized and unapproved
+// transactions of a budget, issuing one type-filtered request for each
+// https://api.youneedabudget.com/v1#/Transactions/getTransactions
+func (s *Service) CountNeedingAttention(budgetID string) (uncategorized int, unapproved int, err error) {
+	full := s.fullReads()
+	snapshot, err := full.GetTransactions(budgetID, &Filter{Type: StatusUncategorized.Pointer()})
+	if err != nil {
+		return 0, 0, err
+	}
+	uncategorized = len(snapshot.Transactions)
+
+	snapshot, err = full.GetTransactions(budgetID, &Filter{Type: StatusUnapproved.Pointer()})
+	if err != nil {
+		return 0, 0, err
+	}
+	unapproved = len(snapshot.Transactions)
+
+	return uncategorized, unapproved, nil
+}
+
+// GetDeletedSince fetches the transactions deleted since the given server
+// knowledge, returning them along with the new server knowledge.
+// Deleted transactions are only included in delta requests, so this
+// always sends lastKnowledge as last_knowledge_of_server.
+// https://api.youneedabudget.com/v1#/Transactions/getTransactions
+func (s *Service) GetDeletedSince(budgetID string, lastKnowledge uint64) ([]*Transaction, uint64, error) {
+	snapshot, err := s.GetTransactions(budgetID, &Filter{LastKnowledgeOfServer: &lastKnowledge})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	deleted := make([]*Transaction, 0)
+	for _, t := range snapshot.Transactions {
+		if t.Deleted {
+			deleted = append(deleted, t)
+		}
+	}
+	return deleted, snapshot.ServerKnowledge, nil
+}
+
+// HasChangesSince reports whether any transaction of a budget changed
+// since the given server knowledge, returning it along with the new server
+// knowledge. The delta is still transferred, but the transactions are only
+// counted, not decoded, and an unchanged server knowledge short-circuits to
+// false.
+// https://api.youneedabudget.com/v1#/Transactions/getTransactions
+func (s *Service) HasChangesSince(budgetID string, lastKnowledge uint64) (bool, uint64, error) {
+	resModel := struct {
+		Data struct {
+			Transactions    []struct{} `json:"transactions"`
+			ServerKnowledge uint64     `json:"server_knowledge"`
+		} `json:"data"`
+	}{}
+
+	url := fmt.Sprintf("/budgets/%s/transactions?last_knowledge_of_server=%d", budgetID, lastKnowledge)
+	if err := s.c.GET(url, &resModel); err != nil {
+		return false, 0, err
+	}
+
+	if resModel.Data.ServerKnowledge == lastKnowledge {
+		return false, lastKnowledge, nil
+	}
+	return len(resModel.Data.Transactions) > 0, resModel.Data.ServerKnowledge, nil
+}
+
 // GetTransaction fetches a specific transaction from a budget
 // https://api.youneedabudget.com/v1#/Transactions/getTransactionsById
 func (s *Service) GetTransaction(budgetID, transactionID string) (*Transaction, error) {
@@ -66,26 +290,81 @@ func (s *Service) GetTransaction(budgetID, transactionID string) (*Transaction,
 	return resModel.Data.Transaction, nil
 }
 
+// WithAutoApprove makes CreateTransaction, CreateTransactions and
+// BulkCreateTransactions send every transaction as approved, whatever the
+// Approved field of its payload, e.g. when importing transactions that are
+// already reviewed. The payloads passed in are left unchanged.
+func (s *Service) WithAutoApprove() *Service {
+	s.autoApprove = true
+	return s
+}
+
+// approve returns ps with every transaction approved when auto approval is
+// enabled, and ps itself otherwise
+func (s *Service) approve(ps []PayloadTransaction) []PayloadTransaction {
+	if !s.autoApprove {
+		return ps
+	}
+
+	approved := make([]PayloadTransaction, len(ps))
+	for i, p := range ps {
+		p.Approved = true
+		approved[i] = p
+	}
+	return approved
+}
+
+// WithMemoTruncation makes the create and update methods cut memos longer
+// than MaxMemoLength characters to that length before sending them, never
+// splitting a multibyte character, instead of failing validation with
+// ErrMemoTooLong. The payloads passed in are left unchanged.
+func (s *Service) WithMemoTruncation() *Service {
+	s.truncateMemos = true
+	return s
+}
+
+// prepare returns ps with memos truncated when memo truncation is enabled,
+// and ps itself otherwise
+func (s *Service) prepare(ps []PayloadTransaction) []PayloadTransaction {
+	if !s.truncateMemos {
+		return ps
+	}
+
+	truncated := make([]PayloadTransaction, len(ps))
+	for i, p := range ps {
+		truncated[i] = p.truncateMemos()
+	}
+	return truncated
+}
+
 // CreateTransaction creates a new transaction for a budget
 // https://api.youneedabudget.com/v1#/Transactions/createTransaction
 func (s *Service) CreateTransaction(budgetID string,
-	p PayloadTransaction) (*OperationSummary, error) {
+	p PayloadTransaction, opts ...api.WriteOption) (*OperationSummary, error) {
 
-	return s.CreateTransactions(budgetID, []PayloadTransaction{p})
+	return s.CreateTransactions(budgetID, []PayloadTransaction{p}, opts...)
 }
 
-// CreateTransactions creates one or more new transactions for a budget
+// CreateTransactions creates one or more new transactions for a budget.
+// The request carries an Idempotency-Key header, set with
+// api.WithIdempotencyKey or derived from the import IDs when every
+// transaction has one; YNAB itself deduplicates by import_id.
 // https://api.youneedabudget.com/v1#/Transactions/createTransaction
 func (s *Service) CreateTransactions(budgetID string,
-	p []PayloadTransaction) (*OperationSummary, error) {
+	p []PayloadTransaction, opts ...api.WriteOption) (*OperationSummary, error) {
+
+	p = s.prepare(p)
+	if err := validatePayloads(p, PayloadTransaction.Validate); err != nil {
+		return nil, err
+	}
 
 	payload := struct {
 		Transactions []PayloadTransaction `json:"transactions"`
 	}{
-		p,
+		s.approve(p),
 	}
 
-	buf, err := json.Marshal(&payload)
+	buf, err := api.Marshal(s.c, &payload)
 	if err != nil {
 		return nil, err
 	}
@@ -95,26 +374,46 @@ func (s *Service) CreateTransactions(budgetID string,
 	}{}
 
 	url := fmt.Sprintf("/budgets/%s/transactions", budgetID)
-	err = s.c.POST(url, &resModel, buf)
+	err = api.POSTWithHeader(s.c, url, &resModel, buf, writeOptions(budgetID, p, opts).Header())
 	if err != nil {
 		return nil, err
 	}
 	return resModel.Data, nil
 }
 
+// writeOptions applies opts, deriving the idempotency key from the import
+// IDs of the payloads when none is given
+func writeOptions(budgetID string, ps []PayloadTransaction, opts []api.WriteOption) api.WriteOptions {
+	o := api.NewWriteOptions(opts...)
+	if o.IdempotencyKey != "" || len(ps) == 0 {
+		return o
+	}
+
+	parts := []string{budgetID}
+	for _, p := range ps {
+		if p.ImportID == nil || *p.ImportID == "" {
+			return o
+		}
+		parts = append(parts, p.AccountID+":"+*p.ImportID)
+	}
+	o.IdempotencyKey = api.IdempotencyKey(parts...)
+	return o
+}
+
 // BulkCreateTransactions creates multiple transactions for a budget
 // https://api.youneedabudget.com/v1#/Transactions/bulkCreateTransactions
 // Deprecated: Use transaction.CreateTransactions instead.
 func (s *Service) BulkCreateTransactions(budgetID string,
-	ps []PayloadTransaction) (*Bulk, error) {
+	ps []PayloadTransaction, opts ...api.WriteOption) (*Bulk, error) {
 
+	ps = s.prepare(ps)
 	payload := struct {
 		Transactions []PayloadTransaction `json:"transactions"`
 	}{
-		ps,
+		s.approve(ps),
 	}
 
-	buf, err := json.Marshal(&payload)
+	buf, err := api.Marshal(s.c, &payload)
 	if err != nil {
 		return nil, err
 	}
@@ -126,7 +425,7 @@ func (s *Service) BulkCreateTransactions(budgetID string,
 	}{}
 
 	url := fmt.Sprintf("/budgets/%s/transactions/bulk", budgetID)
-	if err := s.c.POST(url, &resModel, buf); err != nil {
+	if err := api.POSTWithHeader(s.c, url, &resModel, buf, writeOptions(budgetID, ps, opts).Header()); err != nil {
 		return nil, err
 	}
 	return resModel.Data.Bulk, nil
@@ -137,13 +436,20 @@ func (s *Service) BulkCreateTransactions(budgetID string,
 func (s *Service) UpdateTransaction(budgetID, transactionID string,
 	p PayloadTransaction) (*Transaction, error) {
 
+	if s.truncateMemos {
+		p = p.truncateMemos()
+	}
+	if err := p.ValidateUpdate(); err != nil {
+		return nil, err
+	}
+
 	payload := struct {
 		Transaction *PayloadTransaction `json:"transaction"`
 	}{
 		&p,
 	}
 
-	buf, err := json.Marshal(&payload)
+	buf, err := api.Marshal(s.c, &payload)
 	if err != nil {
 		return nil, err
 	}
@@ -166,13 +472,18 @@ func (s *Service) UpdateTransaction(budgetID, transactionID string,
 func (s *Service) UpdateTransactions(budgetID string,
 	p []PayloadTransaction) (*OperationSummary, error) {
 
+	p = s.prepare(p)
+	if err := validatePayloads(p, PayloadTransaction.ValidateUpdate); err != nil {
+		return nil, err
+	}
+
 	payload := struct {
 		Transactions []PayloadTransaction `json:"transactions"`
 	}{
 		p,
 	}
 
-	buf, err := json.Marshal(&payload)
+	buf, err := api.Marshal(s.c, &payload)
 	if err != nil {
 		return nil, err
 	}
@@ -229,12 +540,107 @@ func (s *Service) GetTransactionsByAccount(budgetID, accountID string,
 	}
 
 	return &SearchResultSnapshot{
-		Transactions:    resModel.Data.Transactions,
+		Transactions:    filterAmount(f, resModel.Data.Transactions, transactionAmount),
 		ServerKnowledge: resModel.Data.ServerKnowledge,
 	}, nil
 }
 
-// GetTransactionsByMonth fetches the list of transactions for a specific month from a budget
+// ErrNoLinkedAccounts is returned by ImportTransactions when the budget
+// has no linked account to import from
+var ErrNoLinkedAccounts = errors.New("transaction: budget has no linked accounts")
+
+// ErrAccountNotFound is returned by GetAccountRegister when the account does
+// not exist or was deleted
+var ErrAccountNotFound = errors.New("transaction: account not found")
+
+// AccountRegister represents the transactions of an account, newest first,
+// each with the account balance after it
+type AccountRegister struct {
+	AccountID string
+	// Closed whether the account is closed
+	Closed bool
+	// Balance the current balance of the account in milliunits format
+	Balance         int64
+	Entries         []*RegisterEntry
+	ServerKnowledge uint64
+}
+
+// RegisterEntry represents a transaction of an account register
+type RegisterEntry struct {
+	Transaction *Transaction
+	// Balance the account balance after the transaction in milliunits format
+	Balance int64
+}
+
+// GetAccountRegister fetches the transactions of an account sorted newest
+// first, with running balances worked backward from the current account
+// balance. The balances are only meaningful when f does not narrow the
+// transactions down to a delta. A missing or deleted account fails with
+// an error wrapping ErrAccountNotFound, while an account without
+// transactions yields an empty register.
+// https://api.youneedabudget.com/v1#/Transactions/getTransactionsByAccount
+func (s *Service) GetAccountRegister(budgetID, accountID string,
+	f *Filter) (*AccountRegister, error) {
+
+	resModel := struct {
+		Data struct {
+			Account struct {
+				Balance int64 `json:"balance"`
+				Closed  bool  `json:"closed"`
+				Deleted bool  `json:"deleted"`
+			} `json:"account"`
+		} `json:"data"`
+	}{}
+
+	url := fmt.Sprintf("/budgets/%s/accounts/%s", budgetID, accountID)
+	if err := s.c.GET(url, &resModel); err != nil {
+		return nil, accountNotFound(accountID, err)
+	}
+	account := resModel.Data.Account
+	if account.Deleted {
+		return nil, fmt.Errorf("%w: %q", ErrAccountNotFound, accountID)
+	}
+
+	snapshot, err := s.fullReads().GetTransactionsByAccount(budgetID, accountID, f)
+	if err != nil {
+		return nil, accountNotFound(accountID, err)
+	}
+
+	sorted, balances := SortedRunningBalances(0, snapshot.Transactions)
+
+	// Shift the balances so the newest one matches the account balance
+	var offset int64
+	if len(balances) > 0 {
+		offset = account.Balance - balances[len(balances)-1]
+	}
+
+	entries := make([]*RegisterEntry, len(sorted))
+	for i, t := range sorted {
+		entries[i] = &RegisterEntry{Transaction: t, Balance: balances[i] + offset}
+	}
+	slices.Reverse(entries)
+
+	return &AccountRegister{
+		AccountID:       accountID,
+		Closed:          account.Closed,
+		Balance:         account.Balance,
+		Entries:         entries,
+		ServerKnowledge: snapshot.ServerKnowledge,
+	}, nil
+}
+
+// accountNotFound wraps a not found API error with ErrAccountNotFound,
+// returning any other error unchanged
+func accountNotFound(accountID string, err error) error {
+	var apiErr *api.Error
+	if errors.As(err, &apiErr) && apiErr.IsNotFound() {
+		return fmt.Errorf("%w: %q: %w", ErrAccountNotFound, accountID, err)
+	}
+	return err
+}
+
+// GetTransactionsByMonth fetches the list of transactions for a specific month from a budget.
+// The month may be given as "YYYY-MM-DD", "YYYY-MM" or "current", see api.MonthParam.
 // https://api.youneedabudget.com/v1#/Transactions/getTransactionsByMonth
 func (s *Service) GetTransactionsByMonth(budgetID, month string, f *Filter) (*SearchResultSnapshot, error) {
 	resModel := struct {
@@ -244,7 +650,12 @@ func (s *Service) GetTransactionsByMonth(budgetID, month string, f *Filter) (*Se
 		} `json:"data"`
 	}{}
 
-	url := fmt.Sprintf("/budgets/%s/months/%s/transactions", budgetID, month)
+	monthParam, err := api.MonthParam(month)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("/budgets/%s/months/%s/transactions", budgetID, monthParam)
 	if f != nil {
 		url = fmt.Sprintf("%s?%s", url, f.ToQuery())
 	}
@@ -254,7 +665,7 @@ func (s *Service) GetTransactionsByMonth(budgetID, month string, f *Filter) (*Se
 	}
 
 	return &SearchResultSnapshot{
-		Transactions:    resModel.Data.Transactions,
+		Transactions:    filterAmount(f, resModel.Data.Transactions, transactionAmount),
 		ServerKnowledge: resModel.Data.ServerKnowledge,
 	}, nil
 }
@@ -265,9 +676,24 @@ func (s *Service) GetTransactionsByMonth(budgetID, month string, f *Filter) (*Se
 func (s *Service) GetTransactionsByCategory(budgetID, categoryID string,
 	f *Filter) ([]*Hybrid, error) {
 
+	snapshot, err := s.fullReads().GetTransactionsByCategoryDelta(budgetID, categoryID, f)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.Transactions, nil
+}
+
+// GetTransactionsByCategoryDelta fetches the list of transactions of a specific
+// category from a budget along with the server knowledge, so the results can be
+// synced incrementally through the LastKnowledgeOfServer of the filter
+// https://api.youneedabudget.com/v1#/Transactions/getTransactionsByCategory
+func (s *Service) GetTransactionsByCategoryDelta(budgetID, categoryID string,
+	f *Filter) (*HybridSearchResultSnapshot, error) {
+
 	resModel := struct {
 		Data struct {
-			Transactions []*Hybrid `json:"transactions"`
+			Transactions    []*Hybrid `json:"transactions"`
+			ServerKnowledge uint64    `json:"server_knowledge"`
 		} `json:"data"`
 	}{}
 
@@ -280,7 +706,10 @@ func (s *Service) GetTransactionsByCategory(budgetID, categoryID string,
 		return nil, err
 	}
 
-	return resModel.Data.Transactions, nil
+	return &HybridSearchResultSnapshot{
+		Transactions:    filterAmount(f, resModel.Data.Transactions, hybridAmount),
+		ServerKnowledge: resModel.Data.ServerKnowledge,
+	}, nil
 }
 
 // GetTransactionsByPayee fetches the list of transactions of a specific payee
@@ -289,9 +718,24 @@ func (s *Service) GetTransactionsByCategory(budgetID, categoryID string,
 func (s *Service) GetTransactionsByPayee(budgetID, payeeID string,
 	f *Filter) ([]*Hybrid, error) {
 
+	snapshot, err := s.fullReads().GetTransactionsByPayeeDelta(budgetID, payeeID, f)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.Transactions, nil
+}
+
+// GetTransactionsByPayeeDelta fetches the list of transactions of a specific
+// payee from a budget along with the server knowledge, so the results can be
+// synced incrementally through the LastKnowledgeOfServer of the filter
+// https://api.youneedabudget.com/v1#/Transactions/getTransactionsByPayee
+func (s *Service) GetTransactionsByPayeeDelta(budgetID, payeeID string,
+	f *Filter) (*HybridSearchResultSnapshot, error) {
+
 	resModel := struct {
 		Data struct {
-			Transactions []*Hybrid `json:"transactions"`
+			Transactions    []*Hybrid `json:"transactions"`
+			ServerKnowledge uint64    `json:"server_knowledge"`
 		} `json:"data"`
 	}{}
 
@@ -304,7 +748,221 @@ func (s *Service) GetTransactionsByPayee(budgetID, payeeID string,
 		return nil, err
 	}
 
-	return resModel.Data.Transactions, nil
+	return &HybridSearchResultSnapshot{
+		Transactions:    filterAmount(f, resModel.Data.Transactions, hybridAmount),
+		ServerKnowledge: resModel.Data.ServerKnowledge,
+	}, nil
+}
+
+// RecategorizeByPayee moves every transaction of a payee, optionally
+// filtered, to the given category. Only the category is sent in the
+// update; transactions already in the category and deleted ones are left
+// alone. Split transactions, those with subtransactions among the
+// transactions of the payee, are skipped and reported through the sorted
+// SkippedSplitIDs of the returned summary.
+// https://api.youneedabudget.com/v1#/Transactions/updateTransactions
+func (s *Service) RecategorizeByPayee(budgetID, payeeID, categoryID string,
+	f *Filter) (*OperationSummary, error) {
+
+	transactions, err := s.GetTransactionsByPayee(budgetID, payeeID, f)
+	if err != nil {
+		return nil, err
+	}
+
+	// Splits whose parent was not returned, e.g. filtered out by date,
+	// are still reported
+	splitIDs := make(map[string]bool)
+	for _, t := range transactions {
+		if t.Type == TypeSubTransaction && t.ParentTransactionID != nil {
+			splitIDs[*t.ParentTransactionID] = true
+		}
+	}
+	skipped := make([]string, 0, len(splitIDs))
+	for id := range splitIDs {
+		skipped = append(skipped, id)
+	}
+	slices.Sort(skipped)
+
+	var updates []payloadTransactionCategory
+	for _, t := range transactions {
+		if t.Type != TypeTransaction || splitIDs[t.ID] || t.Deleted {
+			continue
+		}
+		if t.CategoryID != nil && *t.CategoryID == categoryID {
+			continue
+		}
+		updates = append(updates, payloadTransactionCategory{
+			ID:         t.ID,
+			CategoryID: categoryID,
+		})
+	}
+
+	summary, err := patchTransactions(s, budgetID, updates, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(skipped) > 0 {
+		summary.SkippedSplitIDs = skipped
+	}
+	return summary, nil
+}
+
+// ApproveTransactions approves the transactions with the given IDs in a
+// single update, e.g. after an import. The unapproved transactions of the
+// budget are fetched first to fill in the fields the update requires; IDs
+// not among them are looked up one by one, so already approved ones are
+// left alone and those that do not exist are reported through the
+// MissingIDs of the returned summary instead of failing the batch.
+func (s *Service) ApproveTransactions(budgetID string, ids []string) (*OperationSummary, error) {
+	found, missing, err := s.lookupTransactions(budgetID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var updates []payloadTransactionApproval
+	for _, t := range found {
+		if t.Approved {
+			continue
+		}
+		updates = append(updates, payloadTransactionApproval{
+			ID:        t.ID,
+			AccountID: t.AccountID,
+			Approved:  true,
+		})
+	}
+	return patchTransactions(s, budgetID, updates, missing)
+}
+
+// ApproveAndCategorize assigns each transaction of assignments, keyed by
+// transaction ID, to the category ID it maps to and approves it, all in a
+// single update, e.g. to clean up after an import. Transactions are looked
+// up as with ApproveTransactions: those already approved in the given
+// category are left alone, and IDs that do not exist are reported through
+// the MissingIDs of the returned summary. Split transactions cannot take a
+// single category, so they are left alone and reported through the
+// SkippedSplitIDs of the summary. Transactions are updated in the order of
+// their IDs.
+func (s *Service) ApproveAndCategorize(budgetID string, assignments map[string]string) (*OperationSummary, error) {
+	ids := make([]string, 0, len(assignments))
+	for id := range assignments {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	found, missing, err := s.lookupTransactions(budgetID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		updates []payloadTransactionAssignment
+		skipped []string
+	)
+	for _, t := range found {
+		if t.IsSplit() {
+			skipped = append(skipped, t.ID)
+			continue
+		}
+		categoryID := assignments[t.ID]
+		if t.Approved && t.CategoryID != nil && *t.CategoryID == categoryID {
+			continue
+		}
+		updates = append(updates, payloadTransactionAssignment{
+			ID:         t.ID,
+			AccountID:  t.AccountID,
+			CategoryID: categoryID,
+			Approved:   true,
+		})
+	}
+
+	summary, err := patchTransactions(s, budgetID, updates, missing)
+	if err != nil {
+		return nil, err
+	}
+	summary.SkippedSplitIDs = skipped
+	return summary, nil
+}
+
+// lookupTransactions returns the transactions with the given IDs, in order
+// and without duplicates, from the unapproved transactions of the budget or
+// fetched one by one otherwise. IDs of transactions that do not exist or
+// are deleted are returned as missing.
+func (s *Service) lookupTransactions(budgetID string, ids []string) ([]*Transaction, []string, error) {
+	unapproved, err := s.fullReads().GetTransactions(budgetID, &Filter{Type: StatusUnapproved.Pointer()})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byID := make(map[string]*Transaction, len(unapproved.Transactions))
+	for _, t := range unapproved.Transactions {
+		byID[t.ID] = t
+	}
+
+	var (
+		found   []*Transaction
+		missing []string
+	)
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		t, ok := byID[id]
+		if !ok {
+			t, err = s.GetTransaction(budgetID, id)
+			var apiErr *api.Error
+			if errors.As(err, &apiErr) && apiErr.IsNotFound() {
+				missing = append(missing, id)
+				continue
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if t == nil || t.Deleted {
+			missing = append(missing, id)
+			continue
+		}
+		found = append(found, t)
+	}
+	return found, missing, nil
+}
+
+// patchTransactions sends updates in a single request, reporting missing
+// through the MissingIDs of the returned summary. No request is made when
+// there are no updates.
+func patchTransactions[T any](s *Service, budgetID string, updates []T, missing []string) (*OperationSummary, error) {
+	if len(updates) == 0 {
+		return &OperationSummary{MissingIDs: missing}, nil
+	}
+
+	payload := struct {
+		Transactions []T `json:"transactions"`
+	}{
+		updates,
+	}
+
+	buf, err := api.Marshal(s.c, &payload)
+	if err != nil {
+		return nil, err
+	}
+
+	resModel := struct {
+		Data *OperationSummary `json:"data"`
+	}{}
+
+	url := fmt.Sprintf("/budgets/%s/transactions", budgetID)
+	if err := s.c.PATCH(url, &resModel, buf); err != nil {
+		return nil, err
+	}
+	if resModel.Data == nil {
+		resModel.Data = &OperationSummary{}
+	}
+	resModel.Data.MissingIDs = missing
+	return resModel.Data, nil
 }
 
 // ScheduledSearchResultSnapshot represents the result of a scheduled transaction search with server knowledge
@@ -313,6 +971,11 @@ type ScheduledSearchResultSnapshot struct {
 	ServerKnowledge       uint64
 }
 
+// List returns the snapshot as a generic api.ListResult
+func (s *ScheduledSearchResultSnapshot) List() *api.ListResult[*Scheduled] {
+	return &api.ListResult[*Scheduled]{Items: s.ScheduledTransactions, ServerKnowledge: s.ServerKnowledge}
+}
+
 // GetScheduledTransactions fetches the list of scheduled transactions from
 // a budget with filtering capabilities
 // https://api.youneedabudget.com/v1#/Scheduled_Transactions/getScheduledTransactions
@@ -339,6 +1002,69 @@ func (s *Service) GetScheduledTransactions(budgetID string, f *api.Filter) (*Sch
 	}, nil
 }
 
+// GetScheduledTransactionsFiltered fetches the list of scheduled transactions
+// from a budget, applying last_knowledge_of_server on the API side and the
+// due date and frequency filters client-side. A nil filter performs a full
+// unfiltered fetch.
+// https://api.youneedabudget.com/v1#/Scheduled_Transactions/getScheduledTransactions
+func (s *Service) GetScheduledTransactionsFiltered(budgetID string, f *ScheduledFilter) (*ScheduledSearchResultSnapshot, error) {
+	resModel := struct {
+		Data struct {
+			ScheduledTransactions []*Scheduled `json:"scheduled_transactions"`
+			ServerKnowledge       uint64       `json:"server_knowledge"`
+		} `json:"data"`
+	}{}
+
+	url := fmt.Sprintf("/budgets/%s/scheduled_transactions", budgetID)
+	if f != nil {
+		if query := f.ToQuery(); query != "" {
+			url = fmt.Sprintf("%s?%s", url, query)
+		}
+	}
+
+	if err := s.c.GET(url, &resModel); err != nil {
+		return nil, err
+	}
+
+	scheduled := resModel.Data.ScheduledTransactions
+	if f != nil && (f.DueBefore != nil || f.Frequency != nil) {
+		scheduled = make([]*Scheduled, 0, len(resModel.Data.ScheduledTransactions))
+		for _, st := range resModel.Data.ScheduledTransactions {
+			if f.matches(st) {
+				scheduled = append(scheduled, st)
+			}
+		}
+	}
+
+	return &ScheduledSearchResultSnapshot{
+		ScheduledTransactions: scheduled,
+		ServerKnowledge:       resModel.Data.ServerKnowledge,
+	}, nil
+}
+
+// ForecastCashFlow projects the scheduled transactions of a budget over a
+// date range, both ends included, and returns the net amount in milliunits
+// landing on each day with at least one occurrence, keyed by "YYYY-MM-DD".
+// Deleted scheduled transactions are skipped.
+// https://api.youneedabudget.com/v1#/Scheduled_Transactions/getScheduledTransactions
+func (s *Service) ForecastCashFlow(budgetID string, from, to api.Date) (map[string]int64, error) {
+	snapshot, err := s.fullReads().GetScheduledTransactions(budgetID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	forecast := make(map[string]int64)
+	for _, st := range snapshot.ScheduledTransactions {
+		if st == nil || st.Deleted {
+			continue
+		}
+		for _, d := range st.OccurrencesBetween(from, to) {
+			forecast[api.DateFormat(d)] += int64(st.Amount)
+		}
+	}
+	return forecast, nil
+}
+
 // GetScheduledTransaction fetches a specific scheduled transaction from a budget
 // https://api.youneedabudget.com/v1#/Scheduled_Transactions/getScheduledTransactionById
 func (s *Service) GetScheduledTransaction(budgetID, scheduledTransactionID string) (*Scheduled, error) {
@@ -360,8 +1086,49 @@ type Filter struct {
 	Since                 *api.Date
 	Type                  *Status
 	LastKnowledgeOfServer *uint64
+
+	// MinAmount includes only transactions of at least this amount in
+	// milliunits. Applied client-side after the fetch, as the API has no
+	// amount filter, so it does not reduce the data transferred.
+	MinAmount *int64
+	// MaxAmount includes only transactions of at most this amount in
+	// milliunits. Applied client-side like MinAmount.
+	MaxAmount *int64
+}
+
+// hasAmountRange reports whether the filter bounds the amount
+func (f *Filter) hasAmountRange() bool {
+	return f != nil && (f.MinAmount != nil || f.MaxAmount != nil)
 }
 
+// matchesAmount reports whether amount falls within the inclusive bounds
+// of the filter, a nil bound being open-ended
+func (f *Filter) matchesAmount(amount int64) bool {
+	return (f.MinAmount == nil || amount >= *f.MinAmount) &&
+		(f.MaxAmount == nil || amount <= *f.MaxAmount)
+}
+
+// filterAmount returns the items whose amount matches the amount range of
+// the filter, or items as is when the filter has none
+func filterAmount[T any](f *Filter, items []T, amount func(T) int64) []T {
+	if !f.hasAmountRange() {
+		return items
+	}
+
+	kept := make([]T, 0, len(items))
+	for _, item := range items {
+		if f.matchesAmount(amount(item)) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// transactionAmount and hybridAmount return the amount filterAmount
+// matches against
+func transactionAmount(t *Transaction) int64 { return int64(t.Amount) }
+func hybridAmount(h *Hybrid) int64           { return int64(h.Amount) }
+
 // ToQuery returns the filters as a HTTP query string
 func (f *Filter) ToQuery() string {
 	pairs := make([]string, 0, 3)
@@ -378,16 +1145,53 @@ func (f *Filter) ToQuery() string {
 	return strings.Join(pairs, "&")
 }
 
-// CreateScheduledTransaction creates a new scheduled transaction for a budget
+// ScheduledFilter represents the optional filter while fetching
+// scheduled transactions
+type ScheduledFilter struct {
+	// DueBefore includes only scheduled transactions whose next date is
+	// before this date. Applied client-side.
+	DueBefore *api.Date
+	// Frequency includes only scheduled transactions repeating with this
+	// frequency. Applied client-side.
+	Frequency *ScheduledFrequency
+	// LastKnowledgeOfServer The starting server knowledge. If provided,
+	// only scheduled transactions that have changed since
+	// last_knowledge_of_server will be included
+	LastKnowledgeOfServer *uint64
+}
+
+// ToQuery returns the server-side filters as a HTTP query string
+func (f *ScheduledFilter) ToQuery() string {
+	pairs := make([]string, 0, 1)
+	if f.LastKnowledgeOfServer != nil {
+		pairs = append(pairs, fmt.Sprintf("last_knowledge_of_server=%d", *f.LastKnowledgeOfServer))
+	}
+	return strings.Join(pairs, "&")
+}
+
+// matches reports whether a scheduled transaction passes the client-side filters
+func (f *ScheduledFilter) matches(st *Scheduled) bool {
+	if f.DueBefore != nil && !st.DateNext.Before(f.DueBefore.Time) {
+		return false
+	}
+	if f.Frequency != nil && st.Frequency != *f.Frequency {
+		return false
+	}
+	return true
+}
+
+// CreateScheduledTransaction creates a new scheduled transaction for a
+// budget. An Idempotency-Key header can be set with api.WithIdempotencyKey.
 // https://api.youneedabudget.com/v1#/Scheduled_Transactions/createScheduledTransaction
-func (s *Service) CreateScheduledTransaction(budgetID string, p PayloadScheduledTransaction) (*Scheduled, error) {
+func (s *Service) CreateScheduledTransaction(budgetID string, p PayloadScheduledTransaction,
+	opts ...api.WriteOption) (*Scheduled, error) {
 	payload := struct {
 		ScheduledTransaction *PayloadScheduledTransaction `json:"scheduled_transaction"`
 	}{
 		&p,
 	}
 
-	buf, err := json.Marshal(&payload)
+	buf, err := api.Marshal(s.c, &payload)
 	if err != nil {
 		return nil, err
 	}
@@ -399,7 +1203,7 @@ func (s *Service) CreateScheduledTransaction(budgetID string, p PayloadScheduled
 	}{}
 
 	url := fmt.Sprintf("/budgets/%s/scheduled_transactions", budgetID)
-	if err := s.c.POST(url, &resModel, buf); err != nil {
+	if err := api.POSTWithHeader(s.c, url, &resModel, buf, api.NewWriteOptions(opts...).Header()); err != nil {
 		return nil, err
 	}
 	return resModel.Data.ScheduledTransaction, nil
@@ -414,7 +1218,7 @@ func (s *Service) UpdateScheduledTransaction(budgetID, scheduledTransactionID st
 		&p,
 	}
 
-	buf, err := json.Marshal(&payload)
+	buf, err := api.Marshal(s.c, &payload)
 	if err != nil {
 		return nil, err
 	}
@@ -458,6 +1262,10 @@ func (s *Service) ImportTransactions(budgetID string) (*ImportResult, error) {
 
 	url := fmt.Sprintf("/budgets/%s/transactions/import", budgetID)
 	if err := s.c.POST(url, &resModel, nil); err != nil {
+		var apiErr *api.Error
+		if errors.As(err, &apiErr) && apiErr.IsNoLinkedAccounts() {
+			return nil, fmt.Errorf("%w: %w", ErrNoLinkedAccounts, err)
+		}
 		return nil, err
 	}
 	return resModel.Data, nil
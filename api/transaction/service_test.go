@@ -2,10 +2,14 @@ package transaction_test
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/jarcoal/httpmock.v1"
 
 	"github.com/coltoneshaw/ynab.go"
@@ -84,7 +88,7 @@ func TestService_GetTransactions(t *testing.T) {
 		{
 			ID:           "e6ad88f5-6f16-4480-9515-5377012750dd",
 			Date:         expectedDate,
-			Amount:       int64(-43950),
+			Amount:       -43950,
 			Memo:         &expectedMemo,
 			Cleared:      transaction.ClearingStatusReconciled,
 			Approved:     true,
@@ -99,7 +103,7 @@ func TestService_GetTransactions(t *testing.T) {
 				{
 					ID:            "9453526b-2f58-4c02-9683-a30c2a1192d7",
 					TransactionID: "e6ad88f5-6f16-4480-9515-5377012750dd",
-					Amount:        int64(-33970),
+					Amount:        -33970,
 					Memo:          &expectedSubTransactionMemo,
 					PayeeID:       &expectedSubTransactionPayeeID,
 					CategoryID:    &expectedSubTransactionCategoryID,
@@ -180,7 +184,7 @@ func TestService_GetTransaction(t *testing.T) {
 	expected := &transaction.Transaction{
 		ID:           "e6ad88f5-6f16-4480-9515-5377012750dd",
 		Date:         expectedDate,
-		Amount:       int64(-43950),
+		Amount:       -43950,
 		Memo:         &expectedMemo,
 		Cleared:      transaction.ClearingStatusReconciled,
 		Approved:     true,
@@ -195,7 +199,7 @@ func TestService_GetTransaction(t *testing.T) {
 			{
 				ID:            "9453526b-2f58-4c02-9683-a30c2a1192d7",
 				TransactionID: "e6ad88f5-6f16-4480-9515-5377012750dd",
-				Amount:        int64(-33970),
+				Amount:        -33970,
 				Memo:          &expectedSubTransactionMemo,
 				PayeeID:       &expectedSubTransactionPayeeID,
 				CategoryID:    &expectedSubTransactionCategoryID,
@@ -281,7 +285,7 @@ func TestService_GetTransactionsByAccount(t *testing.T) {
 		{
 			ID:           "e6ad88f5-6f16-4480-9515-5377012750dd",
 			Date:         expectedDate,
-			Amount:       int64(-43950),
+			Amount:       -43950,
 			Memo:         &expectedMemo,
 			Cleared:      transaction.ClearingStatusReconciled,
 			Approved:     true,
@@ -296,7 +300,7 @@ func TestService_GetTransactionsByAccount(t *testing.T) {
 				{
 					ID:            "9453526b-2f58-4c02-9683-a30c2a1192d7",
 					TransactionID: "e6ad88f5-6f16-4480-9515-5377012750dd",
-					Amount:        int64(-33970),
+					Amount:        -33970,
 					Memo:          &expectedSubTransactionMemo,
 					PayeeID:       &expectedSubTransactionPayeeID,
 					CategoryID:    &expectedSubTransactionCategoryID,
@@ -368,7 +372,7 @@ func TestService_GetTransactionsByCategory(t *testing.T) {
 			Type:         transaction.TypeTransaction,
 			ID:           "c132c55c-1200-4606-a321-99f4ec24b4df",
 			Date:         expectedDate,
-			Amount:       int64(-42000),
+			Amount:       -42000,
 			Memo:         &expectedMemo,
 			Cleared:      transaction.ClearingStatusReconciled,
 			Approved:     true,
@@ -444,7 +448,7 @@ func TestService_GetTransactionsByPayee(t *testing.T) {
 			Type:         transaction.TypeTransaction,
 			ID:           "c132c55c-1200-4606-a321-99f4ec24b4df",
 			Date:         expectedDate,
-			Amount:       int64(-42000),
+			Amount:       -42000,
 			Memo:         &expectedMemo,
 			Cleared:      transaction.ClearingStatusReconciled,
 			Approved:     true,
@@ -519,7 +523,7 @@ func TestService_GetScheduledTransactions(t *testing.T) {
 			DateFirst:       expectedFirstAndLastDate,
 			DateNext:        expectedFirstAndLastDate,
 			Frequency:       transaction.FrequencyNever,
-			Amount:          int64(-9000),
+			Amount:          -9000,
 			Memo:            &expectedMemo,
 			FlagColor:       &expectedFlagColor,
 			AccountID:       "09eaca5e-312a-4bcd-89c4-828fb90638f2",
@@ -591,7 +595,7 @@ func TestService_GetScheduledTransaction(t *testing.T) {
 		DateFirst:       expectedFirstAndLastDate,
 		DateNext:        expectedFirstAndLastDate,
 		Frequency:       transaction.FrequencyNever,
-		Amount:          int64(-9000),
+		Amount:          -9000,
 		Memo:            &expectedMemo,
 		FlagColor:       &expectedFlagColor,
 		AccountID:       "09eaca5e-312a-4bcd-89c4-828fb90638f2",
@@ -684,7 +688,7 @@ func TestService_CreateTransaction(t *testing.T) {
 		Transaction: &transaction.Transaction{
 			ID:              "0f5b3f73-ded2-4dd7-8b01-c23022622cd6",
 			Date:            payload.Date,
-			Amount:          payload.Amount,
+			Amount:          api.Milliunits(payload.Amount),
 			Memo:            payload.Memo,
 			Cleared:         payload.Cleared,
 			Approved:        payload.Approved,
@@ -703,6 +707,128 @@ func TestService_CreateTransaction(t *testing.T) {
 	assert.Equal(t, expectedTransactions, tx)
 }
 
+func TestService_WithAutoApprove(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var approved []bool
+	record := func(req *http.Request) (*http.Response, error) {
+		body := struct {
+			Transactions []struct {
+				Approved bool `json:"approved"`
+			} `json:"transactions"`
+		}{}
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		approved = approved[:0]
+		for _, tx := range body.Transactions {
+			approved = append(approved, tx.Approved)
+		}
+		return httpmock.NewStringResponse(http.StatusCreated, `{"data":{"transaction_ids":["tx-1","tx-2"],"bulk":{"transaction_ids":["tx-1","tx-2"]}}}`), nil
+	}
+	httpmock.RegisterResponder(http.MethodPost, "https://api.youneedabudget.com/v1/budgets/aa248caa/transactions", record)
+	httpmock.RegisterResponder(http.MethodPost, "https://api.youneedabudget.com/v1/budgets/aa248caa/transactions/bulk", record)
+
+	date, err := api.DateFromString("2024-01-15")
+	require.NoError(t, err)
+	payee := "Supermarket"
+	payloads := []transaction.PayloadTransaction{
+		{AccountID: "acc-checking", Date: date, Amount: -1000, Cleared: transaction.ClearingStatusCleared, PayeeName: &payee},
+		{AccountID: "acc-checking", Date: date, Amount: -2000, Cleared: transaction.ClearingStatusCleared, PayeeName: &payee, Approved: true},
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		client := ynab.NewClient("")
+		_, err := client.Transaction().CreateTransactions("aa248caa", payloads)
+		require.NoError(t, err)
+		assert.Equal(t, []bool{false, true}, approved)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		service := ynab.NewClient("").Transaction().WithAutoApprove()
+
+		_, err := service.CreateTransactions("aa248caa", payloads)
+		require.NoError(t, err)
+		assert.Equal(t, []bool{true, true}, approved)
+
+		_, err = service.CreateTransaction("aa248caa", payloads[0])
+		require.NoError(t, err)
+		assert.Equal(t, []bool{true}, approved)
+
+		_, err = service.BulkCreateTransactions("aa248caa", payloads)
+		require.NoError(t, err)
+		assert.Equal(t, []bool{true, true}, approved)
+
+		// The caller's payloads are not modified
+		assert.False(t, payloads[0].Approved)
+	})
+}
+
+func TestService_MemoLength(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var memos []string
+	httpmock.RegisterResponder(http.MethodPost, "https://api.youneedabudget.com/v1/budgets/aa248caa/transactions",
+		func(req *http.Request) (*http.Response, error) {
+			body := struct {
+				Transactions []struct {
+					Memo string `json:"memo"`
+				} `json:"transactions"`
+			}{}
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+			memos = memos[:0]
+			for _, tx := range body.Transactions {
+				memos = append(memos, tx.Memo)
+			}
+			return httpmock.NewStringResponse(http.StatusCreated, `{"data":{"transaction_ids":["tx-1","tx-2"]}}`), nil
+		})
+
+	date, err := api.DateFromString("2024-01-15")
+	require.NoError(t, err)
+	payee := "Supermarket"
+	withMemo := func(memo string) transaction.PayloadTransaction {
+		return transaction.PayloadTransaction{
+			AccountID: "acc-checking", Date: date, Amount: -1000,
+			Cleared: transaction.ClearingStatusCleared, PayeeName: &payee, Memo: &memo,
+		}
+	}
+
+	atLimit := strings.Repeat("a", transaction.MaxMemoLength)
+	overLimit := strings.Repeat("a", transaction.MaxMemoLength-2) + "€€€"
+
+	t.Run("at the limit", func(t *testing.T) {
+		client := ynab.NewClient("")
+		_, err := client.Transaction().CreateTransactions("aa248caa", []transaction.PayloadTransaction{withMemo(atLimit)})
+		require.NoError(t, err)
+		assert.Equal(t, []string{atLimit}, memos)
+	})
+
+	t.Run("over the limit", func(t *testing.T) {
+		client := ynab.NewClient("")
+		_, err := client.Transaction().CreateTransactions("aa248caa",
+			[]transaction.PayloadTransaction{withMemo("short"), withMemo(overLimit)})
+		assert.ErrorIs(t, err, transaction.ErrMemoTooLong)
+		assert.ErrorContains(t, err, "transaction 1:")
+		assert.ErrorContains(t, err, "501 characters")
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		payloads := []transaction.PayloadTransaction{withMemo("short"), withMemo(overLimit)}
+		client := ynab.NewClient("")
+		_, err := client.Transaction().WithMemoTruncation().CreateTransactions("aa248caa", payloads)
+		require.NoError(t, err)
+
+		require.Len(t, memos, 2)
+		assert.Equal(t, "short", memos[0])
+		assert.Equal(t, strings.Repeat("a", transaction.MaxMemoLength-2)+"€€", memos[1])
+		assert.True(t, utf8.ValidString(memos[1]))
+		assert.Equal(t, transaction.MaxMemoLength, utf8.RuneCountInString(memos[1]))
+
+		// The caller's payloads are not modified
+		assert.Equal(t, overLimit, *payloads[1].Memo)
+	})
+}
+
 func TestService_CreateTransactions(t *testing.T) {
 	httpmock.Activate()
 	defer httpmock.DeactivateAndReset()
@@ -818,7 +944,7 @@ func TestService_CreateTransactions(t *testing.T) {
 			{
 				ID:              "0f5b3f73-ded2-4dd7-8b01-c23022622cd6",
 				Date:            payload[0].Date,
-				Amount:          payload[0].Amount,
+				Amount:          api.Milliunits(payload[0].Amount),
 				Memo:            payload[0].Memo,
 				Cleared:         payload[0].Cleared,
 				Approved:        payload[0].Approved,
@@ -835,7 +961,7 @@ func TestService_CreateTransactions(t *testing.T) {
 			{
 				ID:              "0f5b3f73-ded2-4dd7-8b01-c23022622cd7",
 				Date:            payload[1].Date,
-				Amount:          payload[1].Amount,
+				Amount:          api.Milliunits(payload[1].Amount),
 				Memo:            payload[1].Memo,
 				Cleared:         payload[1].Cleared,
 				Approved:        payload[1].Approved,
@@ -972,7 +1098,7 @@ func TestService_UpdateTransactions(t *testing.T) {
 			{
 				ID:              "0f5b3f73-ded2-4dd7-8b01-c23022622cd6",
 				Date:            payload[0].Date,
-				Amount:          payload[0].Amount,
+				Amount:          api.Milliunits(payload[0].Amount),
 				Memo:            payload[0].Memo,
 				Cleared:         payload[0].Cleared,
 				Approved:        payload[0].Approved,
@@ -989,7 +1115,7 @@ func TestService_UpdateTransactions(t *testing.T) {
 			{
 				ID:              "0f5b3f73-ded2-4dd7-8b01-c23022622cd7",
 				Date:            payload[1].Date,
-				Amount:          payload[1].Amount,
+				Amount:          api.Milliunits(payload[1].Amount),
 				Memo:            payload[1].Memo,
 				Cleared:         payload[1].Cleared,
 				Approved:        payload[1].Approved,
@@ -1060,7 +1186,11 @@ func TestService_BulkCreateTransactions(t *testing.T) {
 			}{}
 			err := json.NewDecoder(req.Body).Decode(&resModel)
 			assert.NoError(t, err)
-			assert.Equal(t, payload, resModel.Transactions)
+			// The payee name is not sent alongside the payee ID
+			assert.Equal(t, []transaction.PayloadTransaction{
+				payload[0].WithExistingPayee(payloadPayeeID),
+				payload[1].WithExistingPayee(payloadPayeeID),
+			}, resModel.Transactions)
 
 			res := httpmock.NewStringResponse(200, `{
   "data": {
@@ -1122,7 +1252,9 @@ func TestService_UpdateTransaction(t *testing.T) {
 			}{}
 			err := json.NewDecoder(req.Body).Decode(&resModel)
 			assert.NoError(t, err)
-			assert.Equal(t, &payload, resModel.Transaction)
+			// The payee name is not sent alongside the payee ID
+			sent := payload.WithExistingPayee(payloadPayeeID)
+			assert.Equal(t, &sent, resModel.Transaction)
 
 			res := httpmock.NewStringResponse(200, `{
   "data": {
@@ -1164,7 +1296,7 @@ func TestService_UpdateTransaction(t *testing.T) {
 	expectedTransaction := &transaction.Transaction{
 		ID:              "0f5b3f73-ded2-4dd7-8b01-c23022622cd6",
 		Date:            payload.Date,
-		Amount:          payload.Amount,
+		Amount:          api.Milliunits(payload.Amount),
 		Cleared:         payload.Cleared,
 		Approved:        payload.Approved,
 		AccountID:       payload.AccountID,
@@ -1180,6 +1312,143 @@ func TestService_UpdateTransaction(t *testing.T) {
 	assert.Equal(t, expectedTransaction, tx)
 }
 
+func TestService_UpdateTransaction_Split(t *testing.T) {
+	date, err := api.DateFromString("2024-01-15")
+	require.NoError(t, err)
+	url := "https://api.youneedabudget.com/v1/budgets/aa248caa/transactions/tx-1"
+
+	register := func(body *string) {
+		httpmock.RegisterResponder(http.MethodPut, url,
+			func(req *http.Request) (*http.Response, error) {
+				b, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+				*body = string(b)
+				return httpmock.NewStringResponse(http.StatusOK,
+					`{"data":{"transaction":{"id":"tx-1","amount":-10000}}}`), nil
+			},
+		)
+	}
+	sent := func(t *testing.T, body string) map[string]any {
+		var payload struct {
+			Transaction map[string]any `json:"transaction"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(body), &payload))
+		return payload.Transaction
+	}
+
+	base := transaction.PayloadTransaction{
+		AccountID: "account-id",
+		Date:      date,
+		Amount:    -10000,
+		Cleared:   transaction.ClearingStatusCleared,
+		PayeeID:   strPtr("payee-id"),
+	}
+
+	t.Run("split a simple transaction", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		var body string
+		register(&body)
+
+		p := base
+		p.SubTransactions = []*transaction.PayloadSubTransaction{
+			{Amount: -6000, CategoryID: strPtr("cat-groceries")},
+			{Amount: -4000, CategoryID: strPtr("cat-household"), Memo: strPtr("Soap")},
+		}
+		_, err := ynab.NewClient("").Transaction().UpdateTransaction("aa248caa", "tx-1", p)
+		require.NoError(t, err)
+
+		assert.Equal(t, []any{
+			map[string]any{"amount": float64(-6000), "payee_id": nil, "payee_name": nil,
+				"category_id": "cat-groceries", "memo": nil},
+			map[string]any{"amount": float64(-4000), "payee_id": nil, "payee_name": nil,
+				"category_id": "cat-household", "memo": "Soap"},
+		}, sent(t, body)["subtransactions"])
+	})
+
+	t.Run("update existing subtransactions", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var body string
+		httpmock.RegisterResponder(http.MethodPatch, "https://api.youneedabudget.com/v1/budgets/aa248caa/transactions",
+			func(req *http.Request) (*http.Response, error) {
+				b, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+				body = string(b)
+				return httpmock.NewStringResponse(http.StatusOK,
+					`{"data":{"transaction_ids":["tx-1"],"server_knowledge":2}}`), nil
+			},
+		)
+
+		split := &transaction.Transaction{
+			ID: "tx-1", AccountID: "account-id", Date: date, Amount: -10000,
+			Cleared: transaction.ClearingStatusCleared, PayeeID: strPtr("payee-id"),
+			SubTransactions: []*transaction.SubTransaction{
+				{ID: "sub-1", TransactionID: "tx-1", Amount: -6000, CategoryID: strPtr("cat-groceries")},
+				{ID: "sub-2", TransactionID: "tx-1", Amount: -4000, CategoryID: strPtr("cat-household")},
+			},
+		}
+		p := split.ToPayload()
+		p.SubTransactions[0].Amount = -7000
+		p.SubTransactions[1].Amount = -3000
+		_, err := ynab.NewClient("").Transaction().UpdateTransactions("aa248caa",
+			[]transaction.PayloadTransaction{p})
+		require.NoError(t, err)
+
+		var payload struct {
+			Transactions []struct {
+				SubTransactions []*transaction.PayloadSubTransaction `json:"subtransactions"`
+			} `json:"transactions"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(body), &payload))
+		require.Len(t, payload.Transactions, 1)
+		assert.Equal(t, []*transaction.PayloadSubTransaction{
+			{ID: "sub-1", Amount: -7000, CategoryID: strPtr("cat-groceries")},
+			{ID: "sub-2", Amount: -3000, CategoryID: strPtr("cat-household")},
+		}, payload.Transactions[0].SubTransactions)
+	})
+
+	t.Run("unsplit back to a single category", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		var body string
+		register(&body)
+
+		p := base
+		p.CategoryID = strPtr("cat-groceries")
+		p.ClearSubTransactions = true
+		_, err := ynab.NewClient("").Transaction().UpdateTransaction("aa248caa", "tx-1", p)
+		require.NoError(t, err)
+
+		tx := sent(t, body)
+		assert.Equal(t, []any{}, tx["subtransactions"])
+		assert.Equal(t, "cat-groceries", tx["category_id"])
+	})
+
+	t.Run("mismatched amounts", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		p := base
+		p.SubTransactions = []*transaction.PayloadSubTransaction{{Amount: -6000}, {Amount: -3000}}
+		_, err := ynab.NewClient("").Transaction().UpdateTransaction("aa248caa", "tx-1", p)
+		assert.ErrorIs(t, err, transaction.ErrSplitAmount)
+		assert.Zero(t, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("plain update leaves subtransactions alone", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		var body string
+		register(&body)
+
+		_, err := ynab.NewClient("").Transaction().UpdateTransaction("aa248caa", "tx-1", base)
+		require.NoError(t, err)
+		assert.NotContains(t, sent(t, body), "subtransactions")
+	})
+}
+
 func TestService_DeleteTransaction(t *testing.T) {
 	httpmock.Activate()
 	defer httpmock.DeactivateAndReset()
@@ -1426,7 +1695,7 @@ func TestService_CreateScheduledTransaction(t *testing.T) {
 		DateFirst:       expectedDateFirst,
 		DateNext:        expectedDateNext,
 		Frequency:       transaction.FrequencyMonthly,
-		Amount:          int64(-15000),
+		Amount:          -15000,
 		Memo:            &expectedMemo,
 		FlagColor:       &expectedFlagColor,
 		AccountID:       "09eaca5e-312a-4bcd-89c4-828fb90638f2",
@@ -1519,7 +1788,7 @@ func TestService_UpdateScheduledTransaction(t *testing.T) {
 		DateFirst:       expectedDateFirst,
 		DateNext:        expectedDateNext,
 		Frequency:       transaction.FrequencyMonthly,
-		Amount:          int64(-17500),
+		Amount:          -17500,
 		Memo:            &expectedMemo,
 		FlagColor:       &expectedFlagColor,
 		AccountID:       "09eaca5e-312a-4bcd-89c4-828fb90638f2",
@@ -1590,7 +1859,7 @@ func TestService_DeleteScheduledTransaction(t *testing.T) {
 		DateFirst:       expectedDateFirst,
 		DateNext:        expectedDateNext,
 		Frequency:       transaction.FrequencyMonthly,
-		Amount:          int64(-15000),
+		Amount:          -15000,
 		Memo:            &expectedMemo,
 		FlagColor:       &expectedFlagColor,
 		AccountID:       "09eaca5e-312a-4bcd-89c4-828fb90638f2",
@@ -1640,13 +1909,36 @@ func TestService_ImportTransactions(t *testing.T) {
 		},
 	}
 	assert.Equal(t, expected, result)
+	assert.False(t, result.IsEmpty())
+
+	t.Run("nothing to import", func(t *testing.T) {
+		httpmock.RegisterResponder(http.MethodPost, url,
+			httpmock.NewStringResponder(200, `{"data":{"transaction_ids":[]}}`))
+
+		result, err := client.Transaction().ImportTransactions("aa248caa-eed7-4575-a990-717386438d2c")
+		require.NoError(t, err)
+		assert.True(t, result.IsEmpty())
+	})
+
+	t.Run("no linked accounts", func(t *testing.T) {
+		httpmock.RegisterResponder(http.MethodPost, url,
+			httpmock.NewStringResponder(400, `{"error":{"id":"400","name":"bad_request","detail":"This budget does not have any linked accounts"}}`))
+
+		result, err := client.Transaction().ImportTransactions("aa248caa-eed7-4575-a990-717386438d2c")
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, transaction.ErrNoLinkedAccounts)
+
+		var apiErr *api.Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.True(t, apiErr.IsValidationError())
+	})
 }
 
 func TestService_GetTransactionsByMonth(t *testing.T) {
 	httpmock.Activate()
 	defer httpmock.DeactivateAndReset()
 
-	url := "https://api.youneedabudget.com/v1/budgets/aa248caa-eed7-4575-a990-717386438d2c/months/2018-11/transactions"
+	url := "https://api.youneedabudget.com/v1/budgets/aa248caa-eed7-4575-a990-717386438d2c/months/2018-11-01/transactions"
 	httpmock.RegisterResponder(http.MethodGet, url,
 		func(req *http.Request) (*http.Response, error) {
 			res := httpmock.NewStringResponse(200, `{
@@ -1703,7 +1995,7 @@ func TestService_GetTransactionsByMonth(t *testing.T) {
 		{
 			ID:              "0f5b3f73-ded2-4dd7-8b01-c23022622cd6",
 			Date:            payloadDate,
-			Amount:          int64(-9000),
+			Amount:          -9000,
 			Memo:            &memo,
 			Cleared:         transaction.ClearingStatusCleared,
 			Approved:        true,
@@ -1721,3 +2013,1027 @@ func TestService_GetTransactionsByMonth(t *testing.T) {
 
 	assert.Equal(t, expected, transactions)
 }
+
+func TestService_GetDeletedSince(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	url := "https://api.youneedabudget.com/v1/budgets/aa248caa-eed7-4575-a990-717386438d2c/transactions"
+	httpmock.RegisterResponder(http.MethodGet, url,
+		func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "42", req.URL.Query().Get("last_knowledge_of_server"))
+
+			res := httpmock.NewStringResponse(200, `{
+  "data": {
+    "transactions": [
+      {
+        "id": "e6ad88f5-6f16-4480-9515-5377012750dd",
+        "date": "2018-03-10",
+        "amount": -43950,
+        "cleared": "cleared",
+        "approved": true,
+        "account_id": "09eaca5e-6f16-4480-9515-828fb90638f2",
+        "deleted": false
+      },
+      {
+        "id": "9b1ad8ea-b3f1-4f4c-8a4c-5b3a5fd0c75e",
+        "date": "2018-03-11",
+        "amount": -1000,
+        "cleared": "uncleared",
+        "approved": false,
+        "account_id": "09eaca5e-6f16-4480-9515-828fb90638f2",
+        "deleted": true
+      }
+    ],
+    "server_knowledge": 51
+  }
+}
+		`)
+			return res, nil
+		},
+	)
+
+	client := ynab.NewClient("")
+	deleted, serverKnowledge, err := client.Transaction().GetDeletedSince("aa248caa-eed7-4575-a990-717386438d2c", 42)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(51), serverKnowledge)
+	assert.Len(t, deleted, 1)
+	assert.Equal(t, "9b1ad8ea-b3f1-4f4c-8a4c-5b3a5fd0c75e", deleted[0].ID)
+	assert.True(t, deleted[0].Deleted)
+}
+
+func TestService_HasChangesSince(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	url := "https://api.youneedabudget.com/v1/budgets/aa248caa-eed7-4575-a990-717386438d2c/transactions"
+	client := ynab.NewClient("")
+
+	t.Run("unchanged", func(t *testing.T) {
+		httpmock.RegisterResponder(http.MethodGet, url,
+			func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "42", req.URL.Query().Get("last_knowledge_of_server"))
+				return httpmock.NewStringResponse(200, `{"data":{"transactions":[],"server_knowledge":42}}`), nil
+			},
+		)
+
+		changed, serverKnowledge, err := client.Transaction().HasChangesSince("aa248caa-eed7-4575-a990-717386438d2c", 42)
+		require.NoError(t, err)
+		assert.False(t, changed)
+		assert.Equal(t, uint64(42), serverKnowledge)
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		httpmock.RegisterResponder(http.MethodGet, url,
+			httpmock.NewStringResponder(200, `{"data":{"transactions":[{"id":"e6ad88f5-6f16-4480-9515-5377012750dd","date":"2018-03-10","amount":-43950}],"server_knowledge":51}}`))
+
+		changed, serverKnowledge, err := client.Transaction().HasChangesSince("aa248caa-eed7-4575-a990-717386438d2c", 42)
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, uint64(51), serverKnowledge)
+	})
+}
+
+func TestService_GetTransactionsByMonth_InvalidMonth(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	client := ynab.NewClient("")
+	result, err := client.Transaction().GetTransactionsByMonth(
+		"aa248caa-eed7-4575-a990-717386438d2c",
+		"November",
+		nil,
+	)
+	assert.Nil(t, result)
+	assert.EqualError(t, err, `api: invalid month "November": expected YYYY-MM-DD, YYYY-MM or "current"`)
+	assert.Equal(t, 0, httpmock.GetTotalCallCount())
+}
+
+func TestService_GetTransactions_StrictEnvelope(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	url := "https://api.youneedabudget.com/v1/budgets/aa248caa-eed7-4575-a990-717386438d2c/transactions"
+	httpmock.RegisterResponder(http.MethodGet, url,
+		httpmock.NewStringResponder(http.StatusOK, `{"data":{"transactions":[],"server_knowledge":10}}`))
+
+	client := ynab.NewClient("").WithStrictEnvelope()
+	snapshot, err := client.Transaction().GetTransactions("aa248caa-eed7-4575-a990-717386438d2c", nil)
+	assert.NoError(t, err)
+	assert.Empty(t, snapshot.Transactions)
+
+	httpmock.RegisterResponder(http.MethodGet, url,
+		httpmock.NewStringResponder(http.StatusOK, `{}`))
+
+	snapshot, err = client.Transaction().GetTransactions("aa248caa-eed7-4575-a990-717386438d2c", nil)
+	assert.ErrorIs(t, err, api.ErrMissingEnvelope)
+	assert.Nil(t, snapshot)
+}
+
+func TestService_RecategorizeByPayee(t *testing.T) {
+	t.Run("mixed transactions", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodGet,
+			"https://api.youneedabudget.com/v1/budgets/aa248caa/payees/payee-1/transactions",
+			func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "since_date=2024-01-01", req.URL.RawQuery)
+				return httpmock.NewStringResponse(http.StatusOK, `{
+  "data": {
+    "transactions": [
+      {"id": "tx-uncategorized", "type": "transaction", "amount": -1000, "category_id": null},
+      {"id": "tx-other", "type": "transaction", "amount": -2000, "category_id": "cat-other", "category_name": "Dining"},
+      {"id": "tx-target", "type": "transaction", "amount": -3000, "category_id": "cat-groceries", "category_name": "Groceries"},
+      {"id": "tx-deleted", "type": "transaction", "amount": -4000, "category_id": null, "deleted": true},
+      {"id": "tx-split", "type": "transaction", "amount": -5000, "category_id": null, "category_name": "Split (Multiple Categories)..."},
+      {"id": "sub-1", "type": "subtransaction", "amount": -2500, "parent_transaction_id": "tx-split", "category_id": "cat-other"},
+      {"id": "sub-2", "type": "subtransaction", "amount": -1500, "parent_transaction_id": "tx-outside", "category_id": "cat-other"},
+      {"id": "sub-3", "type": "subtransaction", "amount": -2500, "parent_transaction_id": "tx-split", "category_id": "cat-groceries"}
+    ]
+  }
+}`), nil
+			},
+		)
+
+		httpmock.RegisterResponder(http.MethodPatch,
+			"https://api.youneedabudget.com/v1/budgets/aa248caa/transactions",
+			func(req *http.Request) (*http.Response, error) {
+				body, err := io.ReadAll(req.Body)
+				assert.NoError(t, err)
+				assert.JSONEq(t, `{"transactions":[
+					{"id":"tx-uncategorized","category_id":"cat-groceries"},
+					{"id":"tx-other","category_id":"cat-groceries"}
+				]}`, string(body))
+
+				return httpmock.NewStringResponse(http.StatusOK, `{
+  "data": {
+    "transaction_ids": ["tx-uncategorized", "tx-other"],
+    "transactions": [
+      {"id": "tx-uncategorized", "category_id": "cat-groceries"},
+      {"id": "tx-other", "category_id": "cat-groceries"}
+    ]
+  }
+}`), nil
+			},
+		)
+
+		since, err := api.DateFromString("2024-01-01")
+		assert.NoError(t, err)
+
+		client := ynab.NewClient("")
+		summary, err := client.Transaction().RecategorizeByPayee("aa248caa", "payee-1", "cat-groceries",
+			&transaction.Filter{Since: &since})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"tx-uncategorized", "tx-other"}, summary.TransactionIDs)
+		assert.Len(t, summary.Transactions, 2)
+		assert.Equal(t, []string{"tx-outside", "tx-split"}, summary.SkippedSplitIDs)
+	})
+
+	t.Run("nothing to update", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodGet,
+			"https://api.youneedabudget.com/v1/budgets/aa248caa/payees/payee-1/transactions",
+			httpmock.NewStringResponder(http.StatusOK, `{
+  "data": {
+    "transactions": [
+      {"id": "tx-target", "type": "transaction", "category_id": "cat-groceries"},
+      {"id": "sub-1", "type": "subtransaction", "parent_transaction_id": "tx-split"}
+    ]
+  }
+}`))
+
+		client := ynab.NewClient("")
+		summary, err := client.Transaction().RecategorizeByPayee("aa248caa", "payee-1", "cat-groceries", nil)
+		assert.NoError(t, err)
+		assert.Empty(t, summary.TransactionIDs)
+		assert.Equal(t, []string{"tx-split"}, summary.SkippedSplitIDs)
+		assert.Equal(t, 1, httpmock.GetTotalCallCount())
+	})
+}
+
+func TestScheduledFilter_ToQuery(t *testing.T) {
+	var knowledge uint64 = 42
+	frequency := transaction.FrequencyMonthly
+	dueBefore := api.Date{}
+
+	assert.Equal(t, "", (&transaction.ScheduledFilter{}).ToQuery())
+	assert.Equal(t, "", (&transaction.ScheduledFilter{Frequency: &frequency, DueBefore: &dueBefore}).ToQuery())
+	assert.Equal(t, "last_knowledge_of_server=42", (&transaction.ScheduledFilter{LastKnowledgeOfServer: &knowledge}).ToQuery())
+}
+
+func TestService_GetScheduledTransactionsFiltered(t *testing.T) {
+	const body = `{
+  "data": {
+    "scheduled_transactions": [
+      {"id": "st-rent", "date_first": "2024-01-01", "date_next": "2024-02-01", "frequency": "monthly", "amount": -1500000},
+      {"id": "st-gym", "date_first": "2024-01-05", "date_next": "2024-01-12", "frequency": "weekly", "amount": -10000},
+      {"id": "st-tax", "date_first": "2024-04-15", "date_next": "2024-04-15", "frequency": "yearly", "amount": -500000}
+    ],
+    "server_knowledge": 12
+  }
+}`
+
+	ids := func(snapshot *transaction.ScheduledSearchResultSnapshot) []string {
+		result := make([]string, 0, len(snapshot.ScheduledTransactions))
+		for _, st := range snapshot.ScheduledTransactions {
+			result = append(result, st.ID)
+		}
+		return result
+	}
+
+	url := "https://api.youneedabudget.com/v1/budgets/aa248caa/scheduled_transactions"
+	dueBefore, err := api.DateFromString("2024-03-01")
+	assert.NoError(t, err)
+	monthly := transaction.FrequencyMonthly
+	var knowledge uint64 = 10
+
+	tests := []struct {
+		name     string
+		filter   *transaction.ScheduledFilter
+		query    string
+		expected []string
+	}{
+		{name: "nil filter", expected: []string{"st-rent", "st-gym", "st-tax"}},
+		{
+			name:     "due before",
+			filter:   &transaction.ScheduledFilter{DueBefore: &dueBefore},
+			expected: []string{"st-rent", "st-gym"},
+		},
+		{
+			name:     "frequency",
+			filter:   &transaction.ScheduledFilter{Frequency: &monthly},
+			expected: []string{"st-rent"},
+		},
+		{
+			name:     "last knowledge of server",
+			filter:   &transaction.ScheduledFilter{LastKnowledgeOfServer: &knowledge},
+			query:    "last_knowledge_of_server=10",
+			expected: []string{"st-rent", "st-gym", "st-tax"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			httpmock.RegisterResponder(http.MethodGet, url,
+				func(req *http.Request) (*http.Response, error) {
+					assert.Equal(t, test.query, req.URL.RawQuery)
+					return httpmock.NewStringResponse(http.StatusOK, body), nil
+				},
+			)
+
+			client := ynab.NewClient("")
+			snapshot, err := client.Transaction().GetScheduledTransactionsFiltered("aa248caa", test.filter)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, ids(snapshot))
+			assert.Equal(t, uint64(12), snapshot.ServerKnowledge)
+		})
+	}
+}
+
+func TestService_GetTransactionsByFlag(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var query string
+	httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets/aa248caa/transactions",
+		func(req *http.Request) (*http.Response, error) {
+			query = req.URL.RawQuery
+			return httpmock.NewStringResponse(http.StatusOK, `{"data":{"transactions":[
+				{"id":"tx-red-1","flag_color":"red"},
+				{"id":"tx-blue","flag_color":"blue"},
+				{"id":"tx-unflagged-null","flag_color":null},
+				{"id":"tx-red-2","flag_color":"red"},
+				{"id":"tx-unflagged-missing"},
+				{"id":"tx-unflagged-empty","flag_color":""}
+			],"server_knowledge":5}}`), nil
+		},
+	)
+
+	ids := func(transactions []*transaction.Transaction) []string {
+		out := make([]string, 0, len(transactions))
+		for _, tx := range transactions {
+			out = append(out, tx.ID)
+		}
+		return out
+	}
+
+	since, err := api.DateFromString("2024-01-01")
+	require.NoError(t, err)
+	client := ynab.NewClient("")
+
+	red, err := client.Transaction().GetTransactionsByFlag("aa248caa", transaction.FlagColorRed,
+		&transaction.Filter{Since: &since, Type: transaction.StatusUnapproved.Pointer()})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tx-red-1", "tx-red-2"}, ids(red))
+	assert.Equal(t, "since_date=2024-01-01&type=unapproved", query)
+
+	unflagged, err := client.Transaction().GetTransactionsByFlag("aa248caa", transaction.FlagColorNone, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tx-unflagged-null", "tx-unflagged-missing", "tx-unflagged-empty"}, ids(unflagged))
+
+	purple, err := client.Transaction().GetTransactionsByFlag("aa248caa", transaction.FlagColorPurple, nil)
+	require.NoError(t, err)
+	assert.Empty(t, purple)
+	assert.Equal(t, 3, httpmock.GetTotalCallCount())
+
+	_, err = client.Transaction().GetTransactionsByFlag("aa248caa", transaction.FlagColor("teal"), nil)
+	assert.ErrorIs(t, err, transaction.ErrInvalidEnum)
+	assert.Equal(t, 3, httpmock.GetTotalCallCount())
+}
+
+func TestService_FindByImportIDs(t *testing.T) {
+	url := "https://api.youneedabudget.com/v1/budgets/aa248caa/transactions"
+	body := `{"data":{"transactions":[
+		{"id":"tx-1","import_id":"YNAB:-1000:2024-01-15:1"},
+		{"id":"tx-2","import_id":"YNAB:-2500:2024-01-20:1"},
+		{"id":"tx-3","import_id":null},
+		{"id":"tx-4","import_id":"YNAB:-9000:2024-01-21:1","deleted":true},
+		{"id":"tx-5","import_id":"bank-export-77"},
+		{"id":"tx-6","import_id":"YNAB:-1000:2024-01-15:1"}
+	],"server_knowledge":5}}`
+
+	t.Run("bounded by the oldest import date", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var query string
+		httpmock.RegisterResponder(http.MethodGet, url,
+			func(req *http.Request) (*http.Response, error) {
+				query = req.URL.RawQuery
+				return httpmock.NewStringResponse(http.StatusOK, body), nil
+			},
+		)
+
+		found, err := ynab.NewClient("").Transaction().FindByImportIDs("aa248caa", []string{
+			"YNAB:-2500:2024-01-20:1",
+			"YNAB:-1000:2024-01-15:1",
+			"YNAB:-9000:2024-01-21:1",
+			"YNAB:-4000:2024-01-18:2",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "since_date=2024-01-15", query)
+
+		require.Len(t, found, 2)
+		assert.Equal(t, "tx-1", found["YNAB:-1000:2024-01-15:1"].ID)
+		assert.Equal(t, "tx-2", found["YNAB:-2500:2024-01-20:1"].ID)
+		assert.NotContains(t, found, "YNAB:-9000:2024-01-21:1")
+		assert.NotContains(t, found, "YNAB:-4000:2024-01-18:2")
+	})
+
+	t.Run("custom import IDs fetch everything", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		query := "unset"
+		httpmock.RegisterResponder(http.MethodGet, url,
+			func(req *http.Request) (*http.Response, error) {
+				query = req.URL.RawQuery
+				return httpmock.NewStringResponse(http.StatusOK, body), nil
+			},
+		)
+
+		found, err := ynab.NewClient("").Transaction().FindByImportIDs("aa248caa",
+			[]string{"YNAB:-2500:2024-01-20:1", "bank-export-77", "bank-export-78"})
+		require.NoError(t, err)
+		assert.Empty(t, query)
+		assert.Len(t, found, 2)
+		assert.Equal(t, "tx-5", found["bank-export-77"].ID)
+	})
+
+	t.Run("no import IDs", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		found, err := ynab.NewClient("").Transaction().FindByImportIDs("aa248caa", nil)
+		require.NoError(t, err)
+		assert.Empty(t, found)
+		assert.Zero(t, httpmock.GetTotalCallCount())
+	})
+}
+
+func TestService_CountNeedingAttention(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets/aa248caa/transactions",
+			func(req *http.Request) (*http.Response, error) {
+				switch req.URL.Query().Get("type") {
+				case "uncategorized":
+					return httpmock.NewStringResponse(http.StatusOK,
+						`{"data":{"transactions":[{"id":"tx-1"},{"id":"tx-2"}],"server_knowledge":5}}`), nil
+				case "unapproved":
+					return httpmock.NewStringResponse(http.StatusOK,
+						`{"data":{"transactions":[{"id":"tx-2"},{"id":"tx-3"},{"id":"tx-4"}],"server_knowledge":5}}`), nil
+				}
+				t.Errorf("unexpected query %q", req.URL.RawQuery)
+				return httpmock.NewStringResponse(http.StatusBadRequest, ""), nil
+			},
+		)
+
+		client := ynab.NewClient("")
+		uncategorized, unapproved, err := client.Transaction().CountNeedingAttention("aa248caa")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, uncategorized)
+		assert.Equal(t, 3, unapproved)
+		assert.Equal(t, 2, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets/aa248caa/transactions",
+			httpmock.NewStringResponder(http.StatusNotFound,
+				`{"error":{"id":"404.2","name":"resource_not_found","detail":"Resource not found"}}`))
+
+		client := ynab.NewClient("")
+		uncategorized, unapproved, err := client.Transaction().CountNeedingAttention("aa248caa")
+		assert.Error(t, err)
+		assert.Zero(t, uncategorized)
+		assert.Zero(t, unapproved)
+	})
+}
+
+func TestService_ValidatesPayloads(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	date, err := api.DateFromString("2018-11-13")
+	assert.NoError(t, err)
+
+	payeeName := "bla bla bla"
+	valid := transaction.PayloadTransaction{
+		AccountID: "09eaca5e-312a-4bcd-89c4-828fb90638f2",
+		Date:      date,
+		Cleared:   transaction.ClearingStatusCleared,
+		PayeeName: &payeeName,
+	}
+	invalid := valid
+	invalid.AccountID = ""
+
+	client := ynab.NewClient("")
+	budgetID := "aa248caa-eed7-4575-a990-717386438d2c"
+
+	_, err = client.Transaction().CreateTransactions(budgetID,
+		[]transaction.PayloadTransaction{valid, invalid})
+	assert.ErrorIs(t, err, transaction.ErrAccountIDRequired)
+	assert.ErrorContains(t, err, "transaction 1:")
+
+	_, err = client.Transaction().CreateTransaction(budgetID, invalid)
+	assert.ErrorIs(t, err, transaction.ErrAccountIDRequired)
+
+	// Updates only check the fields they set
+	malformed := transaction.FlagColor("not a color")
+	invalidUpdate := transaction.PayloadTransaction{ID: "0f5b3f73-ded2-4dd7-8b01-c23022622cd6", FlagColor: &malformed}
+
+	_, err = client.Transaction().UpdateTransactions(budgetID,
+		[]transaction.PayloadTransaction{invalidUpdate})
+	assert.ErrorIs(t, err, transaction.ErrInvalidEnum)
+	assert.ErrorContains(t, err, "transaction 0:")
+
+	_, err = client.Transaction().UpdateTransaction(budgetID,
+		"0f5b3f73-ded2-4dd7-8b01-c23022622cd6", invalidUpdate)
+	assert.ErrorIs(t, err, transaction.ErrInvalidEnum)
+
+	assert.Zero(t, httpmock.GetTotalCallCount())
+
+	httpmock.RegisterResponder(http.MethodPatch, "https://api.youneedabudget.com/v1/budgets/"+budgetID+"/transactions",
+		httpmock.NewStringResponder(http.StatusOK, `{"data":{"transaction_ids":["0f5b3f73-ded2-4dd7-8b01-c23022622cd6"]}}`))
+	memo := "partial"
+	summary, err := client.Transaction().UpdateTransactions(budgetID,
+		[]transaction.PayloadTransaction{{ID: "0f5b3f73-ded2-4dd7-8b01-c23022622cd6", Memo: &memo}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0f5b3f73-ded2-4dd7-8b01-c23022622cd6"}, summary.TransactionIDs)
+}
+
+func TestService_GetTransactionsByCategoryDelta(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	url := "https://api.youneedabudget.com/v1/budgets/aa248caa-eed7-4575-a990-717386438d2c/categories/a33c906e-444c-469c-be27-04c8e0c9959f/transactions"
+	httpmock.RegisterResponder(http.MethodGet, url,
+		func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "last_knowledge_of_server=10", req.URL.RawQuery)
+			return httpmock.NewStringResponse(200, `{
+  "data": {
+    "transactions": [
+      {
+        "type": "transaction",
+        "id": "c132c55c-1200-4606-a321-99f4ec24b4df",
+        "date": "2018-01-10",
+        "amount": -42000,
+        "cleared": "reconciled",
+        "approved": true,
+        "account_id": "134d159-444c-469c-be27-44094e388fa0",
+        "account_name": "Cash",
+        "deleted": true
+      }
+    ],
+    "server_knowledge": 12
+  }
+}`), nil
+		},
+	)
+
+	knowledge := uint64(10)
+	client := ynab.NewClient("")
+	snapshot, err := client.Transaction().GetTransactionsByCategoryDelta(
+		"aa248caa-eed7-4575-a990-717386438d2c",
+		"a33c906e-444c-469c-be27-04c8e0c9959f",
+		&transaction.Filter{LastKnowledgeOfServer: &knowledge},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(12), snapshot.ServerKnowledge)
+	if assert.Len(t, snapshot.Transactions, 1) {
+		assert.Equal(t, "c132c55c-1200-4606-a321-99f4ec24b4df", snapshot.Transactions[0].ID)
+		assert.True(t, snapshot.Transactions[0].Deleted)
+	}
+}
+
+func TestService_GetTransactionsByPayeeDelta(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	url := "https://api.youneedabudget.com/v1/budgets/aa248caa-eed7-4575-a990-717386438d2c/payees/b391144e-444c-469c-be27-fed6aa352a7a/transactions"
+	httpmock.RegisterResponder(http.MethodGet, url,
+		func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "last_knowledge_of_server=20", req.URL.RawQuery)
+			return httpmock.NewStringResponse(200, `{
+  "data": {
+    "transactions": [
+      {
+        "type": "subtransaction",
+        "id": "c132c55c-1200-4606-a321-99f4ec24b4df",
+        "parent_transaction_id": "a9f4b2c1-1200-4606-a321-99f4ec24b4df",
+        "date": "2018-01-10",
+        "amount": -2000,
+        "cleared": "cleared",
+        "approved": true,
+        "account_id": "134d159-444c-469c-be27-44094e388fa0",
+        "account_name": "Cash",
+        "deleted": false
+      }
+    ],
+    "server_knowledge": 25
+  }
+}`), nil
+		},
+	)
+
+	knowledge := uint64(20)
+	client := ynab.NewClient("")
+	snapshot, err := client.Transaction().GetTransactionsByPayeeDelta(
+		"aa248caa-eed7-4575-a990-717386438d2c",
+		"b391144e-444c-469c-be27-fed6aa352a7a",
+		&transaction.Filter{LastKnowledgeOfServer: &knowledge},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(25), snapshot.ServerKnowledge)
+	if assert.Len(t, snapshot.Transactions, 1) {
+		assert.Equal(t, transaction.TypeSubTransaction, snapshot.Transactions[0].Type)
+	}
+}
+
+func TestService_GetTransactionsByAccount_Delta(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	url := "https://api.youneedabudget.com/v1/budgets/aa248caa/accounts/acc-1/transactions"
+	httpmock.RegisterResponder(http.MethodGet, url,
+		func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "last_knowledge_of_server=40", req.URL.RawQuery)
+			return httpmock.NewStringResponse(200, `{"data":{"transactions":[],"server_knowledge":42}}`), nil
+		},
+	)
+
+	knowledge := uint64(40)
+	client := ynab.NewClient("")
+	snapshot, err := client.Transaction().GetTransactionsByAccount("aa248caa", "acc-1",
+		&transaction.Filter{LastKnowledgeOfServer: &knowledge})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(42), snapshot.ServerKnowledge)
+	assert.Empty(t, snapshot.Transactions)
+}
+
+func TestService_GetAccountRegister(t *testing.T) {
+	accountURL := "https://api.youneedabudget.com/v1/budgets/aa248caa/accounts/acc-1"
+	transactionsURL := accountURL + "/transactions"
+	notFound := `{"error":{"id":"404.2","name":"resource_not_found","detail":"Resource not found"}}`
+
+	t.Run("newest first with running balances", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodGet, accountURL,
+			httpmock.NewStringResponder(200, `{"data":{"account":{"id":"acc-1","balance":150000,"closed":true,"deleted":false}}}`))
+		httpmock.RegisterResponder(http.MethodGet, transactionsURL,
+			httpmock.NewStringResponder(200, `{"data":{"transactions":[
+				{"id":"tx-b","date":"2024-01-02","amount":-20000},
+				{"id":"tx-a","date":"2024-01-01","amount":200000},
+				{"id":"tx-d","date":"2024-01-03","amount":-5000,"deleted":true},
+				{"id":"tx-c","date":"2024-01-02","amount":-30000}
+			],"server_knowledge":7}}`))
+
+		client := ynab.NewClient("")
+		register, err := client.Transaction().GetAccountRegister("aa248caa", "acc-1", nil)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "acc-1", register.AccountID)
+		assert.True(t, register.Closed)
+		assert.Equal(t, int64(150000), register.Balance)
+		assert.Equal(t, uint64(7), register.ServerKnowledge)
+
+		var ids []string
+		var balances []int64
+		for _, entry := range register.Entries {
+			ids = append(ids, entry.Transaction.ID)
+			balances = append(balances, entry.Balance)
+		}
+		assert.Equal(t, []string{"tx-d", "tx-c", "tx-b", "tx-a"}, ids)
+		assert.Equal(t, []int64{150000, 150000, 180000, 200000}, balances)
+	})
+
+	t.Run("empty register", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodGet, accountURL,
+			httpmock.NewStringResponder(200, `{"data":{"account":{"id":"acc-1","balance":0}}}`))
+		httpmock.RegisterResponder(http.MethodGet, transactionsURL,
+			httpmock.NewStringResponder(200, `{"data":{"transactions":[],"server_knowledge":3}}`))
+
+		client := ynab.NewClient("")
+		register, err := client.Transaction().GetAccountRegister("aa248caa", "acc-1", nil)
+		assert.NoError(t, err)
+		assert.Empty(t, register.Entries)
+	})
+
+	t.Run("account not found", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodGet, accountURL,
+			httpmock.NewStringResponder(404, notFound))
+
+		client := ynab.NewClient("")
+		register, err := client.Transaction().GetAccountRegister("aa248caa", "acc-1", nil)
+		assert.Nil(t, register)
+		assert.ErrorIs(t, err, transaction.ErrAccountNotFound)
+
+		var apiErr *api.Error
+		if assert.ErrorAs(t, err, &apiErr) {
+			assert.Equal(t, "404.2", apiErr.ID)
+		}
+		assert.Equal(t, 1, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("deleted account", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodGet, accountURL,
+			httpmock.NewStringResponder(200, `{"data":{"account":{"id":"acc-1","balance":0,"deleted":true}}}`))
+
+		client := ynab.NewClient("")
+		_, err := client.Transaction().GetAccountRegister("aa248caa", "acc-1", nil)
+		assert.ErrorIs(t, err, transaction.ErrAccountNotFound)
+	})
+
+	t.Run("other errors pass through", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodGet, accountURL,
+			httpmock.NewStringResponder(200, `{"data":{"account":{"id":"acc-1","balance":0}}}`))
+		httpmock.RegisterResponder(http.MethodGet, transactionsURL,
+			httpmock.NewStringResponder(500, `{"error":{"id":"500","name":"internal_server_error","detail":"Internal error"}}`))
+
+		client := ynab.NewClient("")
+		_, err := client.Transaction().GetAccountRegister("aa248caa", "acc-1", nil)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, transaction.ErrAccountNotFound)
+	})
+}
+
+func TestService_ApproveTransactions(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	baseURL := "https://api.youneedabudget.com/v1/budgets/aa248caa/transactions"
+	httpmock.RegisterResponder(http.MethodGet, baseURL,
+		func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "type=unapproved", req.URL.RawQuery)
+			return httpmock.NewStringResponse(200, `{"data":{"transactions":[
+				{"id":"tx-1","account_id":"acc-1","date":"2024-01-01","amount":-1000,"approved":false},
+				{"id":"tx-2","account_id":"acc-2","date":"2024-01-02","amount":-2000,"approved":false},
+				{"id":"tx-other","account_id":"acc-1","date":"2024-01-03","amount":-3000,"approved":false}
+			],"server_knowledge":10}}`), nil
+		},
+	)
+	httpmock.RegisterResponder(http.MethodGet, baseURL+"/tx-approved",
+		httpmock.NewStringResponder(200, `{"data":{"transaction":{"id":"tx-approved","account_id":"acc-1","date":"2024-01-01","amount":-4000,"approved":true}}}`))
+	httpmock.RegisterResponder(http.MethodGet, baseURL+"/tx-deleted",
+		httpmock.NewStringResponder(200, `{"data":{"transaction":{"id":"tx-deleted","account_id":"acc-1","date":"2024-01-01","amount":-5000,"deleted":true}}}`))
+	httpmock.RegisterResponder(http.MethodGet, baseURL+"/tx-missing",
+		httpmock.NewStringResponder(404, `{"error":{"id":"404.2","name":"resource_not_found","detail":"Resource not found"}}`))
+
+	httpmock.RegisterResponder(http.MethodPatch, baseURL,
+		func(req *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(req.Body)
+			assert.NoError(t, err)
+			assert.JSONEq(t, `{"transactions":[
+				{"id":"tx-1","account_id":"acc-1","approved":true},
+				{"id":"tx-2","account_id":"acc-2","approved":true}
+			]}`, string(body))
+			return httpmock.NewStringResponse(200, `{"data":{"transaction_ids":["tx-1","tx-2"],"transactions":[]}}`), nil
+		},
+	)
+
+	client := ynab.NewClient("")
+	summary, err := client.Transaction().ApproveTransactions("aa248caa",
+		[]string{"tx-1", "tx-missing", "tx-2", "tx-approved", "tx-deleted", "tx-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tx-1", "tx-2"}, summary.TransactionIDs)
+	assert.Equal(t, []string{"tx-missing", "tx-deleted"}, summary.MissingIDs)
+
+	info := httpmock.GetCallCountInfo()
+	assert.Equal(t, 1, info["PATCH "+baseURL])
+}
+
+func TestService_ApproveAndCategorize(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	baseURL := "https://api.youneedabudget.com/v1/budgets/aa248caa/transactions"
+	httpmock.RegisterResponder(http.MethodGet, baseURL,
+		httpmock.NewStringResponder(200, `{"data":{"transactions":[
+			{"id":"tx-1","account_id":"acc-1","date":"2024-01-01","amount":-1000,"approved":false},
+			{"id":"tx-2","account_id":"acc-2","date":"2024-01-02","amount":-2000,"approved":false,"category_id":"cat-old"},
+			{"id":"tx-split","account_id":"acc-1","date":"2024-01-03","amount":-3000,"approved":false,
+				"subtransactions":[{"id":"sub-1","amount":-1000},{"id":"sub-2","amount":-2000}]}
+		],"server_knowledge":10}}`))
+	httpmock.RegisterResponder(http.MethodGet, baseURL+"/tx-approved",
+		httpmock.NewStringResponder(200, `{"data":{"transaction":{"id":"tx-approved","account_id":"acc-3","date":"2024-01-01","amount":-4000,"approved":true,"category_id":"cat-old"}}}`))
+	httpmock.RegisterResponder(http.MethodGet, baseURL+"/tx-done",
+		httpmock.NewStringResponder(200, `{"data":{"transaction":{"id":"tx-done","account_id":"acc-3","date":"2024-01-01","amount":-4000,"approved":true,"category_id":"cat-dining"}}}`))
+	httpmock.RegisterResponder(http.MethodGet, baseURL+"/tx-missing",
+		httpmock.NewStringResponder(404, `{"error":{"id":"404.2","name":"resource_not_found","detail":"Resource not found"}}`))
+
+	httpmock.RegisterResponder(http.MethodPatch, baseURL,
+		func(req *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(req.Body)
+			assert.NoError(t, err)
+			assert.JSONEq(t, `{"transactions":[
+				{"id":"tx-1","account_id":"acc-1","category_id":"cat-groceries","approved":true},
+				{"id":"tx-2","account_id":"acc-2","category_id":"cat-dining","approved":true},
+				{"id":"tx-approved","account_id":"acc-3","category_id":"cat-groceries","approved":true}
+			]}`, string(body))
+			return httpmock.NewStringResponse(200, `{"data":{"transaction_ids":["tx-1","tx-2","tx-approved"],"transactions":[]}}`), nil
+		},
+	)
+
+	client := ynab.NewClient("")
+	summary, err := client.Transaction().ApproveAndCategorize("aa248caa", map[string]string{
+		"tx-1":        "cat-groceries",
+		"tx-2":        "cat-dining",
+		"tx-approved": "cat-groceries",
+		"tx-done":     "cat-dining",
+		"tx-split":    "cat-groceries",
+		"tx-missing":  "cat-groceries",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tx-1", "tx-2", "tx-approved"}, summary.TransactionIDs)
+	assert.Equal(t, []string{"tx-missing"}, summary.MissingIDs)
+	assert.Equal(t, []string{"tx-split"}, summary.SkippedSplitIDs)
+
+	info := httpmock.GetCallCountInfo()
+	assert.Equal(t, 1, info["PATCH "+baseURL])
+}
+
+func TestService_ApproveTransactions_NothingToApprove(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	baseURL := "https://api.youneedabudget.com/v1/budgets/aa248caa/transactions"
+	httpmock.RegisterResponder(http.MethodGet, baseURL,
+		httpmock.NewStringResponder(200, `{"data":{"transactions":[],"server_knowledge":10}}`))
+	httpmock.RegisterResponder(http.MethodGet, baseURL+"/tx-missing",
+		httpmock.NewStringResponder(404, `{"error":{"id":"404.2","name":"resource_not_found","detail":"Resource not found"}}`))
+
+	client := ynab.NewClient("")
+	summary, err := client.Transaction().ApproveTransactions("aa248caa", []string{"tx-missing"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tx-missing"}, summary.MissingIDs)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestService_ForecastCashFlow(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	url := "https://api.youneedabudget.com/v1/budgets/aa248caa-eed7-4575-a990-717386438d2c/scheduled_transactions"
+	httpmock.RegisterResponder(http.MethodGet, url,
+		httpmock.NewStringResponder(200, `{
+  "data": {
+    "scheduled_transactions": [
+      {"id": "groceries", "date_first": "2023-12-27", "date_next": "2024-01-03", "frequency": "weekly", "amount": -5000, "deleted": false},
+      {"id": "salary", "date_first": "2023-12-17", "date_next": "2024-01-17", "frequency": "monthly", "amount": 300000, "deleted": false},
+      {"id": "gym", "date_first": "2023-12-01", "date_next": "2024-01-05", "frequency": "weekly", "amount": -100000, "deleted": true}
+    ],
+    "server_knowledge": 10
+  }
+}`))
+
+	from, err := api.DateFromString("2024-01-01")
+	require.NoError(t, err)
+	to, err := api.DateFromString("2024-03-10")
+	require.NoError(t, err)
+
+	client := ynab.NewClient("")
+	forecast, err := client.Transaction().ForecastCashFlow("aa248caa-eed7-4575-a990-717386438d2c", from, to)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int64{
+		"2024-01-03": -5000,
+		"2024-01-10": -5000,
+		"2024-01-17": 295000,
+		"2024-01-24": -5000,
+		"2024-01-31": -5000,
+		"2024-02-07": -5000,
+		"2024-02-14": -5000,
+		"2024-02-17": 300000,
+		"2024-02-21": -5000,
+		"2024-02-28": -5000,
+		"2024-03-06": -5000,
+	}, forecast)
+}
+
+func TestService_CreateTransactions_IdempotencyKey(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var keys []string
+	url := "https://api.youneedabudget.com/v1/budgets/aa248caa-eed7-4575-a990-717386438d2c/transactions"
+	httpmock.RegisterResponder(http.MethodPost, url,
+		func(req *http.Request) (*http.Response, error) {
+			keys = append(keys, req.Header.Get(api.IdempotencyKeyHeader))
+			return httpmock.NewStringResponse(201, `{"data":{"transaction_ids":["tx-1"],"server_knowledge":1}}`), nil
+		},
+	)
+
+	date, err := api.DateFromString("2024-01-15")
+	require.NoError(t, err)
+	payeeName, importID := "Grocery Store", "YNAB:-1000:2024-01-15:1"
+	payload := transaction.PayloadTransaction{
+		AccountID: "09eaca5e-6f16-4480-9515-828fb90638f2",
+		Date:      date,
+		Amount:    -1000,
+		Cleared:   transaction.ClearingStatusCleared,
+		PayeeName: &payeeName,
+		ImportID:  &importID,
+	}
+
+	client := ynab.NewClient("")
+	budgetID := "aa248caa-eed7-4575-a990-717386438d2c"
+
+	_, err = client.Transaction().CreateTransaction(budgetID, payload)
+	require.NoError(t, err)
+	_, err = client.Transaction().CreateTransactions(budgetID, []transaction.PayloadTransaction{payload})
+	require.NoError(t, err)
+
+	withoutImportID := payload
+	withoutImportID.ImportID = nil
+	_, err = client.Transaction().CreateTransaction(budgetID, withoutImportID)
+	require.NoError(t, err)
+
+	_, err = client.Transaction().CreateTransaction(budgetID, withoutImportID, api.WithIdempotencyKey("custom-key"))
+	require.NoError(t, err)
+
+	require.Len(t, keys, 4)
+	assert.NotEmpty(t, keys[0], "key derived from the import ID")
+	assert.Equal(t, keys[0], keys[1], "key stable for the same payload")
+	assert.Empty(t, keys[2], "no key without import IDs")
+	assert.Equal(t, "custom-key", keys[3])
+}
+
+func TestService_GetTransactions_AmountRange(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var query string
+	httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets/aa248caa/transactions",
+		func(req *http.Request) (*http.Response, error) {
+			query = req.URL.RawQuery
+			return httpmock.NewStringResponse(http.StatusOK, `{"data":{"transactions":[
+				{"id":"tx-rent","amount":-150000},
+				{"id":"tx-groceries","amount":-100000},
+				{"id":"tx-dining","amount":-75000},
+				{"id":"tx-coffee","amount":-50000},
+				{"id":"tx-snack","amount":-2000},
+				{"id":"tx-paycheck","amount":300000}
+			],"server_knowledge":5}}`), nil
+		},
+	)
+	httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets/aa248caa/payees/payee-1/transactions",
+		httpmock.NewStringResponder(http.StatusOK, `{"data":{"transactions":[
+			{"id":"tx-small","amount":-1000},
+			{"id":"tx-large","amount":-90000}
+		],"server_knowledge":5}}`))
+
+	ids := func(transactions []*transaction.Transaction) []string {
+		out := make([]string, 0, len(transactions))
+		for _, tx := range transactions {
+			out = append(out, tx.ID)
+		}
+		return out
+	}
+	amount := func(v int64) *int64 { return &v }
+	client := ynab.NewClient("")
+
+	table := []struct {
+		name   string
+		filter *transaction.Filter
+		ids    []string
+	}{
+		{
+			name:   "lower bound only",
+			filter: &transaction.Filter{MinAmount: amount(-50000)},
+			ids:    []string{"tx-coffee", "tx-snack", "tx-paycheck"},
+		},
+		{
+			name:   "upper bound only",
+			filter: &transaction.Filter{MaxAmount: amount(-100000)},
+			ids:    []string{"tx-rent", "tx-groceries"},
+		},
+		{
+			name:   "both bounds",
+			filter: &transaction.Filter{MinAmount: amount(-100000), MaxAmount: amount(-50000)},
+			ids:    []string{"tx-groceries", "tx-dining", "tx-coffee"},
+		},
+		{
+			name:   "no bounds",
+			filter: nil,
+			ids:    []string{"tx-rent", "tx-groceries", "tx-dining", "tx-coffee", "tx-snack", "tx-paycheck"},
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := client.Transaction().GetTransactions("aa248caa", test.filter)
+			require.NoError(t, err)
+			assert.Equal(t, test.ids, ids(result.Transactions))
+			assert.Empty(t, query)
+		})
+	}
+
+	since, err := api.DateFromString("2024-01-01")
+	require.NoError(t, err)
+	_, err = client.Transaction().GetTransactions("aa248caa",
+		&transaction.Filter{Since: &since, MinAmount: amount(0)})
+	require.NoError(t, err)
+	assert.Equal(t, "since_date=2024-01-01", query)
+
+	hybrids, err := client.Transaction().GetTransactionsByPayee("aa248caa", "payee-1",
+		&transaction.Filter{MaxAmount: amount(-50000)})
+	require.NoError(t, err)
+	if assert.Len(t, hybrids, 1) {
+		assert.Equal(t, "tx-large", hybrids[0].ID)
+	}
+}
+
+func TestService_GetTransaction_NotFound(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets/wrong-budget/transactions/tx-1",
+		httpmock.NewStringResponder(http.StatusNotFound,
+			`{"error":{"id":"404.1","name":"not_found","detail":"The specified URI does not exist"}}`))
+	httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets/aa248caa/transactions/tx-deleted",
+		httpmock.NewStringResponder(http.StatusNotFound,
+			`{"error":{"id":"404.2","name":"resource_not_found","detail":"Resource not found"}}`))
+	client := ynab.NewClient("")
+
+	_, err := client.Transaction().GetTransaction("wrong-budget", "tx-1")
+	assert.EqualError(t, err,
+		`api: error id=404.1 name=not_found detail=The specified URI does not exist budget="wrong-budget"`)
+	assert.ErrorIs(t, err, api.ErrBudgetNotFound)
+	apiErr, ok := err.(*api.Error)
+	if assert.True(t, ok) {
+		assert.True(t, apiErr.IsURINotFound())
+		assert.True(t, apiErr.IsNotFound())
+		assert.Equal(t, "wrong-budget", apiErr.BudgetID)
+	}
+
+	_, err = client.Transaction().GetTransaction("aa248caa", "tx-deleted")
+	assert.NotErrorIs(t, err, api.ErrBudgetNotFound)
+	if assert.ErrorAs(t, err, &apiErr) {
+		assert.True(t, apiErr.IsResourceNotFound())
+		assert.False(t, apiErr.IsURINotFound())
+	}
+}
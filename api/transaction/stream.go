@@ -0,0 +1,119 @@
+package transaction
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/coltoneshaw/ynab.go/api"
+)
+
+// StreamTransactions fetches the transactions of a budget matching the
+// filter and calls fn with each of them as the response is read, without
+// holding the whole list in memory. Transactions outside the amount range
+// of the filter are skipped. The first error returned by fn stops the
+// stream and is returned as is.
+//
+// The response is decoded with encoding/json regardless of the codec of
+// the client, and the server knowledge is not reported; use GetTransactions
+// for delta requests.
+// https://api.youneedabudget.com/v1#/Transactions/getTransactions
+func (s *Service) StreamTransactions(budgetID string, f *Filter, fn func(*Transaction) error) error {
+	url := fmt.Sprintf("/budgets/%s/transactions", budgetID)
+	if f != nil {
+		url = fmt.Sprintf("%s?%s", url, f.ToQuery())
+	}
+
+	if f.hasAmountRange() {
+		next := fn
+		fn = func(t *Transaction) error {
+			if !f.matchesAmount(int64(t.Amount)) {
+				return nil
+			}
+			return next(t)
+		}
+	}
+
+	return api.GETStream(s.c, url, func(body io.Reader) error {
+		return decodeTransactionStream(body, fn)
+	})
+}
+
+// decodeTransactionStream walks the data.transactions array of a response
+// body, decoding one transaction at a time
+func decodeTransactionStream(body io.Reader, fn func(*Transaction) error) error {
+	dec := json.NewDecoder(body)
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+		if key != "data" {
+			if err := skipValue(dec); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := expectDelim(dec, '{'); err != nil {
+			return err
+		}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return fmt.Errorf("failed to parse response: %w", err)
+			}
+			if key != "transactions" {
+				if err := skipValue(dec); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := expectDelim(dec, '['); err != nil {
+				return err
+			}
+			for dec.More() {
+				t := &Transaction{}
+				if err := dec.Decode(t); err != nil {
+					return fmt.Errorf("failed to parse response: %w", err)
+				}
+				if err := fn(t); err != nil {
+					return err
+				}
+			}
+			if err := expectDelim(dec, ']'); err != nil {
+				return err
+			}
+		}
+		if err := expectDelim(dec, '}'); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+// expectDelim reads the next token of dec, failing unless it is delim
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	if token != delim {
+		return fmt.Errorf("failed to parse response: expected %q, got %v", delim, token)
+	}
+	return nil
+}
+
+// skipValue reads and discards the next value of dec
+func skipValue(dec *json.Decoder) error {
+	var skipped json.RawMessage
+	if err := dec.Decode(&skipped); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
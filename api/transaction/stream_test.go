@@ -0,0 +1,116 @@
+package transaction_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/jarcoal/httpmock.v1"
+
+	"github.com/coltoneshaw/ynab.go"
+	"github.com/coltoneshaw/ynab.go/api"
+	"github.com/coltoneshaw/ynab.go/api/transaction"
+)
+
+const streamedTransactions = `{
+  "data": {
+    "transactions": [
+      {"id": "tx-1", "date": "2024-01-01", "amount": -1000, "cleared": "cleared", "flag_color": "red", "subtransactions": []},
+      {"id": "tx-2", "date": "2024-01-02", "amount": -2000, "cleared": "uncleared", "subtransactions": [
+        {"id": "sub-1", "transaction_id": "tx-2", "amount": -2000}
+      ]},
+      {"id": "tx-3", "date": "2024-01-03", "amount": 3000, "cleared": "reconciled", "subtransactions": []}
+    ],
+    "server_knowledge": 42
+  }
+}`
+
+func TestService_StreamTransactions(t *testing.T) {
+	url := "https://api.youneedabudget.com/v1/budgets/aa248caa/transactions"
+
+	t.Run("success", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var query string
+		httpmock.RegisterResponder(http.MethodGet, url,
+			func(req *http.Request) (*http.Response, error) {
+				query = req.URL.RawQuery
+				return httpmock.NewStringResponse(http.StatusOK, streamedTransactions), nil
+			},
+		)
+
+		since, err := api.DateFromString("2024-01-01")
+		require.NoError(t, err)
+
+		var streamed []*transaction.Transaction
+		err = ynab.NewClient("").Transaction().StreamTransactions("aa248caa", &transaction.Filter{Since: &since},
+			func(tx *transaction.Transaction) error {
+				streamed = append(streamed, tx)
+				return nil
+			})
+		require.NoError(t, err)
+		assert.Equal(t, "since_date=2024-01-01", query)
+
+		require.Len(t, streamed, 3)
+		assert.Equal(t, "tx-1", streamed[0].ID)
+		assert.Equal(t, transaction.FlagColorRed, *streamed[0].FlagColor)
+		assert.Equal(t, api.Milliunits(-2000), streamed[1].Amount)
+		require.Len(t, streamed[1].SubTransactions, 1)
+		assert.Equal(t, "sub-1", streamed[1].SubTransactions[0].ID)
+		assert.Equal(t, transaction.ClearingStatusReconciled, streamed[2].Cleared)
+	})
+
+	t.Run("callback aborts the stream", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodGet, url,
+			httpmock.NewStringResponder(http.StatusOK, streamedTransactions))
+
+		stop := errors.New("enough")
+		calls := 0
+		err := ynab.NewClient("").Transaction().StreamTransactions("aa248caa", nil,
+			func(tx *transaction.Transaction) error {
+				calls++
+				if tx.ID == "tx-2" {
+					return stop
+				}
+				return nil
+			})
+		assert.ErrorIs(t, err, stop)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodGet, url,
+			httpmock.NewStringResponder(http.StatusNotFound,
+				`{"error":{"id":"404.2","name":"resource_not_found","detail":"Resource not found"}}`))
+
+		err := ynab.NewClient("").Transaction().StreamTransactions("aa248caa", nil,
+			func(*transaction.Transaction) error {
+				t.Fatal("no transaction expected")
+				return nil
+			})
+		var apiErr *api.Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.True(t, apiErr.IsNotFound())
+	})
+
+	t.Run("malformed body", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodGet, url,
+			httpmock.NewStringResponder(http.StatusOK, `{"data":{"transactions":{"id":"tx-1"}}}`))
+
+		err := ynab.NewClient("").Transaction().StreamTransactions("aa248caa", nil,
+			func(*transaction.Transaction) error { return nil })
+		assert.ErrorContains(t, err, "failed to parse response")
+	})
+}
@@ -1,7 +1,54 @@
 // Package user implements transaction user and services
 package user // import "github.com/coltoneshaw/ynab.go/api/user"
 
+import (
+	"bytes"
+	"encoding/json"
+)
+
 // User represents an user
 type User struct {
 	ID string `json:"id"`
+
+	// Raw the compacted user object as returned by the API, so fields
+	// added by YNAB after this library was released are not lost
+	Raw json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the known user fields and keeps the whole object in Raw
+func (u *User) UnmarshalJSON(b []byte) error {
+	// user has the same fields as User without its methods
+	type user User
+	var v user
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	var raw bytes.Buffer
+	if err := json.Compact(&raw, b); err != nil {
+		return err
+	}
+
+	*u = User(v)
+	u.Raw = raw.Bytes()
+	return nil
+}
+
+// Field decodes the named field of the raw user object into v, returning
+// false if the API did not return the field
+func (u *User) Field(name string, v any) (bool, error) {
+	if len(u.Raw) == 0 {
+		return false, nil
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(u.Raw, &fields); err != nil {
+		return false, err
+	}
+
+	value, ok := fields[name]
+	if !ok {
+		return false, nil
+	}
+	return true, json.Unmarshal(value, v)
 }
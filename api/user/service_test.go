@@ -34,8 +34,59 @@ func TestService_GetUser(t *testing.T) {
 	assert.NoError(t, err)
 
 	expected := &user.User{
-		ID: "aa248caa-eed7-4575-a990-717386438d2c",
+		ID:  "aa248caa-eed7-4575-a990-717386438d2c",
+		Raw: []byte(`{"id":"aa248caa-eed7-4575-a990-717386438d2c"}`),
 	}
 	assert.Equal(t, expected, u)
 
 }
+
+func TestService_GetUser_ExtraFields(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/user",
+		func(req *http.Request) (*http.Response, error) {
+			res := httpmock.NewStringResponse(200, `{
+  "data": {
+    "user": {
+      "id": "aa248caa-eed7-4575-a990-717386438d2c",
+      "default_budget_id": "f419ac25-6217-4175-88dc-c3136ff5f6fd",
+      "settings": {"locale": "en-US"}
+    }
+  }
+}
+		`)
+			return res, nil
+		},
+	)
+
+	client := ynab.NewClient("")
+	u, err := client.User().GetUser()
+	assert.NoError(t, err)
+	assert.Equal(t, "aa248caa-eed7-4575-a990-717386438d2c", u.ID)
+	assert.JSONEq(t, `{
+		"id": "aa248caa-eed7-4575-a990-717386438d2c",
+		"default_budget_id": "f419ac25-6217-4175-88dc-c3136ff5f6fd",
+		"settings": {"locale": "en-US"}
+	}`, string(u.Raw))
+
+	var defaultBudgetID string
+	ok, err := u.Field("default_budget_id", &defaultBudgetID)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "f419ac25-6217-4175-88dc-c3136ff5f6fd", defaultBudgetID)
+
+	settings := struct {
+		Locale string `json:"locale"`
+	}{}
+	ok, err = u.Field("settings", &settings)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "en-US", settings.Locale)
+
+	var missing string
+	ok, err = u.Field("email", &missing)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
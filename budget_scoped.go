@@ -0,0 +1,226 @@
+package ynab
+
+import (
+	"github.com/coltoneshaw/ynab.go/api"
+	"github.com/coltoneshaw/ynab.go/api/account"
+	"github.com/coltoneshaw/ynab.go/api/category"
+	"github.com/coltoneshaw/ynab.go/api/month"
+	"github.com/coltoneshaw/ynab.go/api/payee"
+	"github.com/coltoneshaw/ynab.go/api/transaction"
+)
+
+// BudgetScopedClient exposes the everyday account, category, payee, month
+// and transaction endpoints of a single budget without repeating its ID.
+// Every method delegates to the service of the underlying client; use
+// Client for the methods not mirrored here.
+type BudgetScopedClient struct {
+	c        ClientServicer
+	budgetID string
+}
+
+// ForBudget returns a facade over the services of the client scoped to
+// budgetID, which may also be budget.LastUsed or budget.Default
+func (c *client) ForBudget(budgetID string) *BudgetScopedClient {
+	return &BudgetScopedClient{c: c, budgetID: budgetID}
+}
+
+// BudgetID returns the ID of the budget the client is scoped to
+func (b *BudgetScopedClient) BudgetID() string {
+	return b.budgetID
+}
+
+// Client returns the underlying client
+func (b *BudgetScopedClient) Client() ClientServicer {
+	return b.c
+}
+
+// Accounts
+
+// GetAccounts fetches the accounts of the budget, see account.Service.GetAccounts
+func (b *BudgetScopedClient) GetAccounts(f *api.Filter) (*account.SearchResultSnapshot, error) {
+	return b.c.Account().GetAccounts(b.budgetID, f)
+}
+
+// GetAccount fetches an account of the budget, see account.Service.GetAccount
+func (b *BudgetScopedClient) GetAccount(accountID string) (*account.Account, error) {
+	return b.c.Account().GetAccount(b.budgetID, accountID)
+}
+
+// CreateAccount creates an account in the budget, see account.Service.CreateAccount
+func (b *BudgetScopedClient) CreateAccount(p account.PayloadAccount, opts ...api.WriteOption) (*account.Account, error) {
+	return b.c.Account().CreateAccount(b.budgetID, p, opts...)
+}
+
+// Categories
+
+// GetCategories fetches the categories of the budget, see category.Service.GetCategories
+func (b *BudgetScopedClient) GetCategories(f *api.Filter) (*category.SearchResultSnapshot, error) {
+	return b.c.Category().GetCategories(b.budgetID, f)
+}
+
+// GetCategory fetches a category of the budget, see category.Service.GetCategory
+func (b *BudgetScopedClient) GetCategory(categoryID string) (*category.Category, error) {
+	return b.c.Category().GetCategory(b.budgetID, categoryID)
+}
+
+// GetCategoryForMonth fetches a category of the budget for a month, see
+// category.Service.GetCategoryForMonth
+func (b *BudgetScopedClient) GetCategoryForMonth(categoryID string, month api.Date) (*category.Category, error) {
+	return b.c.Category().GetCategoryForMonth(b.budgetID, categoryID, month)
+}
+
+// UpdateCategoryForMonth updates a category of the budget for a month, see
+// category.Service.UpdateCategoryForMonth
+func (b *BudgetScopedClient) UpdateCategoryForMonth(categoryID string, month api.Date,
+	p category.PayloadMonthCategory) (*category.Category, error) {
+	return b.c.Category().UpdateCategoryForMonth(b.budgetID, categoryID, month, p)
+}
+
+// UpdateCategory updates a category of the budget, see category.Service.UpdateCategory
+func (b *BudgetScopedClient) UpdateCategory(categoryID string, p category.PayloadCategory) (*category.Category, error) {
+	return b.c.Category().UpdateCategory(b.budgetID, categoryID, p)
+}
+
+// Payees
+
+// GetPayees fetches the payees of the budget, see payee.Service.GetPayees
+func (b *BudgetScopedClient) GetPayees(f *api.Filter) (*payee.SearchResultSnapshot, error) {
+	return b.c.Payee().GetPayees(b.budgetID, f)
+}
+
+// GetPayee fetches a payee of the budget, see payee.Service.GetPayee
+func (b *BudgetScopedClient) GetPayee(payeeID string) (*payee.Payee, error) {
+	return b.c.Payee().GetPayee(b.budgetID, payeeID)
+}
+
+// UpdatePayee updates a payee of the budget, see payee.Service.UpdatePayee
+func (b *BudgetScopedClient) UpdatePayee(payeeID string, p payee.PayloadPayee) (*payee.Payee, error) {
+	return b.c.Payee().UpdatePayee(b.budgetID, payeeID, p)
+}
+
+// Months
+
+// GetMonths fetches the months of the budget, see month.Service.GetMonths
+func (b *BudgetScopedClient) GetMonths(f *api.Filter) (*month.SearchResultSnapshot, error) {
+	return b.c.Month().GetMonths(b.budgetID, f)
+}
+
+// GetMonth fetches a month of the budget, see month.Service.GetMonth
+func (b *BudgetScopedClient) GetMonth(m api.Date) (*month.Month, error) {
+	return b.c.Month().GetMonth(b.budgetID, m)
+}
+
+// Transactions
+
+// GetTransactions fetches the transactions of the budget, see
+// transaction.Service.GetTransactions
+func (b *BudgetScopedClient) GetTransactions(f *transaction.Filter) (*transaction.SearchResultSnapshot, error) {
+	return b.c.Transaction().GetTransactions(b.budgetID, f)
+}
+
+// GetTransaction fetches a transaction of the budget, see
+// transaction.Service.GetTransaction
+func (b *BudgetScopedClient) GetTransaction(transactionID string) (*transaction.Transaction, error) {
+	return b.c.Transaction().GetTransaction(b.budgetID, transactionID)
+}
+
+// GetTransactionsByAccount fetches the transactions of an account of the
+// budget, see transaction.Service.GetTransactionsByAccount
+func (b *BudgetScopedClient) GetTransactionsByAccount(accountID string,
+	f *transaction.Filter) (*transaction.SearchResultSnapshot, error) {
+	return b.c.Transaction().GetTransactionsByAccount(b.budgetID, accountID, f)
+}
+
+// GetTransactionsByCategory fetches the transactions of a category of the
+// budget, see transaction.Service.GetTransactionsByCategory
+func (b *BudgetScopedClient) GetTransactionsByCategory(categoryID string,
+	f *transaction.Filter) ([]*transaction.Hybrid, error) {
+	return b.c.Transaction().GetTransactionsByCategory(b.budgetID, categoryID, f)
+}
+
+// GetTransactionsByPayee fetches the transactions of a payee of the
+// budget, see transaction.Service.GetTransactionsByPayee
+func (b *BudgetScopedClient) GetTransactionsByPayee(payeeID string,
+	f *transaction.Filter) ([]*transaction.Hybrid, error) {
+	return b.c.Transaction().GetTransactionsByPayee(b.budgetID, payeeID, f)
+}
+
+// GetTransactionsByMonth fetches the transactions of a month of the
+// budget, see transaction.Service.GetTransactionsByMonth
+func (b *BudgetScopedClient) GetTransactionsByMonth(month string,
+	f *transaction.Filter) (*transaction.SearchResultSnapshot, error) {
+	return b.c.Transaction().GetTransactionsByMonth(b.budgetID, month, f)
+}
+
+// CreateTransaction creates a transaction in the budget, see
+// transaction.Service.CreateTransaction
+func (b *BudgetScopedClient) CreateTransaction(p transaction.PayloadTransaction,
+	opts ...api.WriteOption) (*transaction.OperationSummary, error) {
+	return b.c.Transaction().CreateTransaction(b.budgetID, p, opts...)
+}
+
+// CreateTransactions creates transactions in the budget, see
+// transaction.Service.CreateTransactions
+func (b *BudgetScopedClient) CreateTransactions(p []transaction.PayloadTransaction,
+	opts ...api.WriteOption) (*transaction.OperationSummary, error) {
+	return b.c.Transaction().CreateTransactions(b.budgetID, p, opts...)
+}
+
+// UpdateTransaction updates a transaction of the budget, see
+// transaction.Service.UpdateTransaction
+func (b *BudgetScopedClient) UpdateTransaction(transactionID string,
+	p transaction.PayloadTransaction) (*transaction.Transaction, error) {
+	return b.c.Transaction().UpdateTransaction(b.budgetID, transactionID, p)
+}
+
+// UpdateTransactions updates transactions of the budget, see
+// transaction.Service.UpdateTransactions
+func (b *BudgetScopedClient) UpdateTransactions(p []transaction.PayloadTransaction) (*transaction.OperationSummary, error) {
+	return b.c.Transaction().UpdateTransactions(b.budgetID, p)
+}
+
+// DeleteTransaction deletes a transaction of the budget, see
+// transaction.Service.DeleteTransaction
+func (b *BudgetScopedClient) DeleteTransaction(transactionID string) (*transaction.Transaction, error) {
+	return b.c.Transaction().DeleteTransaction(b.budgetID, transactionID)
+}
+
+// ImportTransactions imports the transactions of the linked accounts of the
+// budget, see transaction.Service.ImportTransactions
+func (b *BudgetScopedClient) ImportTransactions() (*transaction.ImportResult, error) {
+	return b.c.Transaction().ImportTransactions(b.budgetID)
+}
+
+// Scheduled transactions
+
+// GetScheduledTransactions fetches the scheduled transactions of the
+// budget, see transaction.Service.GetScheduledTransactions
+func (b *BudgetScopedClient) GetScheduledTransactions(f *api.Filter) (*transaction.ScheduledSearchResultSnapshot, error) {
+	return b.c.Transaction().GetScheduledTransactions(b.budgetID, f)
+}
+
+// GetScheduledTransaction fetches a scheduled transaction of the budget,
+// see transaction.Service.GetScheduledTransaction
+func (b *BudgetScopedClient) GetScheduledTransaction(scheduledTransactionID string) (*transaction.Scheduled, error) {
+	return b.c.Transaction().GetScheduledTransaction(b.budgetID, scheduledTransactionID)
+}
+
+// CreateScheduledTransaction creates a scheduled transaction in the budget,
+// see transaction.Service.CreateScheduledTransaction
+func (b *BudgetScopedClient) CreateScheduledTransaction(p transaction.PayloadScheduledTransaction,
+	opts ...api.WriteOption) (*transaction.Scheduled, error) {
+	return b.c.Transaction().CreateScheduledTransaction(b.budgetID, p, opts...)
+}
+
+// UpdateScheduledTransaction updates a scheduled transaction of the budget,
+// see transaction.Service.UpdateScheduledTransaction
+func (b *BudgetScopedClient) UpdateScheduledTransaction(scheduledTransactionID string,
+	p transaction.PayloadScheduledTransaction) (*transaction.Scheduled, error) {
+	return b.c.Transaction().UpdateScheduledTransaction(b.budgetID, scheduledTransactionID, p)
+}
+
+// DeleteScheduledTransaction deletes a scheduled transaction of the budget,
+// see transaction.Service.DeleteScheduledTransaction
+func (b *BudgetScopedClient) DeleteScheduledTransaction(scheduledTransactionID string) (*transaction.Scheduled, error) {
+	return b.c.Transaction().DeleteScheduledTransaction(b.budgetID, scheduledTransactionID)
+}
@@ -0,0 +1,162 @@
+package ynab
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/jarcoal/httpmock.v1"
+
+	"github.com/coltoneshaw/ynab.go/api"
+	"github.com/coltoneshaw/ynab.go/api/account"
+	"github.com/coltoneshaw/ynab.go/api/category"
+	"github.com/coltoneshaw/ynab.go/api/payee"
+	"github.com/coltoneshaw/ynab.go/api/transaction"
+)
+
+func TestBudgetScopedClient(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var requests []string
+	httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+		requests = append(requests, req.Method+" "+req.URL.String())
+		return httpmock.NewStringResponse(http.StatusOK, `{"data":{}}`), nil
+	})
+
+	const budgetID = "aa248caa"
+	c := NewClient("")
+	scoped := c.ForBudget(budgetID)
+	assert.Equal(t, budgetID, scoped.BudgetID())
+	assert.Equal(t, c, scoped.Client())
+
+	month, err := api.DateFromString("2024-01-01")
+	require.NoError(t, err)
+	since := &transaction.Filter{Since: &month}
+	payload := transaction.PayloadTransaction{AccountID: "acc", Date: month, Cleared: transaction.ClearingStatusCleared}
+	scheduled := transaction.PayloadScheduledTransaction{AccountID: "acc", Date: month,
+		Frequency: transaction.FrequencyMonthly}
+
+	calls := []struct {
+		name     string
+		scoped   func() error
+		unscoped func() error
+	}{
+		{"GetAccounts",
+			func() error { _, err := scoped.GetAccounts(nil); return err },
+			func() error { _, err := c.Account().GetAccounts(budgetID, nil); return err }},
+		{"GetAccount",
+			func() error { _, err := scoped.GetAccount("acc"); return err },
+			func() error { _, err := c.Account().GetAccount(budgetID, "acc"); return err }},
+		{"CreateAccount",
+			func() error {
+				_, err := scoped.CreateAccount(account.PayloadAccount{Name: "Cash", Type: account.TypeCash})
+				return err
+			},
+			func() error {
+				_, err := c.Account().CreateAccount(budgetID, account.PayloadAccount{Name: "Cash", Type: account.TypeCash})
+				return err
+			}},
+		{"GetCategories",
+			func() error { _, err := scoped.GetCategories(nil); return err },
+			func() error { _, err := c.Category().GetCategories(budgetID, nil); return err }},
+		{"GetCategory",
+			func() error { _, err := scoped.GetCategory("cat"); return err },
+			func() error { _, err := c.Category().GetCategory(budgetID, "cat"); return err }},
+		{"GetCategoryForMonth",
+			func() error { _, err := scoped.GetCategoryForMonth("cat", month); return err },
+			func() error { _, err := c.Category().GetCategoryForMonth(budgetID, "cat", month); return err }},
+		{"UpdateCategoryForMonth",
+			func() error {
+				_, err := scoped.UpdateCategoryForMonth("cat", month, category.PayloadMonthCategory{Budgeted: 1000})
+				return err
+			},
+			func() error {
+				_, err := c.Category().UpdateCategoryForMonth(budgetID, "cat", month, category.PayloadMonthCategory{Budgeted: 1000})
+				return err
+			}},
+		{"GetPayees",
+			func() error { _, err := scoped.GetPayees(nil); return err },
+			func() error { _, err := c.Payee().GetPayees(budgetID, nil); return err }},
+		{"GetPayee",
+			func() error { _, err := scoped.GetPayee("payee"); return err },
+			func() error { _, err := c.Payee().GetPayee(budgetID, "payee"); return err }},
+		{"UpdatePayee",
+			func() error { _, err := scoped.UpdatePayee("payee", payee.PayloadPayee{Name: "Shop"}); return err },
+			func() error {
+				_, err := c.Payee().UpdatePayee(budgetID, "payee", payee.PayloadPayee{Name: "Shop"})
+				return err
+			}},
+		{"GetMonths",
+			func() error { _, err := scoped.GetMonths(nil); return err },
+			func() error { _, err := c.Month().GetMonths(budgetID, nil); return err }},
+		{"GetMonth",
+			func() error { _, err := scoped.GetMonth(month); return err },
+			func() error { _, err := c.Month().GetMonth(budgetID, month); return err }},
+		{"GetTransactions",
+			func() error { _, err := scoped.GetTransactions(since); return err },
+			func() error { _, err := c.Transaction().GetTransactions(budgetID, since); return err }},
+		{"GetTransaction",
+			func() error { _, err := scoped.GetTransaction("tx"); return err },
+			func() error { _, err := c.Transaction().GetTransaction(budgetID, "tx"); return err }},
+		{"GetTransactionsByAccount",
+			func() error { _, err := scoped.GetTransactionsByAccount("acc", since); return err },
+			func() error { _, err := c.Transaction().GetTransactionsByAccount(budgetID, "acc", since); return err }},
+		{"GetTransactionsByCategory",
+			func() error { _, err := scoped.GetTransactionsByCategory("cat", since); return err },
+			func() error { _, err := c.Transaction().GetTransactionsByCategory(budgetID, "cat", since); return err }},
+		{"GetTransactionsByPayee",
+			func() error { _, err := scoped.GetTransactionsByPayee("payee", since); return err },
+			func() error { _, err := c.Transaction().GetTransactionsByPayee(budgetID, "payee", since); return err }},
+		{"GetTransactionsByMonth",
+			func() error { _, err := scoped.GetTransactionsByMonth("2024-01", since); return err },
+			func() error { _, err := c.Transaction().GetTransactionsByMonth(budgetID, "2024-01", since); return err }},
+		{"CreateTransaction",
+			func() error { _, err := scoped.CreateTransaction(payload.WithNewPayee("Shop")); return err },
+			func() error {
+				_, err := c.Transaction().CreateTransaction(budgetID, payload.WithNewPayee("Shop"))
+				return err
+			}},
+		{"UpdateTransaction",
+			func() error { _, err := scoped.UpdateTransaction("tx", payload.WithNewPayee("Shop")); return err },
+			func() error {
+				_, err := c.Transaction().UpdateTransaction(budgetID, "tx", payload.WithNewPayee("Shop"))
+				return err
+			}},
+		{"DeleteTransaction",
+			func() error { _, err := scoped.DeleteTransaction("tx"); return err },
+			func() error { _, err := c.Transaction().DeleteTransaction(budgetID, "tx"); return err }},
+		{"ImportTransactions",
+			func() error { _, err := scoped.ImportTransactions(); return err },
+			func() error { _, err := c.Transaction().ImportTransactions(budgetID); return err }},
+		{"GetScheduledTransactions",
+			func() error { _, err := scoped.GetScheduledTransactions(nil); return err },
+			func() error { _, err := c.Transaction().GetScheduledTransactions(budgetID, nil); return err }},
+		{"GetScheduledTransaction",
+			func() error { _, err := scoped.GetScheduledTransaction("sched"); return err },
+			func() error { _, err := c.Transaction().GetScheduledTransaction(budgetID, "sched"); return err }},
+		{"UpdateScheduledTransaction",
+			func() error { _, err := scoped.UpdateScheduledTransaction("sched", scheduled); return err },
+			func() error {
+				_, err := c.Transaction().UpdateScheduledTransaction(budgetID, "sched", scheduled)
+				return err
+			}},
+		{"DeleteScheduledTransaction",
+			func() error { _, err := scoped.DeleteScheduledTransaction("sched"); return err },
+			func() error { _, err := c.Transaction().DeleteScheduledTransaction(budgetID, "sched"); return err }},
+	}
+
+	for _, call := range calls {
+		t.Run(call.name, func(t *testing.T) {
+			requests = nil
+			scopedErr := call.scoped()
+			unscopedErr := call.unscoped()
+
+			assert.Equal(t, scopedErr, unscopedErr)
+			require.Len(t, requests, 2)
+			assert.Contains(t, requests[0], "/budgets/"+budgetID)
+			assert.Equal(t, requests[1], requests[0])
+		})
+	}
+}
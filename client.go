@@ -1,8 +1,14 @@
 // Package ynab implements the client API
+//
+// Every budgetID argument of the services accepts a budget ID or one of
+// the budget.LastUsed and budget.Default aliases.
 package ynab // import "github.com/coltoneshaw/ynab.go"
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"sync"
 	"time"
@@ -30,6 +36,12 @@ type ClientServicer interface {
 	Month() *month.Service
 	Transaction() *transaction.Service
 
+	// ForBudget returns the services scoped to a single budget
+	ForBudget(budgetID string) *BudgetScopedClient
+
+	// Ping checks that the API is reachable and accepts the token
+	Ping() error
+
 	// Rate limiting interface
 	api.RateLimiter
 
@@ -38,6 +50,61 @@ type ClientServicer interface {
 
 	// Token management interface
 	api.TokenProvider
+
+	// WithObserver sets an observer notified after every API request
+	WithObserver(observer api.Observer) ClientServicer
+
+	// WithStrictEnvelope makes responses missing their data envelope fail
+	// with api.ErrMissingEnvelope
+	WithStrictEnvelope() ClientServicer
+
+	// WithSingleFlight coalesces concurrent identical GET requests into a
+	// single API call
+	WithSingleFlight() ClientServicer
+
+	// WithMaxConcurrency bounds the number of simultaneous API requests
+	WithMaxConcurrency(n int) ClientServicer
+
+	// WithRetry retries rate limited and failed requests with backoff
+	WithRetry(maxAttempts int, strategy api.BackoffStrategy) ClientServicer
+
+	// WithErrorClassifier sets the policy deciding which API errors are
+	// retried and which are ignored
+	WithErrorClassifier(classifier api.ErrorClassifier) ClientServicer
+
+	// WithRequestTransformer sets a function rewriting every request body
+	// before it is sent
+	WithRequestTransformer(transform func(method, url string, body []byte) ([]byte, error)) ClientServicer
+
+	// WithRateLimit sets the limit enforced by the local rate limit tracker
+	WithRateLimit(limit int, window time.Duration) ClientServicer
+
+	// RateLimitConfig returns the limit enforced by the local rate limit
+	// tracker
+	RateLimitConfig() (limit int, window time.Duration)
+
+	// WithoutRateLimitTracking disables the local rate limit tracker
+	WithoutRateLimitTracking() ClientServicer
+
+	// WithWriteCost sets the weight writes are recorded with by the local
+	// rate limit tracker
+	WithWriteCost(n int) ClientServicer
+
+	// WithCodec sets the codec used for request and response bodies
+	WithCodec(codec api.Codec) ClientServicer
+
+	// WithBaseContext sets the context every request is sent with
+	WithBaseContext(ctx context.Context) ClientServicer
+
+	// WithKnowledgeCache makes GET requests send the server knowledge last
+	// returned by their endpoint
+	WithKnowledgeCache() ClientServicer
+
+	// ResetKnowledge forgets the server knowledge cached for a budget
+	ResetKnowledge(budgetID string)
+
+	// Codec returns the codec used for request and response bodies
+	api.CodecProvider
 }
 
 // NewClient facilitates the creation of a new client instance with a static token
@@ -52,6 +119,7 @@ func NewClientWithTokenProvider(tokenProvider api.TokenProvider) ClientServicer
 		tokenProvider: tokenProvider,
 		httpClient:    api.NewHTTPClient(),
 		rateLimiter:   api.NewYNABRateLimitTracker(),
+		writeCost:     1,
 	}
 
 	c.user = user.NewService(c)
@@ -74,6 +142,37 @@ type client struct {
 
 	rateLimiter *api.RateLimitTracker
 
+	// writeCost is the weight non-GET requests are recorded with by the
+	// rate limit tracker, 1 unless WithWriteCost is used
+	writeCost int
+
+	// flights coalesces concurrent GETs, nil unless WithSingleFlight is used
+	flights *singleFlight
+
+	// slots bounds in-flight requests, nil unless WithMaxConcurrency is used
+	slots chan struct{}
+
+	// maxAttempts and backoff configure retries, disabled unless WithRetry
+	// is used
+	maxAttempts int
+	backoff     api.BackoffStrategy
+
+	// knowledge caches the server knowledge of each endpoint, nil unless
+	// WithKnowledgeCache is used
+	knowledge *knowledgeCache
+
+	// classifier decides which errors are retried or ignored, the built-in
+	// policy unless WithErrorClassifier is used
+	classifier api.ErrorClassifier
+
+	// transform rewrites request bodies before they are sent, nil unless
+	// WithRequestTransformer is used
+	transform func(method, url string, body []byte) ([]byte, error)
+
+	// baseCtx is the context requests are sent with, context.Background()
+	// unless WithBaseContext is used
+	baseCtx context.Context
+
 	user        *user.Service
 	budget      *budget.Service
 	account     *account.Service
@@ -89,6 +188,155 @@ func (c *client) WithHTTPClient(httpClient *http.Client) api.HTTPClientConfigure
 	return c
 }
 
+// WithTimeout sets the request timeout and returns the client for chaining
+func (c *client) WithTimeout(timeout time.Duration) api.HTTPClientConfigurer {
+	c.httpClient = c.httpClient.WithTimeout(timeout)
+	return c
+}
+
+// WithObserver sets an observer notified after every API request and
+// returns the client for chaining
+func (c *client) WithObserver(observer api.Observer) ClientServicer {
+	c.httpClient = c.httpClient.WithObserver(observer)
+	return c
+}
+
+// WithStrictEnvelope makes successful responses without a data key fail
+// with api.ErrMissingEnvelope and returns the client for chaining
+func (c *client) WithStrictEnvelope() ClientServicer {
+	c.httpClient = c.httpClient.WithStrictEnvelope()
+	return c
+}
+
+// WithSingleFlight makes concurrent GET requests for the same URL share a
+// single API call, with every caller decoding the same response. Only
+// the call actually sent is recorded for rate limiting. Returns the
+// client for chaining.
+func (c *client) WithSingleFlight() ClientServicer {
+	c.flights = &singleFlight{}
+	return c
+}
+
+// WithMaxConcurrency limits the client to n simultaneous API requests
+// across all services; further requests block until a slot is free.
+// A value of zero or less removes the limit. Returns the client for
+// chaining.
+func (c *client) WithMaxConcurrency(n int) ClientServicer {
+	if n <= 0 {
+		c.slots = nil
+		return c
+	}
+	c.slots = make(chan struct{}, n)
+	return c
+}
+
+// WithRetry makes the client send each request up to maxAttempts times
+// while it fails with a retryable api.Error (see api.Error.IsRetryable),
+// waiting strategy.NextDelay between attempts. POST requests are only
+// retried when rate limited, as a server error may come after the
+// resources were created. A nil strategy uses api.DefaultBackoff, and a
+// maxAttempts of one or less disables retries. Returns the client for
+// chaining.
+func (c *client) WithRetry(maxAttempts int, strategy api.BackoffStrategy) ClientServicer {
+	if strategy == nil {
+		strategy = api.DefaultBackoff()
+	}
+	c.maxAttempts = maxAttempts
+	c.backoff = strategy
+	return c
+}
+
+// WithErrorClassifier sets the policy applied to API errors. Errors
+// classified as api.ErrorClassTransient are retried as configured by
+// WithRetry, and errors classified as api.ErrorClassIgnorable are reported
+// as success, leaving the response model untouched. A classifier replaces
+// the built-in policy entirely, including the rule that POST requests are
+// only retried when rate limited. A nil classifier restores the built-in
+// policy.
+func (c *client) WithErrorClassifier(classifier api.ErrorClassifier) ClientServicer {
+	c.classifier = classifier
+	return c
+}
+
+// WithRequestTransformer sets a function called with the method, path and
+// encoded body of every request, including those without a body, before it
+// is sent. The returned body is sent instead, e.g. to prefix memos or strip
+// personal data, and an error aborts the request with that error. Retries
+// resend the transformed body without calling transform again. A nil
+// transform removes it. Returns the client for chaining.
+func (c *client) WithRequestTransformer(transform func(method, url string, body []byte) ([]byte, error)) ClientServicer {
+	c.transform = transform
+	return c
+}
+
+// WithRateLimit replaces the default tracker of 200 requests per hour
+// with one allowing limit requests per rolling window, e.g. for a plan
+// with different limits or for testing. Requests recorded so far are
+// forgotten. Returns the client for chaining.
+func (c *client) WithRateLimit(limit int, window time.Duration) ClientServicer {
+	c.rateLimiter = api.NewRateLimitTracker(limit, window)
+	return c
+}
+
+// RateLimitConfig returns the number of requests allowed per rolling
+// window by the rate limit tracker. The limit is
+// api.RateLimitTrackingDisabled when tracking is disabled.
+func (c *client) RateLimitConfig() (limit int, window time.Duration) {
+	if c.rateLimiter.IsDisabled() {
+		return api.RateLimitTrackingDisabled, c.rateLimiter.GetWindow()
+	}
+	return c.rateLimiter.GetLimit(), c.rateLimiter.GetWindow()
+}
+
+// WithoutRateLimitTracking stops recording requests for rate limiting,
+// for clients behind a gateway enforcing its own limits. Afterwards
+// RequestsRemaining returns api.RateLimitTrackingDisabled and IsAtLimit
+// is always false. Returns the client for chaining.
+func (c *client) WithoutRateLimitTracking() ClientServicer {
+	c.rateLimiter = api.NewDisabledRateLimitTracker()
+	return c
+}
+
+// WithWriteCost makes every successful write, i.e. any request other than
+// a GET, count as n requests against the rate limit tracker while reads
+// still count as one, so conservative callers keep headroom for writes.
+// A negative n is treated as zero. Returns the client for chaining.
+func (c *client) WithWriteCost(n int) ClientServicer {
+	c.writeCost = max(n, 0)
+	return c
+}
+
+// WithCodec sets the codec used to encode request bodies and decode
+// response bodies, e.g. to plug in a faster JSON library. A nil codec
+// restores api.StdlibCodec. Returns the client for chaining.
+func (c *client) WithCodec(codec api.Codec) ClientServicer {
+	c.httpClient = c.httpClient.WithCodec(codec)
+	return c
+}
+
+// WithBaseContext sets the context every request is sent with, so values
+// such as a request-scoped logger or a deadline can be attached once. Once
+// ctx is done, requests fail with its error without reaching the API. A nil
+// ctx restores context.Background().
+func (c *client) WithBaseContext(ctx context.Context) ClientServicer {
+	c.baseCtx = ctx
+	return c
+}
+
+// requestContext returns the context requests are sent with by default
+func (c *client) requestContext() context.Context {
+	if c.baseCtx == nil {
+		return context.Background()
+	}
+	return c.baseCtx
+}
+
+// Codec returns the codec used to encode request bodies and decode
+// response bodies
+func (c *client) Codec() api.Codec {
+	return c.httpClient.Codec()
+}
+
 // User returns user.Service API instance
 func (c *client) User() *user.Service {
 	return c.user
@@ -124,6 +372,16 @@ func (c *client) Transaction() *transaction.Service {
 	return c.transaction
 }
 
+// Ping checks that the API is reachable and accepts the token with a
+// request to the lightweight user endpoint, e.g. as a health check or to
+// validate setup. It returns nil on success; when the token is rejected the
+// error matches api.ErrUnauthorized, and any other failure, such as a
+// network error, is returned as is.
+// https://api.youneedabudget.com/v1#/User/getUser
+func (c *client) Ping() error {
+	return c.GET("/user", nil)
+}
+
 // RequestsRemaining returns how many requests can be made before hitting the rate limit
 func (c *client) RequestsRemaining() int {
 	return c.rateLimiter.RequestsRemaining()
@@ -168,49 +426,196 @@ func (c *client) IsAuthenticated() bool {
 	return c.tokenProvider.IsAuthenticated()
 }
 
+// CanWrite returns true if the current token permits write requests
+func (c *client) CanWrite() bool {
+	return c.tokenProvider.CanWrite()
+}
+
 // GET sends a GET request to the YNAB API
 func (c *client) GET(url string, responseModel any) error {
-	return c.do(http.MethodGet, url, responseModel, nil)
+	if c.knowledge != nil {
+		return c.getWithKnowledge(url, responseModel)
+	}
+	return c.do(c.requestContext(), http.MethodGet, url, responseModel, nil, nil)
 }
 
 // POST sends a POST request to the YNAB API
 func (c *client) POST(url string, responseModel any, requestBody []byte) error {
-	return c.do(http.MethodPost, url, responseModel, requestBody)
+	return c.do(c.requestContext(), http.MethodPost, url, responseModel, requestBody, nil)
+}
+
+// POSTWithHeader sends a POST request with extra headers to the YNAB API
+func (c *client) POSTWithHeader(url string, responseModel any, requestBody []byte, header http.Header) error {
+	return c.do(c.requestContext(), http.MethodPost, url, responseModel, requestBody, header)
+}
+
+// GETStream sends a GET request to the YNAB API and passes the response
+// body to fn as it is read
+func (c *client) GETStream(url string, fn api.StreamFunc) error {
+	return c.do(c.requestContext(), http.MethodGet, url, fn, nil, nil)
 }
 
 // PUT sends a PUT request to the YNAB API
 func (c *client) PUT(url string, responseModel any, requestBody []byte) error {
-	return c.do(http.MethodPut, url, responseModel, requestBody)
+	return c.do(c.requestContext(), http.MethodPut, url, responseModel, requestBody, nil)
 }
 
 // PATCH sends a PATCH request to the YNAB API
 func (c *client) PATCH(url string, responseModel any, requestBody []byte) error {
-	return c.do(http.MethodPatch, url, responseModel, requestBody)
+	return c.do(c.requestContext(), http.MethodPatch, url, responseModel, requestBody, nil)
 }
 
 // DELETE sends a DELETE request to the YNAB API
 func (c *client) DELETE(url string, responseModel any) error {
-	return c.do(http.MethodDelete, url, responseModel, nil)
+	return c.do(c.requestContext(), http.MethodDelete, url, responseModel, nil, nil)
+}
+
+// do sends a request to the YNAB API with ctx, retrying it as configured by
+// WithRetry. Cancelling ctx during a backoff delay returns ctx.Err().
+func (c *client) do(ctx context.Context, method, url string, responseModel any, requestBody []byte, header http.Header) error {
+	if c.transform != nil {
+		body, err := c.transform(method, url, requestBody)
+		if err != nil {
+			return err
+		}
+		requestBody = body
+	}
+
+	err := c.authorized(ctx, method, url, responseModel, requestBody, header)
+	for attempt := 1; attempt < c.maxAttempts && c.shouldRetry(method, err); attempt++ {
+		timer := time.NewTimer(c.backoff.NextDelay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		err = c.authorized(ctx, method, url, responseModel, requestBody, header)
+	}
+
+	if err != nil && c.classifier != nil && api.ClassifyError(err, c.classifier) == api.ErrorClassIgnorable {
+		return nil
+	}
+	return err
+}
+
+// shouldRetry reports whether a request failing with err may be sent again
+func (c *client) shouldRetry(method string, err error) bool {
+	var apiErr *api.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	// A failed response without an error object is judged by its status
+	// code, which HandleResponse forges into the error ID; a nil error
+	// carries none and is retried like a server error
+	if apiErr == nil {
+		return method != http.MethodPost
+	}
+	if c.classifier != nil {
+		return c.classifier(apiErr) == api.ErrorClassTransient
+	}
+	if method == http.MethodPost {
+		return apiErr.IsRateLimit()
+	}
+	return apiErr.IsRetryable()
 }
 
-// do sends a request to the YNAB API
-func (c *client) do(method, url string, responseModel any, requestBody []byte) error {
-	token, err := c.tokenProvider.GetAccessToken(context.Background())
+// authorized sends a request with the current token of the provider
+func (c *client) authorized(ctx context.Context, method, url string, responseModel any, requestBody []byte,
+	header http.Header) error {
+	token, err := c.tokenProvider.GetAccessToken(ctx)
 	if err != nil {
 		return err
 	}
 
-	err = c.httpClient.DoRequest(context.Background(), method, url, responseModel, requestBody, token)
+	err = c.attempt(ctx, method, url, responseModel, requestBody, token, header)
+
+	// A provider backed by a rotating source may hand out a fresh token
+	// after a 401; retry once if it does. Static tokens never change.
+	var apiErr *api.Error
+	if errors.As(err, &apiErr) && apiErr != nil && apiErr.IsUnauthorized() {
+		fresh, tokenErr := c.tokenProvider.GetAccessToken(ctx)
+		if tokenErr == nil && fresh != token {
+			err = c.attempt(ctx, method, url, responseModel, requestBody, fresh, header)
+		}
+	}
+
+	// A personal access token is only rejected when revoked or mistyped
+	if _, static := c.tokenProvider.(*api.StaticTokenProvider); static && errors.Is(err, api.ErrUnauthorized) {
+		return fmt.Errorf("%w: %w", api.ErrTokenRejected, err)
+	}
+
+	return err
+}
+
+// attempt sends a request with the given token, recording it for rate
+// limiting when it succeeds
+func (c *client) attempt(ctx context.Context, method, url string, responseModel any, requestBody []byte, token string,
+	header http.Header) error {
+	if _, stream := responseModel.(api.StreamFunc); c.flights != nil && method == http.MethodGet && !stream {
+		return c.doShared(ctx, url, responseModel, token)
+	}
+
+	err := c.send(ctx, method, url, responseModel, requestBody, token, header)
 	if err != nil {
 		return err
 	}
 
 	// Record successful request for rate limiting
-	c.rateLimiter.RecordRequest()
+	if method == http.MethodGet {
+		c.rateLimiter.RecordRequest()
+	} else {
+		c.rateLimiter.RecordRequestWeighted(c.writeCost)
+	}
 
 	return nil
 }
 
+// doShared sends a GET request coalesced with identical in-flight ones.
+// The key includes the token so responses are never shared across users.
+func (c *client) doShared(ctx context.Context, url string, responseModel any, token string) error {
+	body, leader, err := c.flights.do(ctx, url+"\x00"+token, func() ([]byte, error) {
+		var body json.RawMessage
+		err := c.send(ctx, http.MethodGet, url, &body, nil, token, nil)
+		return body, err
+	})
+	if err != nil {
+		return err
+	}
+
+	if leader {
+		c.rateLimiter.RecordRequest()
+	}
+
+	if responseModel == nil || len(body) == 0 {
+		return nil
+	}
+	if err := c.Codec().Unmarshal(body, responseModel); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// send performs the HTTP request once a concurrency slot is available,
+// giving up if ctx is done first
+func (c *client) send(ctx context.Context, method, url string, responseModel any, requestBody []byte, token string,
+	header http.Header) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if slots := c.slots; slots != nil {
+		select {
+		case slots <- struct{}{}:
+			defer func() { <-slots }()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return c.httpClient.DoRequestWithHeader(ctx, method, url, responseModel, requestBody, token, header)
+}
+
 // OAuth convenience functions
 
 // NewOAuthConfig creates a new OAuth configuration
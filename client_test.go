@@ -1,13 +1,21 @@
 package ynab
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/coltoneshaw/ynab.go/api"
+	"github.com/coltoneshaw/ynab.go/api/category"
+	"github.com/coltoneshaw/ynab.go/api/transaction"
+	"github.com/coltoneshaw/ynab.go/oauth"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/jarcoal/httpmock.v1"
 )
@@ -120,6 +128,22 @@ func TestClient_GET(t *testing.T) {
 		assert.EqualError(t, err, expectedErrStr)
 	})
 
+	t.Run("failure with a JSON body without an error object", func(t *testing.T) {
+		for _, status := range []int{http.StatusNotFound, http.StatusInternalServerError} {
+			httpmock.Activate()
+			httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s%s", apiEndpoint, "/budgets/aa248caa/foo"),
+				httpmock.NewStringResponder(status, `{"message":"oops"}`))
+
+			err := NewClient("").(*client).GET("/budgets/aa248caa/foo", nil)
+			apiErr, ok := err.(*api.Error)
+			if assert.True(t, ok, status) && assert.NotNil(t, apiErr, status) {
+				assert.Equal(t, strconv.Itoa(status), apiErr.ID)
+				assert.Equal(t, "unknown_api_error", apiErr.Name)
+			}
+			httpmock.DeactivateAndReset()
+		}
+	})
+
 	t.Run("silent failure due to invalid response model", func(t *testing.T) {
 		httpmock.Activate()
 		defer httpmock.DeactivateAndReset()
@@ -704,3 +728,933 @@ func TestClient_RateLimitingNotTrackedOnError(t *testing.T) {
 	assert.Equal(t, 200, c.RequestsRemaining()) // Should remain unchanged
 	assert.Equal(t, 0, c.RequestsInWindow())    // Should remain unchanged
 }
+
+func TestClient_EmptyResponseBody(t *testing.T) {
+	t.Run("204 no content", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodDelete, fmt.Sprintf("%s%s", apiEndpoint, "/foo"),
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewStringResponse(http.StatusNoContent, ""), nil
+			},
+		)
+
+		response := struct {
+			Foo string `json:"foo"`
+		}{}
+
+		c := NewClient("")
+		err := c.(*client).DELETE("/foo", &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "", response.Foo)
+		assert.Equal(t, 1, c.RequestsInWindow())
+	})
+
+	t.Run("200 with empty body", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s%s", apiEndpoint, "/foo"),
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewStringResponse(http.StatusOK, ""), nil
+			},
+		)
+
+		response := struct {
+			Foo string `json:"foo"`
+		}{}
+
+		c := NewClient("")
+		err := c.(*client).POST("/foo", &response, []byte(`{"bar":"foo"}`))
+		assert.NoError(t, err)
+		assert.Equal(t, "", response.Foo)
+		assert.Equal(t, 1, c.RequestsInWindow())
+	})
+}
+
+func TestClient_WithTimeout(t *testing.T) {
+	t.Run("default timeout", func(t *testing.T) {
+		c := NewClient("")
+		assert.Equal(t, api.DefaultTimeout, c.(*client).httpClient.Timeout())
+	})
+
+	t.Run("request exceeding timeout fails", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s%s", apiEndpoint, "/slow"),
+			func(req *http.Request) (*http.Response, error) {
+				select {
+				case <-time.After(2 * time.Second):
+					return httpmock.NewStringResponse(http.StatusOK, `{"foo":"bar"}`), nil
+				case <-req.Context().Done():
+					return nil, req.Context().Err()
+				}
+			},
+		)
+
+		c := NewClient("")
+		c.WithTimeout(50 * time.Millisecond)
+
+		start := time.Now()
+		err := c.(*client).GET("/slow", nil)
+		assert.Error(t, err)
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, 0, c.RequestsInWindow())
+	})
+
+	t.Run("last setter wins", func(t *testing.T) {
+		custom := &http.Client{Timeout: 5 * time.Second}
+
+		c := NewClient("")
+		c.WithHTTPClient(custom).WithTimeout(time.Second)
+		assert.Equal(t, time.Second, c.(*client).httpClient.Timeout())
+		assert.Equal(t, 5*time.Second, custom.Timeout, "custom client must not be modified")
+
+		c.WithHTTPClient(custom)
+		assert.Equal(t, 5*time.Second, c.(*client).httpClient.Timeout())
+	})
+}
+
+func TestClient_WithObserver(t *testing.T) {
+	var observed []api.RequestInfo
+	observer := api.ObserverFunc(func(info api.RequestInfo) {
+		observed = append(observed, info)
+	})
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s%s", apiEndpoint, "/budgets/aa248caa/accounts/e5a9b1c7"),
+		func(req *http.Request) (*http.Response, error) {
+			time.Sleep(10 * time.Millisecond)
+			return httpmock.NewStringResponse(http.StatusOK, `{"foo":"bar"}`), nil
+		},
+	)
+	httpmock.RegisterResponder(http.MethodPut, fmt.Sprintf("%s%s", apiEndpoint, "/budgets/aa248caa/months/2024-01-01/categories/13419c12"),
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewStringResponse(http.StatusNotFound, `{"error":{"id":"404.2","name":"resource_not_found","detail":"Resource not found"}}`), nil
+		},
+	)
+
+	c := NewClient("").WithObserver(observer)
+
+	err := c.(*client).GET("/budgets/aa248caa/accounts/e5a9b1c7", nil)
+	assert.NoError(t, err)
+
+	err = c.(*client).PUT("/budgets/aa248caa/months/2024-01-01/categories/13419c12", nil, []byte(`{}`))
+	assert.Error(t, err)
+
+	if assert.Len(t, observed, 2) {
+		assert.Equal(t, http.MethodGet, observed[0].Method)
+		assert.Equal(t, "/budgets/{budget_id}/accounts/{account_id}", observed[0].Path)
+		assert.Equal(t, http.StatusOK, observed[0].StatusCode)
+		assert.GreaterOrEqual(t, observed[0].Duration, 10*time.Millisecond)
+		assert.NoError(t, observed[0].Err)
+
+		assert.Equal(t, http.MethodPut, observed[1].Method)
+		assert.Equal(t, "/budgets/{budget_id}/months/{month}/categories/{category_id}", observed[1].Path)
+		assert.Equal(t, http.StatusNotFound, observed[1].StatusCode)
+		assert.Positive(t, observed[1].Duration)
+		assert.Equal(t, err, observed[1].Err)
+	}
+}
+
+func TestClient_CanWrite(t *testing.T) {
+	t.Run("personal access token", func(t *testing.T) {
+		c := NewClient("token")
+		assert.True(t, c.CanWrite())
+	})
+
+	t.Run("read-only OAuth token", func(t *testing.T) {
+		config := NewOAuthConfig("client-id", "client-secret", "https://example.com/callback")
+		token := &oauth.Token{
+			AccessToken: "token",
+			Scope:       oauth.ScopeReadOnly,
+			ExpiresAt:   time.Now().Add(time.Hour),
+		}
+
+		c, err := NewOAuthClientFromToken(config, token)
+		assert.NoError(t, err)
+		assert.False(t, c.CanWrite())
+	})
+
+	t.Run("full access OAuth token", func(t *testing.T) {
+		config := NewOAuthConfig("client-id", "client-secret", "https://example.com/callback")
+		token := &oauth.Token{AccessToken: "token", ExpiresAt: time.Now().Add(time.Hour)}
+
+		c, err := NewOAuthClientFromToken(config, token)
+		assert.NoError(t, err)
+		assert.True(t, c.CanWrite())
+	})
+}
+
+func TestClient_WithSingleFlight(t *testing.T) {
+	t.Run("concurrent GETs share one call", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var (
+			mu    sync.Mutex
+			calls int
+		)
+		release := make(chan struct{})
+		httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s%s", apiEndpoint, "/budgets/aa248caa/categories"),
+			func(req *http.Request) (*http.Response, error) {
+				mu.Lock()
+				calls++
+				mu.Unlock()
+				<-release
+				return httpmock.NewStringResponse(http.StatusOK, `{"foo":"bar"}`), nil
+			},
+		)
+
+		c := NewClient("").WithSingleFlight()
+
+		const callers = 20
+		var wg sync.WaitGroup
+		results := make([]string, callers)
+		errs := make([]error, callers)
+		for i := 0; i < callers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				response := struct {
+					Foo string `json:"foo"`
+				}{}
+				errs[i] = c.(*client).GET("/budgets/aa248caa/categories", &response)
+				results[i] = response.Foo
+			}(i)
+		}
+
+		// Give every caller time to join the in-flight request
+		time.Sleep(100 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, 1, calls)
+		for i := 0; i < callers; i++ {
+			assert.NoError(t, errs[i])
+			assert.Equal(t, "bar", results[i])
+		}
+		assert.Equal(t, 1, c.RequestsInWindow())
+	})
+
+	t.Run("errors are shared", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s%s", apiEndpoint, "/foo"),
+			httpmock.NewStringResponder(http.StatusNotFound, `{"error":{"id":"404.2","name":"resource_not_found","detail":"Resource not found"}}`))
+
+		c := NewClient("").WithSingleFlight()
+		err := c.(*client).GET("/foo", nil)
+		apiErr, ok := err.(*api.Error)
+		if assert.True(t, ok) {
+			assert.Equal(t, api.ErrorResourceNotFound, apiErr.ID)
+		}
+		assert.Equal(t, 0, c.RequestsInWindow())
+	})
+
+	t.Run("writes are not coalesced", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s%s", apiEndpoint, "/foo"),
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewStringResponse(http.StatusOK, `{"foo":"bar"}`), nil
+			},
+		)
+
+		c := NewClient("").WithSingleFlight()
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, c.(*client).POST("/foo", nil, []byte(`{}`)))
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 5, httpmock.GetTotalCallCount())
+		assert.Equal(t, 5, c.RequestsInWindow())
+	})
+}
+
+func TestClient_WithMaxConcurrency(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	const limit = 3
+
+	var (
+		mu      sync.Mutex
+		current int
+		peak    int
+	)
+	httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s%s", apiEndpoint, "/foo"),
+		func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			current++
+			if current > peak {
+				peak = current
+			}
+			mu.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			mu.Lock()
+			current--
+			mu.Unlock()
+			return httpmock.NewStringResponse(http.StatusOK, `{"foo":"bar"}`), nil
+		},
+	)
+
+	c := NewClient("").WithMaxConcurrency(limit)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4*limit; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, c.(*client).GET("/foo", nil))
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 4*limit, httpmock.GetTotalCallCount())
+	assert.LessOrEqual(t, peak, limit)
+	assert.Positive(t, peak)
+	assert.Equal(t, 4*limit, c.RequestsInWindow())
+}
+
+func TestClient_SendRespectsContext(t *testing.T) {
+	c := NewClient("").WithMaxConcurrency(1).(*client)
+	c.slots <- struct{}{} // occupy the only slot
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := c.send(ctx, http.MethodGet, "/foo", nil, nil, "", nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestClient_WithoutRateLimitTracking(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s%s", apiEndpoint, "/budgets/aa248caa/accounts"),
+		httpmock.NewStringResponder(http.StatusOK, `{"foo":"bar"}`),
+	)
+
+	c := NewClient("").WithoutRateLimitTracking()
+
+	for i := 0; i < 3; i++ {
+		err := c.(*client).GET("/budgets/aa248caa/accounts", nil)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, 3, httpmock.GetTotalCallCount())
+	assert.Equal(t, 0, c.RequestsInWindow())
+	assert.Equal(t, api.RateLimitTrackingDisabled, c.RequestsRemaining())
+	assert.False(t, c.IsAtLimit())
+}
+
+func TestClient_WithRateLimit(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s%s", apiEndpoint, "/budgets/aa248caa/accounts"),
+		httpmock.NewStringResponder(http.StatusOK, `{"foo":"bar"}`),
+	)
+
+	c := NewClient("")
+	limit, window := c.RateLimitConfig()
+	assert.Equal(t, 200, limit)
+	assert.Equal(t, time.Hour, window)
+
+	c = c.WithRateLimit(5, time.Minute)
+	limit, window = c.RateLimitConfig()
+	assert.Equal(t, 5, limit)
+	assert.Equal(t, time.Minute, window)
+	assert.Equal(t, 5, c.RequestsRemaining())
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, c.(*client).GET("/budgets/aa248caa/accounts", nil))
+	}
+	assert.Equal(t, 0, c.RequestsRemaining())
+	assert.True(t, c.IsAtLimit())
+
+	limit, _ = c.WithoutRateLimitTracking().RateLimitConfig()
+	assert.Equal(t, api.RateLimitTrackingDisabled, limit)
+}
+
+func TestClient_WithWriteCost(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	url := fmt.Sprintf("%s%s", apiEndpoint, "/budgets/aa248caa/accounts")
+	httpmock.RegisterResponder(http.MethodGet, url, httpmock.NewStringResponder(http.StatusOK, `{"foo":"bar"}`))
+	httpmock.RegisterResponder(http.MethodPost, url, httpmock.NewStringResponder(http.StatusCreated, `{"foo":"bar"}`))
+
+	c := NewClient("").WithRateLimit(10, time.Minute)
+	assert.NoError(t, c.(*client).POST("/budgets/aa248caa/accounts", nil, nil))
+	assert.Equal(t, 1, c.RequestsInWindow())
+
+	c = c.WithWriteCost(3)
+	assert.NoError(t, c.(*client).GET("/budgets/aa248caa/accounts", nil))
+	assert.NoError(t, c.(*client).POST("/budgets/aa248caa/accounts", nil, nil))
+	assert.NoError(t, c.(*client).POST("/budgets/aa248caa/accounts", nil, nil))
+	assert.Equal(t, 8, c.RequestsInWindow())
+	assert.Equal(t, 2, c.RequestsRemaining())
+
+	// Writes can be left out of the count entirely
+	c = c.WithWriteCost(0)
+	assert.NoError(t, c.(*client).POST("/budgets/aa248caa/accounts", nil, nil))
+	assert.Equal(t, 8, c.RequestsInWindow())
+}
+
+// recordingCodec counts the calls made to the stdlib codec it wraps
+type recordingCodec struct {
+	mu         sync.Mutex
+	marshals   int
+	unmarshals int
+}
+
+func (c *recordingCodec) Marshal(v any) ([]byte, error) {
+	c.mu.Lock()
+	c.marshals++
+	c.mu.Unlock()
+	return api.StdlibCodec.Marshal(v)
+}
+
+func (c *recordingCodec) Unmarshal(data []byte, v any) error {
+	c.mu.Lock()
+	c.unmarshals++
+	c.mu.Unlock()
+	return api.StdlibCodec.Unmarshal(data, v)
+}
+
+func TestClient_WithCodec(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodPatch, fmt.Sprintf("%s%s", apiEndpoint, "/budgets/aa248caa/months/2024-01-01/categories/13419c12"),
+		func(req *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(req.Body)
+			assert.NoError(t, err)
+			assert.JSONEq(t, `{"category":{"budgeted":1000}}`, string(body))
+			return httpmock.NewStringResponse(http.StatusOK, `{"data":{"category":{"id":"13419c12","budgeted":1000}}}`), nil
+		},
+	)
+
+	codec := &recordingCodec{}
+	c := NewClient("").WithCodec(codec)
+	assert.Equal(t, codec, c.Codec())
+
+	date, err := api.DateFromString("2024-01-01")
+	assert.NoError(t, err)
+
+	cat, err := c.Category().UpdateCategoryForMonth("aa248caa", "13419c12",
+		date, category.PayloadMonthCategory{Budgeted: 1000})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), cat.Budgeted)
+
+	assert.Equal(t, 1, codec.marshals)
+	assert.Equal(t, 1, codec.unmarshals)
+}
+
+// rotatingTokenProvider hands out the next token of a list on every call,
+// like a provider reading a secret that was rotated in the meantime
+type rotatingTokenProvider struct {
+	*api.StaticTokenProvider
+	tokens []string
+	calls  int
+}
+
+func (p *rotatingTokenProvider) GetAccessToken(ctx context.Context) (string, error) {
+	token := p.tokens[min(p.calls, len(p.tokens)-1)]
+	p.calls++
+	return token, nil
+}
+
+func TestClient_RetriesWithFreshTokenOn401(t *testing.T) {
+	url := fmt.Sprintf("%s%s", apiEndpoint, "/budgets/aa248caa/accounts")
+	unauthorized := `{"error":{"id":"401","name":"unauthorized","detail":"Unauthorized"}}`
+
+	registerBody := func(valid, body string, authorizations *[]string) {
+		httpmock.RegisterResponder(http.MethodGet, url,
+			func(req *http.Request) (*http.Response, error) {
+				authorization := req.Header.Get("Authorization")
+				*authorizations = append(*authorizations, authorization)
+				if authorization != "Bearer "+valid {
+					return httpmock.NewStringResponse(http.StatusUnauthorized, body), nil
+				}
+				return httpmock.NewStringResponse(http.StatusOK, `{"data":{}}`), nil
+			},
+		)
+	}
+	register := func(valid string, authorizations *[]string) {
+		registerBody(valid, unauthorized, authorizations)
+	}
+
+	t.Run("rotated token", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var authorizations []string
+		register("new-token", &authorizations)
+
+		provider := &rotatingTokenProvider{
+			StaticTokenProvider: api.NewStaticTokenProvider(""),
+			tokens:              []string{"old-token", "new-token"},
+		}
+		c := NewClientWithTokenProvider(provider)
+
+		err := c.(*client).GET("/budgets/aa248caa/accounts", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Bearer old-token", "Bearer new-token"}, authorizations)
+		assert.Equal(t, 1, c.RequestsInWindow())
+	})
+
+	t.Run("rotated token with a 401 body without an error object", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var authorizations []string
+		registerBody("new-token", `{"message":"unauthorized"}`, &authorizations)
+
+		provider := &rotatingTokenProvider{
+			StaticTokenProvider: api.NewStaticTokenProvider(""),
+			tokens:              []string{"old-token", "new-token"},
+		}
+		c := NewClientWithTokenProvider(provider)
+
+		assert.NoError(t, c.(*client).GET("/budgets/aa248caa/accounts", nil))
+		assert.Equal(t, []string{"Bearer old-token", "Bearer new-token"}, authorizations)
+	})
+
+	t.Run("static token with a 401 body without an error object", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var authorizations []string
+		registerBody("new-token", `{"message":"unauthorized"}`, &authorizations)
+
+		err := NewClient("old-token").(*client).GET("/budgets/aa248caa/accounts", nil)
+		assert.ErrorIs(t, err, api.ErrTokenRejected)
+		assert.ErrorIs(t, err, api.ErrUnauthorized)
+	})
+
+	t.Run("static token", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var authorizations []string
+		register("new-token", &authorizations)
+
+		c := NewClient("old-token")
+
+		err := c.(*client).GET("/budgets/aa248caa/accounts", nil)
+		if assert.Error(t, err) {
+			var apiErr *api.Error
+			if assert.ErrorAs(t, err, &apiErr) {
+				assert.Equal(t, "401", apiErr.ID)
+			}
+		}
+		assert.Equal(t, []string{"Bearer old-token"}, authorizations)
+	})
+}
+
+func TestClient_StaticTokenRejected(t *testing.T) {
+	unauthorized := `{"error":{"id":"401","name":"unauthorized","detail":"Unauthorized"}}`
+
+	t.Run("personal access token", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s%s", apiEndpoint, "/user"),
+			httpmock.NewStringResponder(http.StatusUnauthorized, unauthorized))
+
+		_, err := NewClient("revoked-token").User().GetUser()
+		assert.EqualError(t, err, "api: personal access token rejected; verify it hasn't been revoked: "+
+			"api: error id=401 name=unauthorized detail=Unauthorized")
+		assert.ErrorIs(t, err, api.ErrTokenRejected)
+		assert.ErrorIs(t, err, api.ErrUnauthorized)
+
+		var apiErr *api.Error
+		if assert.ErrorAs(t, err, &apiErr) {
+			assert.True(t, apiErr.IsTokenInvalid())
+		}
+	})
+
+	t.Run("rotating token", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s%s", apiEndpoint, "/user"),
+			httpmock.NewStringResponder(http.StatusUnauthorized, unauthorized))
+
+		_, err := NewClientWithTokenProvider(&rotatingTokenProvider{
+			StaticTokenProvider: api.NewStaticTokenProvider(""),
+			tokens:              []string{"expired-token"},
+		}).User().GetUser()
+		assert.ErrorIs(t, err, api.ErrUnauthorized)
+		assert.NotErrorIs(t, err, api.ErrTokenRejected)
+	})
+}
+
+// recordingBackoff waits no time and records the attempts it is asked for
+type recordingBackoff struct {
+	attempts []int
+}
+
+func (b *recordingBackoff) NextDelay(attempt int) time.Duration {
+	b.attempts = append(b.attempts, attempt)
+	return time.Millisecond
+}
+
+func TestClient_WithRetry(t *testing.T) {
+	url := fmt.Sprintf("%s%s", apiEndpoint, "/foo")
+	rateLimited := `{"error":{"id":"429","name":"too_many_requests","detail":"Too many requests"}}`
+	serverError := `{"error":{"id":"500","name":"internal_server_error","detail":"Internal Server Error"}}`
+
+	// respond fails the first failures calls with body and status, then succeeds
+	respond := func(method string, failures, status int, body string) *int {
+		calls := 0
+		httpmock.RegisterResponder(method, url,
+			func(req *http.Request) (*http.Response, error) {
+				calls++
+				if calls <= failures {
+					return httpmock.NewStringResponse(status, body), nil
+				}
+				return httpmock.NewStringResponse(http.StatusOK, `{"foo":"bar"}`), nil
+			},
+		)
+		return &calls
+	}
+
+	t.Run("retries until success", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		calls := respond(http.MethodGet, 2, http.StatusTooManyRequests, rateLimited)
+		backoff := &recordingBackoff{}
+		c := NewClient("").WithRetry(3, backoff)
+
+		assert.NoError(t, c.(*client).GET("/foo", nil))
+		assert.Equal(t, 3, *calls)
+		assert.Equal(t, []int{1, 2}, backoff.attempts)
+		assert.Equal(t, 1, c.RequestsInWindow())
+	})
+
+	t.Run("retries a server error without an error object", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		calls := respond(http.MethodGet, 2, http.StatusInternalServerError, `{"message":"oops"}`)
+		c := NewClient("").WithRetry(3, &recordingBackoff{})
+
+		assert.NoError(t, c.(*client).GET("/foo", nil))
+		assert.Equal(t, 3, *calls)
+
+		var typedNil *api.Error
+		assert.True(t, c.(*client).shouldRetry(http.MethodGet, typedNil))
+		assert.False(t, c.(*client).shouldRetry(http.MethodPost, typedNil))
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		calls := respond(http.MethodGet, 5, http.StatusInternalServerError, serverError)
+		c := NewClient("").WithRetry(3, &recordingBackoff{})
+
+		err := c.(*client).GET("/foo", nil)
+		var apiErr *api.Error
+		if assert.ErrorAs(t, err, &apiErr) {
+			assert.Equal(t, "500", apiErr.ID)
+		}
+		assert.Equal(t, 3, *calls)
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		calls := respond(http.MethodGet, 5, http.StatusNotFound,
+			`{"error":{"id":"404.2","name":"resource_not_found","detail":"Resource not found"}}`)
+		c := NewClient("").WithRetry(3, &recordingBackoff{})
+
+		assert.Error(t, c.(*client).GET("/foo", nil))
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("POST is only retried when rate limited", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		calls := respond(http.MethodPost, 1, http.StatusInternalServerError, serverError)
+		c := NewClient("").WithRetry(3, &recordingBackoff{})
+		assert.Error(t, c.(*client).POST("/foo", nil, []byte(`{}`)))
+		assert.Equal(t, 1, *calls)
+
+		calls = respond(http.MethodPost, 1, http.StatusTooManyRequests, rateLimited)
+		assert.NoError(t, c.(*client).POST("/foo", nil, []byte(`{}`)))
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("cancelled during the backoff", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		calls := respond(http.MethodGet, 5, http.StatusTooManyRequests, rateLimited)
+		ctx, cancel := context.WithCancel(context.Background())
+		c := NewClient("").WithBaseContext(ctx).WithRetry(3, api.NewNoJitter(time.Hour, time.Hour))
+
+		time.AfterFunc(20*time.Millisecond, cancel)
+		start := time.Now()
+		err := c.(*client).GET("/foo", nil)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Less(t, time.Since(start), time.Minute)
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		calls := respond(http.MethodGet, 1, http.StatusTooManyRequests, rateLimited)
+		assert.Error(t, NewClient("").(*client).GET("/foo", nil))
+		assert.Equal(t, 1, *calls)
+	})
+}
+
+func TestClient_WithBaseContext(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	type ctxKey struct{}
+	var seen []any
+	httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s%s", apiEndpoint, "/foo"),
+		func(req *http.Request) (*http.Response, error) {
+			seen = append(seen, req.Context().Value(ctxKey{}))
+			return httpmock.NewStringResponse(http.StatusOK, `{}`), nil
+		},
+	)
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "request-scoped"))
+	c := NewClient("").WithBaseContext(ctx)
+
+	assert.NoError(t, c.(*client).GET("/foo", nil))
+	assert.Equal(t, []any{"request-scoped"}, seen)
+
+	cancel()
+	err := c.(*client).GET("/foo", nil)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Len(t, seen, 1)
+
+	// A nil context restores the default
+	assert.NoError(t, c.WithBaseContext(nil).(*client).GET("/foo", nil))
+	assert.Equal(t, []any{"request-scoped", nil}, seen)
+}
+
+func TestClient_GETStream(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s%s", apiEndpoint, "/foo"),
+		httpmock.NewStringResponder(http.StatusOK, `{"data":{"foo":"bar"}}`),
+	)
+
+	// Streams bypass request coalescing, which buffers the body
+	c := NewClient("").WithSingleFlight().(*client)
+
+	var body string
+	err := c.GETStream("/foo", func(r io.Reader) error {
+		data, err := io.ReadAll(r)
+		body = string(data)
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"data":{"foo":"bar"}}`, body)
+	assert.Equal(t, 199, c.RequestsRemaining())
+}
+
+func TestClient_Ping(t *testing.T) {
+	url := fmt.Sprintf("%s%s", apiEndpoint, "/user")
+
+	t.Run("success", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodGet, url,
+			httpmock.NewStringResponder(http.StatusOK, `{"data":{"user":{"id":"aa248caa-eed7-4575-a990-717386438d2c"}}}`))
+
+		assert.NoError(t, NewClient("token").Ping())
+		assert.Equal(t, 1, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodGet, url,
+			httpmock.NewStringResponder(http.StatusUnauthorized,
+				`{"error":{"id":"401","name":"unauthorized","detail":"Unauthorized"}}`))
+
+		err := NewClient("token").Ping()
+		assert.ErrorIs(t, err, api.ErrUnauthorized)
+		assert.ErrorIs(t, err, api.ErrTokenRejected)
+	})
+
+	t.Run("network error", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		errNetwork := errors.New("connection refused")
+		httpmock.RegisterResponder(http.MethodGet, url, httpmock.NewErrorResponder(errNetwork))
+
+		err := NewClient("token").Ping()
+		assert.ErrorIs(t, err, errNetwork)
+		assert.NotErrorIs(t, err, api.ErrUnauthorized)
+	})
+}
+
+func TestClient_WithRequestTransformer(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var sent string
+	httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s%s", apiEndpoint, "/budgets/aa248caa/transactions"),
+		func(req *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(req.Body)
+			assert.NoError(t, err)
+			sent = string(body)
+			return httpmock.NewStringResponse(http.StatusCreated,
+				`{"data":{"transaction_ids":["tx-1"],"transaction":{"id":"tx-1","date":"2024-01-15","amount":-9000}}}`), nil
+		},
+	)
+
+	var calls []string
+	c := NewClient("").WithRequestTransformer(func(method, url string, body []byte) ([]byte, error) {
+		calls = append(calls, method+" "+url)
+		return bytes.Replace(body, []byte(`"memo":"`), []byte(`"memo":"[app] `), 1), nil
+	})
+
+	date, err := api.DateFromString("2024-01-15")
+	assert.NoError(t, err)
+	memo, payee := "groceries", "Supermarket"
+	payload := transaction.PayloadTransaction{
+		AccountID: "acc-checking",
+		Date:      date,
+		Amount:    -9000,
+		Cleared:   transaction.ClearingStatusCleared,
+		PayeeName: &payee,
+		Memo:      &memo,
+	}
+	_, err = c.Transaction().CreateTransaction("aa248caa", payload)
+	assert.NoError(t, err)
+	assert.Contains(t, sent, `"memo":"[app] groceries"`)
+	assert.Equal(t, []string{"POST /budgets/aa248caa/transactions"}, calls)
+
+	// An error aborts the request before it is sent
+	errBlocked := errors.New("blocked")
+	c = c.WithRequestTransformer(func(method, url string, body []byte) ([]byte, error) {
+		return nil, errBlocked
+	})
+	_, err = c.Transaction().CreateTransaction("aa248caa", payload)
+	assert.ErrorIs(t, err, errBlocked)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestClient_WithErrorClassifier(t *testing.T) {
+	url := fmt.Sprintf("%s%s", apiEndpoint, "/foo")
+	conflict := `{"error":{"id":"409","name":"conflict","detail":"Conflict"}}`
+	serverError := `{"error":{"id":"500","name":"internal_server_error","detail":"Internal Server Error"}}`
+
+	respond := func(method string, failures, status int, body string) *int {
+		calls := 0
+		httpmock.RegisterResponder(method, url,
+			func(req *http.Request) (*http.Response, error) {
+				calls++
+				if calls <= failures {
+					return httpmock.NewStringResponse(status, body), nil
+				}
+				return httpmock.NewStringResponse(http.StatusOK, `{"data":{"foo":"bar"}}`), nil
+			},
+		)
+		return &calls
+	}
+
+	conflictsAreTransient := func(e *api.Error) api.ErrorClass {
+		if e.IsConflict() {
+			return api.ErrorClassTransient
+		}
+		return api.DefaultErrorClassifier(e)
+	}
+
+	t.Run("custom transient errors are retried", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		calls := respond(http.MethodPost, 2, http.StatusConflict, conflict)
+		backoff := &recordingBackoff{}
+		c := NewClient("").WithRetry(3, backoff).WithErrorClassifier(conflictsAreTransient)
+
+		assert.NoError(t, c.(*client).POST("/foo", nil, []byte(`{}`)))
+		assert.Equal(t, 3, *calls)
+		assert.Equal(t, []int{1, 2}, backoff.attempts)
+	})
+
+	t.Run("built-in policy does not retry conflicts", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		calls := respond(http.MethodPut, 2, http.StatusConflict, conflict)
+		c := NewClient("").WithRetry(3, &recordingBackoff{})
+
+		var apiErr *api.Error
+		assert.ErrorAs(t, c.(*client).PUT("/foo", nil, []byte(`{}`)), &apiErr)
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("custom fatal errors are not retried", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		calls := respond(http.MethodGet, 2, http.StatusInternalServerError, serverError)
+		c := NewClient("").WithRetry(3, &recordingBackoff{}).
+			WithErrorClassifier(func(*api.Error) api.ErrorClass { return api.ErrorClassFatal })
+
+		assert.Error(t, c.(*client).GET("/foo", nil))
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("ignorable errors are reported as success", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		calls := respond(http.MethodPost, 1, http.StatusConflict, conflict)
+		c := NewClient("").WithErrorClassifier(func(e *api.Error) api.ErrorClass {
+			if e.IsConflict() {
+				return api.ErrorClassIgnorable
+			}
+			return api.ErrorClassFatal
+		})
+
+		var response struct {
+			Data map[string]string `json:"data"`
+		}
+		assert.NoError(t, c.(*client).POST("/foo", &response, []byte(`{}`)))
+		assert.Nil(t, response.Data)
+		assert.Equal(t, 1, *calls)
+
+		// A nil classifier restores the built-in policy
+		calls = respond(http.MethodPost, 1, http.StatusConflict, conflict)
+		assert.Error(t, c.WithErrorClassifier(nil).(*client).POST("/foo", nil, []byte(`{}`)))
+		assert.Equal(t, 1, *calls)
+	})
+}
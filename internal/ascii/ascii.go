@@ -0,0 +1,12 @@
+// Package ascii holds small ASCII text helpers shared by the api packages
+package ascii
+
+// IsDigits reports whether s holds only ASCII digits
+func IsDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,138 @@
+package ynab
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"sync"
+)
+
+// lastKnowledgeParam is the query parameter of delta requests
+const lastKnowledgeParam = "last_knowledge_of_server"
+
+// knowledgeCache remembers the server knowledge last returned by each
+// endpoint, keyed by the request path and its query without
+// last_knowledge_of_server, see knowledgeKey
+type knowledgeCache struct {
+	mu        sync.Mutex
+	knowledge map[string]uint64
+}
+
+// knowledgeKey returns the cache key of a request, so requests with
+// different filters such as since_date or type keep their own knowledge
+func knowledgeKey(path, query string) string {
+	values, err := neturl.ParseQuery(query)
+	if err != nil {
+		return path + "?" + query
+	}
+	values.Del(lastKnowledgeParam)
+	if len(values) == 0 {
+		return path
+	}
+	return path + "?" + values.Encode()
+}
+
+// get returns the server knowledge recorded for key
+func (k *knowledgeCache) get(key string) (uint64, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	knowledge, ok := k.knowledge[key]
+	return knowledge, ok
+}
+
+// record stores the server knowledge returned for key
+func (k *knowledgeCache) record(key string, knowledge uint64) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.knowledge == nil {
+		k.knowledge = map[string]uint64{}
+	}
+	k.knowledge[key] = knowledge
+}
+
+// reset forgets the server knowledge of every endpoint of a budget
+func (k *knowledgeCache) reset(budgetID string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	prefix := "/budgets/" + budgetID
+	for key := range k.knowledge {
+		path, _, _ := strings.Cut(key, "?")
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			delete(k.knowledge, key)
+		}
+	}
+}
+
+// WithKnowledgeCache makes the client remember the server_knowledge of
+// every GET response carrying one, and send it as last_knowledge_of_server
+// on the next request to the same endpoint with the same query, so repeated
+// calls only return what changed. A request already carrying
+// last_knowledge_of_server is sent unchanged, and so are the requests of the
+// methods returning entities without their server knowledge, such as
+// CountNeedingAttention, which always read in full. Use ResetKnowledge to
+// fetch a budget in full again.
+func (c *client) WithKnowledgeCache() ClientServicer {
+	if c.knowledge == nil {
+		c.knowledge = &knowledgeCache{}
+	}
+	return c
+}
+
+// ResetKnowledge forgets the server knowledge cached for the endpoints of
+// a budget, so their next requests return every entity again
+func (c *client) ResetKnowledge(budgetID string) {
+	if c.knowledge != nil {
+		c.knowledge.reset(budgetID)
+	}
+}
+
+// getWithKnowledge sends a GET request carrying the cached server
+// knowledge of its endpoint, recording the one it returns
+func (c *client) getWithKnowledge(url string, responseModel any) error {
+	path, query, _ := strings.Cut(url, "?")
+	key := knowledgeKey(path, query)
+	if !strings.Contains(query, lastKnowledgeParam+"=") {
+		if knowledge, ok := c.knowledge.get(key); ok {
+			param := fmt.Sprintf("%s=%d", lastKnowledgeParam, knowledge)
+			if query == "" {
+				query = param
+			} else {
+				query += "&" + param
+			}
+			url = path + "?" + query
+		}
+	}
+
+	var body json.RawMessage
+	if err := c.do(c.requestContext(), http.MethodGet, url, &body, nil, nil); err != nil {
+		return err
+	}
+
+	envelope := struct {
+		Data struct {
+			ServerKnowledge *uint64 `json:"server_knowledge"`
+		} `json:"data"`
+	}{}
+	if err := c.Codec().Unmarshal(body, &envelope); err == nil && envelope.Data.ServerKnowledge != nil {
+		c.knowledge.record(key, *envelope.Data.ServerKnowledge)
+	}
+
+	if responseModel == nil || len(body) == 0 {
+		return nil
+	}
+	if err := c.Codec().Unmarshal(body, responseModel); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// GETFull sends a GET request to the YNAB API unchanged, bypassing the
+// knowledge cache, so it always returns every entity of the endpoint
+func (c *client) GETFull(url string, responseModel any) error {
+	return c.do(c.requestContext(), http.MethodGet, url, responseModel, nil, nil)
+}
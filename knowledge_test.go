@@ -0,0 +1,161 @@
+package ynab
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/jarcoal/httpmock.v1"
+
+	"github.com/coltoneshaw/ynab.go/api"
+	"github.com/coltoneshaw/ynab.go/api/transaction"
+)
+
+func TestClient_WithKnowledgeCache(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var mu sync.Mutex
+	var queries []string
+	knowledge := 10
+	respond := func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		queries = append(queries, req.URL.Path+"?"+req.URL.RawQuery)
+		knowledge++
+		return httpmock.NewStringResponse(http.StatusOK,
+			`{"data":{"transactions":[],"accounts":[],"server_knowledge":`+strconv.Itoa(knowledge)+`}}`), nil
+	}
+	httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets/aa248caa/transactions", respond)
+	httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets/aa248caa/accounts", respond)
+	httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets/bb248caa/transactions", respond)
+
+	c := NewClient("").WithKnowledgeCache()
+	since, err := api.DateFromString("2024-01-01")
+	require.NoError(t, err)
+
+	// The first call is a full fetch, the second carries its knowledge
+	result, err := c.Transaction().GetTransactions("aa248caa", &transaction.Filter{Since: &since})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(11), result.ServerKnowledge)
+
+	result, err = c.Transaction().GetTransactions("aa248caa", &transaction.Filter{Since: &since})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(12), result.ServerKnowledge)
+
+	// Knowledge is tracked per endpoint, query and budget
+	_, err = c.Transaction().GetTransactions("aa248caa", nil)
+	require.NoError(t, err)
+	_, err = c.Account().GetAccounts("aa248caa", nil)
+	require.NoError(t, err)
+	_, err = c.Transaction().GetTransactions("bb248caa", nil)
+	require.NoError(t, err)
+
+	// An explicit knowledge wins
+	explicit := uint64(3)
+	_, err = c.Transaction().GetTransactions("aa248caa", &transaction.Filter{LastKnowledgeOfServer: &explicit})
+	require.NoError(t, err)
+
+	// Resetting a budget forces a full fetch of its endpoints only
+	c.ResetKnowledge("aa248caa")
+	_, err = c.Transaction().GetTransactions("aa248caa", nil)
+	require.NoError(t, err)
+	_, err = c.Transaction().GetTransactions("bb248caa", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"/v1/budgets/aa248caa/transactions?since_date=2024-01-01",
+		"/v1/budgets/aa248caa/transactions?since_date=2024-01-01&last_knowledge_of_server=11",
+		"/v1/budgets/aa248caa/transactions?",
+		"/v1/budgets/aa248caa/accounts?",
+		"/v1/budgets/bb248caa/transactions?",
+		"/v1/budgets/aa248caa/transactions?last_knowledge_of_server=3",
+		"/v1/budgets/aa248caa/transactions?",
+		"/v1/budgets/bb248caa/transactions?last_knowledge_of_server=15",
+	}, queries)
+}
+
+func TestClient_WithKnowledgeCache_Disabled(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var queries []string
+	httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets/aa248caa/transactions",
+		func(req *http.Request) (*http.Response, error) {
+			queries = append(queries, req.URL.RawQuery)
+			return httpmock.NewStringResponse(http.StatusOK, `{"data":{"transactions":[],"server_knowledge":11}}`), nil
+		})
+
+	c := NewClient("")
+	for i := 0; i < 2; i++ {
+		_, err := c.Transaction().GetTransactions("aa248caa", nil)
+		require.NoError(t, err)
+	}
+	c.ResetKnowledge("aa248caa")
+	assert.Equal(t, []string{"", ""}, queries)
+}
+
+func TestClient_WithKnowledgeCache_Concurrent(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets/aa248caa/transactions",
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewStringResponse(http.StatusOK, `{"data":{"transactions":[],"server_knowledge":11}}`), nil
+		})
+
+	c := NewClient("").WithKnowledgeCache()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.Transaction().GetTransactions("aa248caa", nil)
+			assert.NoError(t, err)
+			c.ResetKnowledge("aa248caa")
+		}()
+	}
+	wg.Wait()
+}
+
+func TestClient_WithKnowledgeCache_FullReads(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var queries []string
+	httpmock.RegisterResponder(http.MethodGet, "https://api.youneedabudget.com/v1/budgets/aa248caa/transactions",
+		func(req *http.Request) (*http.Response, error) {
+			queries = append(queries, req.URL.RawQuery)
+			return httpmock.NewStringResponse(http.StatusOK, `{"data":{"transactions":[
+				{"id":"tx-1","date":"2024-01-01","amount":-1000,"cleared":"uncleared","approved":false,"account_id":"acc-1"}
+			],"server_knowledge":11}}`), nil
+		})
+	httpmock.RegisterResponder(http.MethodPatch, "https://api.youneedabudget.com/v1/budgets/aa248caa/transactions",
+		httpmock.NewStringResponder(http.StatusOK, `{"data":{"transaction_ids":["tx-1"],"server_knowledge":12}}`))
+
+	c := NewClient("").WithKnowledgeCache()
+
+	// Cache the knowledge of the queries used by the methods below
+	_, err := c.Transaction().GetTransactions("aa248caa", nil)
+	require.NoError(t, err)
+	for _, status := range []transaction.Status{transaction.StatusUnapproved, transaction.StatusUncategorized} {
+		_, err = c.Transaction().GetTransactions("aa248caa", &transaction.Filter{Type: status.Pointer()})
+		require.NoError(t, err)
+	}
+	queries = nil
+
+	uncategorized, unapproved, err := c.Transaction().CountNeedingAttention("aa248caa")
+	require.NoError(t, err)
+	assert.Equal(t, 1, uncategorized)
+	assert.Equal(t, 1, unapproved)
+
+	summary, err := c.Transaction().ApproveTransactions("aa248caa", []string{"tx-1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tx-1"}, summary.TransactionIDs)
+	assert.Empty(t, summary.MissingIDs)
+
+	assert.Equal(t, []string{"type=uncategorized", "type=unapproved", "type=unapproved"}, queries)
+}
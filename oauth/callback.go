@@ -0,0 +1,118 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// callbackShutdownTimeout bounds how long ListenForCallback waits for the
+// callback page to be delivered before closing the server
+const callbackShutdownTimeout = 5 * time.Second
+
+// ListenForCallback starts an HTTP server on the host, port and path of
+// redirectURI and waits for the browser to be redirected there after the
+// user authorizes the application. The callback is parsed with
+// Config.ParseCallbackURL, the browser is shown a page saying the tab can
+// be closed, and the server is shut down. An authorization error reported
+// by YNAB is returned in the Error of the result, as with ParseCallbackURL.
+//
+// The redirect URI must be a plain http URL such as
+// http://localhost:8080/callback. Only the authorization code flow is
+// supported, as browsers never send the fragment of an implicit grant
+// redirect to the server. If ctx is done first its error is returned.
+func ListenForCallback(ctx context.Context, redirectURI string) (*CallbackResult, error) {
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redirect URI: %w", err)
+	}
+	if u.Scheme != "http" || u.Host == "" {
+		return nil, fmt.Errorf("redirect URI must be an http URL with a host: %q", redirectURI)
+	}
+
+	listener, err := net.Listen("tcp", u.Host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for callback: %w", err)
+	}
+
+	return serveCallback(ctx, listener, u.Path)
+}
+
+// serveCallback serves the callback path on listener until a callback is
+// received or ctx is done
+func serveCallback(ctx context.Context, listener net.Listener, path string) (*CallbackResult, error) {
+	if path == "" {
+		path = "/"
+	}
+
+	type callback struct {
+		result *CallbackResult
+		err    error
+	}
+	received := make(chan callback, 1)
+
+	config := &Config{}
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		result, err := config.ParseCallbackURL(r.URL.String())
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		switch {
+		case err != nil:
+			w.WriteHeader(http.StatusBadRequest)
+			writeCallbackPage(w, "Authorization failed", err.Error())
+		case result.Error != nil:
+			writeCallbackPage(w, "Authorization failed", result.Error.Error())
+		default:
+			writeCallbackPage(w, "Authorization complete", "You can close this tab and return to the application.")
+		}
+
+		select {
+		case received <- callback{result, err}:
+		default:
+		}
+	})
+
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), callbackShutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	select {
+	case cb := <-received:
+		return cb.result, cb.err
+	case err := <-serveErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil, fmt.Errorf("callback server closed")
+		}
+		return nil, fmt.Errorf("callback server failed: %w", err)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// writeCallbackPage writes the minimal HTML page shown after the redirect
+func writeCallbackPage(w http.ResponseWriter, title, message string) {
+	_, _ = fmt.Fprintf(w, `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>%[1]s</title></head>
+<body><h1>%[1]s</h1><p>%[2]s</p></body>
+</html>
+`, html.EscapeString(title), html.EscapeString(message))
+}
@@ -0,0 +1,99 @@
+package oauth
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// callbackClient bypasses http.DefaultTransport, which httpmock replaces
+var callbackClient = &http.Client{Transport: &http.Transport{}, Timeout: 5 * time.Second}
+
+func startCallbackServer(ctx context.Context, t *testing.T) (string, <-chan *CallbackResult, <-chan error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	results := make(chan *CallbackResult, 1)
+	errs := make(chan error, 1)
+	go func() {
+		result, err := serveCallback(ctx, listener, "/callback")
+		results <- result
+		errs <- err
+	}()
+
+	return "http://" + listener.Addr().String(), results, errs
+}
+
+func TestListenForCallback(t *testing.T) {
+	t.Run("authorization code", func(t *testing.T) {
+		baseURL, results, errs := startCallbackServer(context.Background(), t)
+
+		resp, err := callbackClient.Get(baseURL + "/callback?code=auth-code-123&state=state-456")
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, string(body), "You can close this tab")
+
+		require.NoError(t, <-errs)
+		result := <-results
+		require.NotNil(t, result)
+		assert.Equal(t, "auth-code-123", result.Code)
+		assert.Equal(t, "state-456", result.State)
+		assert.Nil(t, result.Error)
+	})
+
+	t.Run("authorization denied", func(t *testing.T) {
+		baseURL, results, errs := startCallbackServer(context.Background(), t)
+
+		resp, err := callbackClient.Get(baseURL + "/callback?error=access_denied&error_description=User+denied&state=state-456")
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		require.NoError(t, err)
+		assert.Contains(t, string(body), "Authorization failed")
+
+		require.NoError(t, <-errs)
+		result := <-results
+		require.NotNil(t, result.Error)
+		assert.Equal(t, "access_denied", result.Error.ErrorCode)
+		assert.Equal(t, "state-456", result.State)
+	})
+
+	t.Run("other paths are not handled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		baseURL, _, errs := startCallbackServer(ctx, t)
+
+		resp, err := callbackClient.Get(baseURL + "/favicon.ico")
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		cancel()
+		assert.ErrorIs(t, <-errs, context.Canceled)
+	})
+
+	t.Run("context cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		result, err := ListenForCallback(ctx, "http://127.0.0.1:0/callback")
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("invalid redirect URI", func(t *testing.T) {
+		for _, uri := range []string{"https://localhost:8080/callback", "/callback", "://bad"} {
+			_, err := ListenForCallback(context.Background(), uri)
+			assert.Error(t, err, uri)
+		}
+	})
+}
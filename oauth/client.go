@@ -75,19 +75,73 @@ func NewOAuthClientFromStorage(config *Config, storage TokenStorage) (*OAuthClie
 	return NewOAuthClient(config, tokenManager), nil
 }
 
-// WithHTTPClient sets a custom HTTP client
+// WithHTTPClient sets the HTTP client used for API requests. Token
+// exchanges and refreshes keep using the client of the token manager; use
+// WithTokenHTTPClient to configure it.
 func (c *OAuthClient) WithHTTPClient(httpClient *http.Client) api.HTTPClientConfigurer {
 	c.httpClient = c.httpClient.WithHTTPClient(httpClient)
+	return c
+}
+
+// WithTokenHTTPClient sets the HTTP client used by the token manager for
+// token exchanges and refreshes, leaving the client used for API requests
+// untouched
+func (c *OAuthClient) WithTokenHTTPClient(httpClient *http.Client) *OAuthClient {
 	c.tokenManager.WithHTTPClient(httpClient)
 	return c
 }
 
+// WithTimeout sets the request timeout for API requests
+func (c *OAuthClient) WithTimeout(timeout time.Duration) api.HTTPClientConfigurer {
+	c.httpClient = c.httpClient.WithTimeout(timeout)
+	return c
+}
+
+// WithObserver sets an observer notified after every API request
+func (c *OAuthClient) WithObserver(observer api.Observer) *OAuthClient {
+	c.httpClient = c.httpClient.WithObserver(observer)
+	return c
+}
+
+// WithStrictEnvelope makes successful responses without a data key fail
+// with api.ErrMissingEnvelope
+func (c *OAuthClient) WithStrictEnvelope() *OAuthClient {
+	c.httpClient = c.httpClient.WithStrictEnvelope()
+	return c
+}
+
+// WithCodec sets the codec used to encode request bodies and decode
+// response bodies. A nil codec restores api.StdlibCodec.
+func (c *OAuthClient) WithCodec(codec api.Codec) *OAuthClient {
+	c.httpClient = c.httpClient.WithCodec(codec)
+	return c
+}
+
+// Codec returns the codec used to encode request bodies and decode
+// response bodies
+func (c *OAuthClient) Codec() api.Codec {
+	return c.httpClient.Codec()
+}
+
+// WithoutRateLimitTracking stops recording requests for rate limiting;
+// RequestsRemaining then returns api.RateLimitTrackingDisabled
+func (c *OAuthClient) WithoutRateLimitTracking() *OAuthClient {
+	c.rateLimiter = api.NewDisabledRateLimitTracker()
+	return c
+}
+
 // WithTokenRefreshCallback sets a callback for token refresh events
 func (c *OAuthClient) WithTokenRefreshCallback(callback func(*Token)) *OAuthClient {
 	c.tokenManager.WithTokenRefreshCallback(callback)
 	return c
 }
 
+// WithTokenRefreshErrorCallback sets a callback for failed token refreshes
+func (c *OAuthClient) WithTokenRefreshErrorCallback(callback func(error)) *OAuthClient {
+	c.tokenManager.WithTokenRefreshErrorCallback(callback)
+	return c
+}
+
 // Config returns the OAuth configuration
 func (c *OAuthClient) Config() *Config {
 	return c.config
@@ -103,6 +157,11 @@ func (c *OAuthClient) IsAuthenticated() bool {
 	return c.tokenManager.IsAuthenticated()
 }
 
+// CanWrite returns false if the client's token is limited to the read-only scope
+func (c *OAuthClient) CanWrite() bool {
+	return c.tokenManager.CanWrite()
+}
+
 // GetToken returns the current token
 func (c *OAuthClient) GetToken(ctx context.Context) (*Token, error) {
 	return c.tokenManager.GetToken(ctx)
@@ -182,62 +241,92 @@ func (c *OAuthClient) IsAtLimit() bool {
 	return c.rateLimiter.IsAtLimit()
 }
 
+// WithRateLimit replaces the default tracker of 200 requests per hour
+// with one allowing limit requests per rolling window. Requests recorded
+// so far are forgotten.
+func (c *OAuthClient) WithRateLimit(limit int, window time.Duration) *OAuthClient {
+	c.rateLimiter = api.NewRateLimitTracker(limit, window)
+	return c
+}
+
+// RateLimitConfig returns the number of requests allowed per rolling
+// window by the rate limit tracker. The limit is
+// api.RateLimitTrackingDisabled when tracking is disabled.
+func (c *OAuthClient) RateLimitConfig() (limit int, window time.Duration) {
+	if c.rateLimiter.IsDisabled() {
+		return api.RateLimitTrackingDisabled, c.rateLimiter.GetWindow()
+	}
+	return c.rateLimiter.GetLimit(), c.rateLimiter.GetWindow()
+}
+
 // HTTP methods (implementing api.ClientReaderWriter interface)
 
 // GET sends a GET request to the YNAB API
 func (c *OAuthClient) GET(url string, responseModel any) error {
-	return c.do(context.Background(), http.MethodGet, url, responseModel, nil)
+	return c.do(context.Background(), http.MethodGet, url, responseModel, nil, nil)
 }
 
 // POST sends a POST request to the YNAB API
 func (c *OAuthClient) POST(url string, responseModel any, requestBody []byte) error {
-	return c.do(context.Background(), http.MethodPost, url, responseModel, requestBody)
+	return c.do(context.Background(), http.MethodPost, url, responseModel, requestBody, nil)
+}
+
+// GETStream sends a GET request and passes the response body to fn as it
+// is read
+func (c *OAuthClient) GETStream(url string, fn api.StreamFunc) error {
+	return c.do(context.Background(), http.MethodGet, url, fn, nil, nil)
+}
+
+// POSTWithHeader sends a POST request with extra headers
+func (c *OAuthClient) POSTWithHeader(url string, responseModel any, requestBody []byte, header http.Header) error {
+	return c.do(context.Background(), http.MethodPost, url, responseModel, requestBody, header)
 }
 
 // PUT sends a PUT request to the YNAB API
 func (c *OAuthClient) PUT(url string, responseModel any, requestBody []byte) error {
-	return c.do(context.Background(), http.MethodPut, url, responseModel, requestBody)
+	return c.do(context.Background(), http.MethodPut, url, responseModel, requestBody, nil)
 }
 
 // PATCH sends a PATCH request to the YNAB API
 func (c *OAuthClient) PATCH(url string, responseModel any, requestBody []byte) error {
-	return c.do(context.Background(), http.MethodPatch, url, responseModel, requestBody)
+	return c.do(context.Background(), http.MethodPatch, url, responseModel, requestBody, nil)
 }
 
 // DELETE sends a DELETE request to the YNAB API
 func (c *OAuthClient) DELETE(url string, responseModel any) error {
-	return c.do(context.Background(), http.MethodDelete, url, responseModel, nil)
+	return c.do(context.Background(), http.MethodDelete, url, responseModel, nil, nil)
 }
 
 // Context-aware HTTP methods
 
 // GETWithContext sends a GET request with context
 func (c *OAuthClient) GETWithContext(ctx context.Context, url string, responseModel any) error {
-	return c.do(ctx, http.MethodGet, url, responseModel, nil)
+	return c.do(ctx, http.MethodGet, url, responseModel, nil, nil)
 }
 
 // POSTWithContext sends a POST request with context
 func (c *OAuthClient) POSTWithContext(ctx context.Context, url string, responseModel any, requestBody []byte) error {
-	return c.do(ctx, http.MethodPost, url, responseModel, requestBody)
+	return c.do(ctx, http.MethodPost, url, responseModel, requestBody, nil)
 }
 
 // PUTWithContext sends a PUT request with context
 func (c *OAuthClient) PUTWithContext(ctx context.Context, url string, responseModel any, requestBody []byte) error {
-	return c.do(ctx, http.MethodPut, url, responseModel, requestBody)
+	return c.do(ctx, http.MethodPut, url, responseModel, requestBody, nil)
 }
 
 // PATCHWithContext sends a PATCH request with context
 func (c *OAuthClient) PATCHWithContext(ctx context.Context, url string, responseModel any, requestBody []byte) error {
-	return c.do(ctx, http.MethodPatch, url, responseModel, requestBody)
+	return c.do(ctx, http.MethodPatch, url, responseModel, requestBody, nil)
 }
 
 // DELETEWithContext sends a DELETE request with context
 func (c *OAuthClient) DELETEWithContext(ctx context.Context, url string, responseModel any) error {
-	return c.do(ctx, http.MethodDelete, url, responseModel, nil)
+	return c.do(ctx, http.MethodDelete, url, responseModel, nil, nil)
 }
 
 // do sends a request to the YNAB API with OAuth authentication
-func (c *OAuthClient) do(ctx context.Context, method, url string, responseModel any, requestBody []byte) error {
+func (c *OAuthClient) do(ctx context.Context, method, url string, responseModel any, requestBody []byte,
+	header http.Header) error {
 	// Get access token
 	accessToken, err := c.tokenManager.GetAccessToken(ctx)
 	if err != nil {
@@ -245,7 +334,7 @@ func (c *OAuthClient) do(ctx context.Context, method, url string, responseModel
 	}
 
 	// Try the request with current token
-	err = c.httpClient.DoRequestWithContext(ctx, method, url, responseModel, requestBody, accessToken)
+	err = c.httpClient.DoRequestWithHeader(ctx, method, url, responseModel, requestBody, accessToken, header)
 
 	// If we get an authentication error, try token refresh once
 	if err != nil {
@@ -254,7 +343,7 @@ func (c *OAuthClient) do(ctx context.Context, method, url string, responseModel
 			if _, refreshErr := c.tokenManager.RefreshToken(ctx); refreshErr == nil {
 				// Get new access token and retry
 				if newAccessToken, tokenErr := c.tokenManager.GetAccessToken(ctx); tokenErr == nil {
-					err = c.httpClient.DoRequestWithContext(ctx, method, url, responseModel, requestBody, newAccessToken)
+					err = c.httpClient.DoRequestWithHeader(ctx, method, url, responseModel, requestBody, newAccessToken, header)
 				}
 			}
 		}
@@ -272,11 +361,13 @@ func (c *OAuthClient) do(ctx context.Context, method, url string, responseModel
 
 // ClientBuilder helps build OAuth clients with fluent interface
 type ClientBuilder struct {
-	config               *Config
-	storage              TokenStorage
-	token                *Token
-	httpClient           *http.Client
-	tokenRefreshCallback func(*Token)
+	config                    *Config
+	storage                   TokenStorage
+	token                     *Token
+	httpClient                *http.Client
+	tokenHTTPClient           *http.Client
+	tokenRefreshCallback      func(*Token)
+	tokenRefreshErrorCallback func(error)
 }
 
 // NewClientBuilder creates a new client builder
@@ -316,18 +407,32 @@ func (b *ClientBuilder) WithToken(token *Token) *ClientBuilder {
 	return b
 }
 
-// WithHTTPClient sets a custom HTTP client
+// WithHTTPClient sets the HTTP client used for API requests, and for token
+// requests unless WithTokenHTTPClient is also used
 func (b *ClientBuilder) WithHTTPClient(httpClient *http.Client) *ClientBuilder {
 	b.httpClient = httpClient
 	return b
 }
 
+// WithTokenHTTPClient sets the HTTP client used only for token exchanges
+// and refreshes
+func (b *ClientBuilder) WithTokenHTTPClient(httpClient *http.Client) *ClientBuilder {
+	b.tokenHTTPClient = httpClient
+	return b
+}
+
 // WithTokenRefreshCallback sets a token refresh callback
 func (b *ClientBuilder) WithTokenRefreshCallback(callback func(*Token)) *ClientBuilder {
 	b.tokenRefreshCallback = callback
 	return b
 }
 
+// WithTokenRefreshErrorCallback sets a callback for failed token refreshes
+func (b *ClientBuilder) WithTokenRefreshErrorCallback(callback func(error)) *ClientBuilder {
+	b.tokenRefreshErrorCallback = callback
+	return b
+}
+
 // Build creates the OAuth client
 func (b *ClientBuilder) Build() (*OAuthClient, error) {
 	// Use memory storage if none specified
@@ -338,8 +443,10 @@ func (b *ClientBuilder) Build() (*OAuthClient, error) {
 	// Create token manager
 	tokenManager := NewTokenManager(b.config, b.storage)
 
-	// Set HTTP client if provided
-	if b.httpClient != nil {
+	// Set token HTTP client if provided, falling back to the API one
+	if b.tokenHTTPClient != nil {
+		tokenManager.WithHTTPClient(b.tokenHTTPClient)
+	} else if b.httpClient != nil {
 		tokenManager.WithHTTPClient(b.httpClient)
 	}
 
@@ -347,6 +454,9 @@ func (b *ClientBuilder) Build() (*OAuthClient, error) {
 	if b.tokenRefreshCallback != nil {
 		tokenManager.WithTokenRefreshCallback(b.tokenRefreshCallback)
 	}
+	if b.tokenRefreshErrorCallback != nil {
+		tokenManager.WithTokenRefreshErrorCallback(b.tokenRefreshErrorCallback)
+	}
 
 	// Create client
 	client := NewOAuthClient(b.config, tokenManager)
@@ -1,10 +1,15 @@
 package oauth_test
 
 import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/coltoneshaw/ynab.go/oauth"
 )
@@ -99,6 +104,25 @@ func TestOAuthClient_RateLimiting(t *testing.T) {
 	assert.Equal(t, time.Duration(0), client.TimeUntilReset())
 }
 
+func TestOAuthClient_WithRateLimit(t *testing.T) {
+	config := oauth.NewOAuthConfig(oauth.Config{
+		ClientID:     "test-client",
+		ClientSecret: "test-secret",
+		RedirectURI:  "https://example.com/callback",
+	})
+	client := oauth.NewOAuthClient(config, oauth.NewTokenManager(config, oauth.NewMemoryStorage()))
+
+	limit, window := client.RateLimitConfig()
+	assert.Equal(t, 200, limit)
+	assert.Equal(t, time.Hour, window)
+
+	client.WithRateLimit(50, 10*time.Minute)
+	limit, window = client.RateLimitConfig()
+	assert.Equal(t, 50, limit)
+	assert.Equal(t, 10*time.Minute, window)
+	assert.Equal(t, 50, client.RequestsRemaining())
+}
+
 func TestNewTokenManager(t *testing.T) {
 	config := oauth.NewOAuthConfig(oauth.Config{
 		ClientID:     "test-client",
@@ -170,3 +194,70 @@ func TestClientBuilder_WithFileStorage(t *testing.T) {
 	assert.NotNil(t, client)
 	assert.Equal(t, config, client.Config())
 }
+
+// countingTransport answers every request with body and counts them
+type countingTransport struct {
+	body  string
+	calls []string
+}
+
+func (ct *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ct.calls = append(ct.calls, req.URL.String())
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(ct.body)),
+		Request:    req,
+	}, nil
+}
+
+func TestOAuthClient_WithTokenHTTPClient(t *testing.T) {
+	config := oauth.NewOAuthConfig(oauth.Config{
+		ClientID:     "test-client",
+		ClientSecret: "test-secret",
+		RedirectURI:  "https://example.com/callback",
+	})
+	token := &oauth.Token{
+		AccessToken:  "test-token",
+		RefreshToken: "test-refresh",
+		TokenType:    "Bearer",
+	}
+	token.SetExpirationAt(3600, time.Now())
+
+	apiTransport := &countingTransport{body: `{"data":{"user":{"id":"aa248caa-eed7-4575-a990-717386438d2c"}}}`}
+	tokenTransport := &countingTransport{body: `{"access_token":"new-token","refresh_token":"new-refresh","token_type":"Bearer","expires_in":7200}`}
+
+	assertIndependent := func(t *testing.T, client *oauth.OAuthClient) {
+		apiTransport.calls, tokenTransport.calls = nil, nil
+
+		_, err := client.User().GetUser()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://api.youneedabudget.com/v1/user"}, apiTransport.calls)
+		assert.Empty(t, tokenTransport.calls)
+
+		_, err = client.TokenManager().RefreshToken(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []string{oauth.TokenURL}, tokenTransport.calls)
+		assert.Len(t, apiTransport.calls, 1)
+	}
+
+	t.Run("client", func(t *testing.T) {
+		client, err := oauth.NewOAuthClientFromToken(config, token)
+		require.NoError(t, err)
+		client.WithHTTPClient(&http.Client{Transport: apiTransport})
+		client.WithTokenHTTPClient(&http.Client{Transport: tokenTransport})
+
+		assertIndependent(t, client)
+	})
+
+	t.Run("builder", func(t *testing.T) {
+		client, err := oauth.NewClientBuilder(config).
+			WithToken(token).
+			WithHTTPClient(&http.Client{Transport: apiTransport}).
+			WithTokenHTTPClient(&http.Client{Transport: tokenTransport}).
+			Build()
+		require.NoError(t, err)
+
+		assertIndependent(t, client)
+	})
+}
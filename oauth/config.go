@@ -5,8 +5,13 @@ import (
 	"encoding/hex"
 	"fmt"
 	"net/url"
+	"strconv"
 )
 
+// defaultExpiresIn the lifetime in seconds assumed for a token when YNAB
+// does not report one, as its tokens last two hours
+const defaultExpiresIn = 7200
+
 // Config holds OAuth 2.0 configuration for YNAB
 type Config struct {
 	// ClientID is the OAuth application's client identifier
@@ -75,6 +80,20 @@ func (c *Config) ImplicitGrantURL(state string) string {
 	return c.buildAuthorizeURL(ResponseTypeToken, state)
 }
 
+// AuthCodeURLWithParams generates the authorization URL for the
+// authorization code flow with additional query parameters, e.g. prompt.
+// Extra parameters never override client_id, redirect_uri or response_type.
+func (c *Config) AuthCodeURLWithParams(state string, extra url.Values) string {
+	return c.buildAuthorizeURLWithParams(ResponseTypeCode, state, extra)
+}
+
+// ImplicitGrantURLWithParams generates the authorization URL for the
+// implicit grant flow with additional query parameters. Extra parameters
+// never override client_id, redirect_uri or response_type.
+func (c *Config) ImplicitGrantURLWithParams(state string, extra url.Values) string {
+	return c.buildAuthorizeURLWithParams(ResponseTypeToken, state, extra)
+}
+
 // GenerateState generates a secure random state parameter for CSRF protection
 func (c *Config) GenerateState() (string, error) {
 	bytes := make([]byte, 16)
@@ -96,7 +115,18 @@ func (c *Config) ValidateState(expectedState, actualState string) bool {
 
 // buildAuthorizeURL constructs the authorization URL
 func (c *Config) buildAuthorizeURL(responseType ResponseType, state string) string {
+	return c.buildAuthorizeURLWithParams(responseType, state, nil)
+}
+
+// buildAuthorizeURLWithParams constructs the authorization URL, merging
+// extra into the standard parameters. The standard parameters are set
+// last so extra can never clobber them.
+func (c *Config) buildAuthorizeURLWithParams(responseType ResponseType, state string, extra url.Values) string {
 	params := url.Values{}
+	for key, values := range extra {
+		params[key] = append([]string(nil), values...)
+	}
+
 	params.Set("client_id", c.ClientID)
 	params.Set("redirect_uri", c.RedirectURI)
 	params.Set("response_type", string(responseType))
@@ -185,11 +215,14 @@ func (c *Config) ParseCallbackURL(callbackURL string) (*CallbackResult, error) {
 			result.TokenType = fragmentParams.Get("token_type")
 			result.Scope = fragmentParams.Get("scope")
 
-			// Parse expires_in if present
+			// Parse expires_in, assuming the default lifetime when absent
+			result.ExpiresIn = defaultExpiresIn
 			if expiresIn := fragmentParams.Get("expires_in"); expiresIn != "" {
-				if seconds, err := parseExpiresIn(expiresIn); err == nil {
-					result.ExpiresIn = seconds
+				seconds, err := parseExpiresIn(expiresIn)
+				if err != nil {
+					return nil, err
 				}
+				result.ExpiresIn = seconds
 			}
 
 			// Override state from fragment if present
@@ -239,16 +272,12 @@ func (cr *CallbackResult) ToToken() *Token {
 	return token
 }
 
-// parseExpiresIn converts expires_in string to int64
+// parseExpiresIn converts the expires_in fragment parameter to a number of
+// seconds, rejecting values that are not a non-negative integer
 func parseExpiresIn(expiresIn string) (int64, error) {
-	// This would typically use strconv.ParseInt but keeping it simple
-	switch expiresIn {
-	case "7200": // 2 hours (YNAB default)
-		return 7200, nil
-	case "3600": // 1 hour
-		return 3600, nil
-	default:
-		// For now, default to 2 hours if we can't parse
-		return 7200, nil
+	seconds, err := strconv.ParseInt(expiresIn, 10, 64)
+	if err != nil || seconds < 0 {
+		return 0, fmt.Errorf("invalid expires_in %q in callback URL", expiresIn)
 	}
+	return seconds, nil
 }
@@ -107,6 +107,54 @@ func TestConfig_ImplicitGrantURL(t *testing.T) {
 	assert.Equal(t, "token", params.Get("response_type"))
 }
 
+func TestConfig_AuthCodeURLWithParams(t *testing.T) {
+	config := NewOAuthConfig(Config{
+		ClientID:     "test-client",
+		ClientSecret: "test-secret",
+		RedirectURI:  "https://example.com/callback",
+	})
+
+	extra := url.Values{
+		"prompt":        {"login"},
+		"client_id":     {"evil-client"},
+		"redirect_uri":  {"https://evil.example.com"},
+		"response_type": {"token"},
+	}
+	authURL := config.AuthCodeURLWithParams("test-state", extra)
+
+	parsedURL, err := url.Parse(authURL)
+	require.NoError(t, err)
+
+	params := parsedURL.Query()
+	assert.Equal(t, "login", params.Get("prompt"))
+	assert.Equal(t, []string{"test-client"}, params["client_id"])
+	assert.Equal(t, []string{"https://example.com/callback"}, params["redirect_uri"])
+	assert.Equal(t, []string{"code"}, params["response_type"])
+	assert.Equal(t, "test-state", params.Get("state"))
+	assert.Equal(t, []string{"evil-client"}, extra["client_id"], "extra must not be modified")
+}
+
+func TestConfig_ImplicitGrantURLWithParams(t *testing.T) {
+	config := NewOAuthConfig(Config{
+		ClientID:     "test-client",
+		ClientSecret: "test-secret",
+		RedirectURI:  "https://example.com/callback",
+	})
+
+	authURL := config.ImplicitGrantURLWithParams("test-state", url.Values{
+		"prompt":        {"login"},
+		"response_type": {"code"},
+	})
+
+	parsedURL, err := url.Parse(authURL)
+	require.NoError(t, err)
+
+	params := parsedURL.Query()
+	assert.Equal(t, "login", params.Get("prompt"))
+	assert.Equal(t, []string{"token"}, params["response_type"])
+	assert.Equal(t, "test-client", params.Get("client_id"))
+}
+
 func TestConfig_GenerateState(t *testing.T) {
 	config := NewOAuthConfig(Config{
 		ClientID:     "client-id",
@@ -275,6 +323,39 @@ func TestConfig_ParseCallbackURL(t *testing.T) {
 				assert.Equal(t, "test-state", result.State)
 			},
 		},
+		{
+			name:        "Implicit grant callback with short lifetime",
+			callbackURL: "https://example.com/callback#access_token=token123&token_type=Bearer&expires_in=1800",
+			checkResult: func(t *testing.T, result *CallbackResult, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1800), result.ExpiresIn)
+			},
+		},
+		{
+			name:        "Implicit grant callback with long lifetime",
+			callbackURL: "https://example.com/callback#access_token=token123&token_type=Bearer&expires_in=604800",
+			checkResult: func(t *testing.T, result *CallbackResult, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(604800), result.ExpiresIn)
+			},
+		},
+		{
+			name:        "Implicit grant callback without expires_in",
+			callbackURL: "https://example.com/callback#access_token=token123&token_type=Bearer",
+			checkResult: func(t *testing.T, result *CallbackResult, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(7200), result.ExpiresIn)
+			},
+		},
+		{
+			name:        "Implicit grant callback with invalid expires_in",
+			callbackURL: "https://example.com/callback#access_token=token123&token_type=Bearer&expires_in=soon",
+			expectError: true,
+			checkResult: func(t *testing.T, result *CallbackResult, err error) {
+				assert.Nil(t, result)
+				assert.ErrorContains(t, err, `invalid expires_in "soon"`)
+			},
+		},
 		{
 			name:        "Invalid URL",
 			callbackURL: ":",
@@ -4,8 +4,12 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 )
 
+// defaultPollInterval is used by PollForToken when no interval is given
+const defaultPollInterval = 2 * time.Second
+
 // Flow represents an OAuth flow implementation
 type Flow interface {
 	// GetAuthorizationURL returns the URL users should visit to authorize the application
@@ -254,6 +258,38 @@ func (fm *FlowManager) CompleteAuthorizationCodeFlow(ctx context.Context, callba
 	return fm.authCodeFlow.HandleCallbackWithContext(ctx, callbackURL, expectedState)
 }
 
+// PollForToken completes the authorization code flow on a headless machine
+// where the redirect cannot be served directly. getCallbackURL is called
+// every interval until it returns a non-empty callback URL, such as one the
+// operator pasted into a prompt or one captured by a separate web server,
+// and the code in it is then exchanged for a token. An error from
+// getCallbackURL stops polling and is returned, as is the error of ctx if it
+// is done first. A non-positive interval polls every two seconds.
+func (fm *FlowManager) PollForToken(ctx context.Context, state string, getCallbackURL func() (string, error), interval time.Duration) (*Token, error) {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		callbackURL, err := getCallbackURL()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get callback URL: %w", err)
+		}
+		if callbackURL != "" {
+			return fm.CompleteAuthorizationCodeFlow(ctx, callbackURL, state)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
 // CompleteImplicitGrantFlow completes the implicit grant flow
 func (fm *FlowManager) CompleteImplicitGrantFlow(callbackURL, expectedState string) (*Token, error) {
 	return fm.implicitFlow.HandleCallback(callbackURL, expectedState)
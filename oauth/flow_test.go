@@ -2,9 +2,11 @@ package oauth
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -299,6 +301,69 @@ func TestFlowManager_CompleteAuthorizationCodeFlow(t *testing.T) {
 	assert.Equal(t, "access-token-123", token.AccessToken)
 }
 
+func TestFlowManager_PollForToken(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodPost, TokenURL,
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewStringResponse(200, `{
+				"access_token": "access-token-123",
+				"token_type": "Bearer"
+			}`), nil
+		},
+	)
+
+	config := NewOAuthConfig(Config{
+		ClientID:     "test-client",
+		ClientSecret: "test-secret",
+		RedirectURI:  "https://example.com/callback",
+	})
+	manager := NewFlowManager(config)
+
+	t.Run("returns the token once the callback arrives", func(t *testing.T) {
+		polls := 0
+		getCallbackURL := func() (string, error) {
+			polls++
+			if polls < 3 {
+				return "", nil
+			}
+			return "https://example.com/callback?code=auth-code&state=test-state", nil
+		}
+
+		token, err := manager.PollForToken(context.Background(), "test-state", getCallbackURL, time.Millisecond)
+		require.NoError(t, err)
+		assert.Equal(t, "access-token-123", token.AccessToken)
+		assert.Equal(t, 3, polls)
+	})
+
+	t.Run("rejects a mismatched state", func(t *testing.T) {
+		getCallbackURL := func() (string, error) {
+			return "https://example.com/callback?code=auth-code&state=other-state", nil
+		}
+
+		_, err := manager.PollForToken(context.Background(), "test-state", getCallbackURL, time.Millisecond)
+		assert.Error(t, err)
+	})
+
+	t.Run("stops on a callback error", func(t *testing.T) {
+		errAborted := errors.New("operator aborted")
+		getCallbackURL := func() (string, error) { return "", errAborted }
+
+		_, err := manager.PollForToken(context.Background(), "test-state", getCallbackURL, time.Millisecond)
+		assert.ErrorIs(t, err, errAborted)
+	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		getCallbackURL := func() (string, error) { return "", nil }
+
+		_, err := manager.PollForToken(ctx, "test-state", getCallbackURL, time.Millisecond)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
 func TestFlowManager_CompleteImplicitGrantFlow(t *testing.T) {
 	config := NewOAuthConfig(Config{
 		ClientID:     "test-client",
@@ -0,0 +1,17 @@
+module github.com/coltoneshaw/ynab.go/oauth/oauthx
+
+go 1.24
+
+require (
+	github.com/coltoneshaw/ynab.go v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.10.0
+	golang.org/x/oauth2 v0.30.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/coltoneshaw/ynab.go => ../..
@@ -0,0 +1,53 @@
+// Package oauthx converts the tokens of package oauth to the types of
+// golang.org/x/oauth2, for interoperability with libraries built on it.
+// It is a module of its own, apart from package oauth, so programs that
+// only use oauth do not depend on golang.org/x/oauth2.
+//
+// To share a refreshing token with an oauth2 based client, wrap the
+// token source of a TokenManager and cache its tokens:
+//
+//	source := oauth2.ReuseTokenSource(nil, oauthx.TokenSource(oauth.NewTokenSource(ctx, manager)))
+//	httpClient := oauth2.NewClient(ctx, source)
+package oauthx // import "github.com/coltoneshaw/ynab.go/oauth/oauthx"
+
+import (
+	"golang.org/x/oauth2"
+
+	"github.com/coltoneshaw/ynab.go/oauth"
+)
+
+// ToOAuth2Token converts t to an oauth2 token carrying the same access
+// token, refresh token, type and expiry, so its Valid method reflects the
+// expiry of t. A token without an expiry never expires, as in package
+// oauth. A nil t converts to nil.
+func ToOAuth2Token(t *oauth.Token) *oauth2.Token {
+	if t == nil {
+		return nil
+	}
+	return &oauth2.Token{
+		AccessToken:  t.AccessToken,
+		TokenType:    string(t.TokenType),
+		RefreshToken: t.RefreshToken,
+		Expiry:       t.ExpiresAt,
+		ExpiresIn:    t.ExpiresIn,
+	}
+}
+
+// TokenSource adapts ts to oauth2.TokenSource, converting every token it
+// returns with ToOAuth2Token
+func TokenSource(ts *oauth.TokenSource) oauth2.TokenSource {
+	return tokenSource{ts}
+}
+
+type tokenSource struct {
+	ts *oauth.TokenSource
+}
+
+// Token returns the current token of the wrapped source
+func (s tokenSource) Token() (*oauth2.Token, error) {
+	t, err := s.ts.Token()
+	if err != nil {
+		return nil, err
+	}
+	return ToOAuth2Token(t), nil
+}
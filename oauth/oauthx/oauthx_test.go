@@ -0,0 +1,60 @@
+package oauthx_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/coltoneshaw/ynab.go/oauth"
+	"github.com/coltoneshaw/ynab.go/oauth/oauthx"
+)
+
+func TestToOAuth2Token(t *testing.T) {
+	token := &oauth.Token{
+		AccessToken:  "access-token-123",
+		RefreshToken: "refresh-token-456",
+		TokenType:    oauth.TokenTypeBearer,
+	}
+	token.SetExpiration(7200)
+
+	converted := oauthx.ToOAuth2Token(token)
+	require.NotNil(t, converted)
+	assert.Equal(t, "access-token-123", converted.AccessToken)
+	assert.Equal(t, "refresh-token-456", converted.RefreshToken)
+	assert.Equal(t, "Bearer", converted.TokenType)
+	assert.Equal(t, token.ExpiresAt, converted.Expiry)
+	assert.Equal(t, int64(7200), converted.ExpiresIn)
+	assert.True(t, converted.Valid())
+
+	token.ExpiresAt = time.Now().Add(-time.Minute)
+	assert.False(t, oauthx.ToOAuth2Token(token).Valid())
+
+	// Tokens without an expiry never expire
+	token.ExpiresAt = time.Time{}
+	assert.True(t, oauthx.ToOAuth2Token(token).Valid())
+
+	assert.Nil(t, oauthx.ToOAuth2Token(nil))
+}
+
+func TestTokenSource(t *testing.T) {
+	config := oauth.NewOAuthConfig(oauth.Config{
+		ClientID:     "test-client",
+		ClientSecret: "test-secret",
+		RedirectURI:  "https://example.com/callback",
+	})
+	manager := oauth.NewTokenManager(config, oauth.NewMemoryStorage())
+
+	token := &oauth.Token{AccessToken: "access-token-123", TokenType: oauth.TokenTypeBearer}
+	token.SetExpiration(7200)
+	require.NoError(t, manager.SetToken(token))
+
+	source := oauth2.ReuseTokenSource(nil, oauthx.TokenSource(oauth.NewTokenSource(context.Background(), manager)))
+	converted, err := source.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "access-token-123", converted.AccessToken)
+	assert.True(t, converted.Valid())
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 )
 
 // TokenStorage defines the interface for token persistence
@@ -57,10 +58,25 @@ func (s *MemoryStorage) HasToken() bool {
 	return s.token != nil
 }
 
-// FileStorage implements file-based token storage
+// FileStorage implements file-based token storage. Tokens are written to a
+// temporary file renamed over the token file, so a crash or a concurrent
+// save never leaves a truncated token behind.
 type FileStorage struct {
+	// mu serializes writes to the token file
+	mu sync.Mutex
+
 	filePath string
 	fileMode os.FileMode
+
+	// write writes the token data to the temporary file, writeTokenData
+	// unless set otherwise, e.g. by tests simulating an interrupted write
+	write func(f *os.File, data []byte) error
+}
+
+// writeTokenData writes the token data to the temporary file
+func writeTokenData(f *os.File, data []byte) error {
+	_, err := f.Write(data)
+	return err
 }
 
 // NewFileStorage creates a new file-based storage
@@ -83,26 +99,61 @@ func (s *FileStorage) SaveToken(token *Token) error {
 		return fmt.Errorf("token cannot be nil")
 	}
 
-	// Ensure directory exists
-	dir := filepath.Dir(s.filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
 	// Serialize token
 	data, err := json.MarshalIndent(token, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal token: %w", err)
 	}
 
-	// Write to file with secure permissions
-	if err := os.WriteFile(s.filePath, data, s.fileMode); err != nil {
+	if err := s.writeAtomic(data); err != nil {
 		return fmt.Errorf("failed to write token file: %w", err)
 	}
 
 	return nil
 }
 
+// writeAtomic replaces the token file with data by writing a temporary
+// file in the same directory and renaming it over the token file
+func (s *FileStorage) writeAtomic(data []byte) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Ensure directory exists
+	dir := filepath.Dir(s.filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(s.filePath)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	if err := tmp.Chmod(s.fileMode); err != nil {
+		return err
+	}
+	write := s.write
+	if write == nil {
+		write = writeTokenData
+	}
+	if err := write(tmp, data); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.filePath)
+}
+
 // LoadToken loads the token from a file
 func (s *FileStorage) LoadToken() (*Token, error) {
 	// Check if file exists
@@ -127,6 +178,9 @@ func (s *FileStorage) LoadToken() (*Token, error) {
 
 // ClearToken removes the token file
 func (s *FileStorage) ClearToken() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if !s.HasToken() {
 		return nil // Already cleared
 	}
@@ -188,14 +242,8 @@ func (s *EncryptedFileStorage) SaveToken(token *Token) error {
 	// Encrypt data (simple XOR for demonstration - use proper encryption in production)
 	encrypted := s.encrypt(data)
 
-	// Ensure directory exists
-	dir := filepath.Dir(s.filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
 	// Write encrypted data to file
-	if err := os.WriteFile(s.filePath, encrypted, s.fileMode); err != nil {
+	if err := s.writeAtomic(encrypted); err != nil {
 		return fmt.Errorf("failed to write encrypted token file: %w", err)
 	}
 
@@ -1,8 +1,11 @@
 package oauth
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -107,6 +110,64 @@ func TestFileStorage_WithFileMode(t *testing.T) {
 	assert.Equal(t, os.FileMode(0644), fileInfo.Mode())
 }
 
+func TestFileStorage_InterruptedSave(t *testing.T) {
+	tempDir := t.TempDir()
+	filePath := filepath.Join(tempDir, "token.json")
+
+	storage := NewFileStorage(filePath)
+	require.NoError(t, storage.SaveToken(&Token{AccessToken: "previous"}))
+
+	// Write half of the new token, then fail as a crash would
+	storage.write = func(f *os.File, data []byte) error {
+		_, _ = f.Write(data[:len(data)/2])
+		return errors.New("disk full")
+	}
+
+	err := storage.SaveToken(&Token{AccessToken: "next"})
+	assert.ErrorContains(t, err, "disk full")
+
+	token, err := storage.LoadToken()
+	require.NoError(t, err)
+	assert.Equal(t, "previous", token.AccessToken)
+
+	// The temporary file is cleaned up
+	entries, err := os.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "token.json", entries[0].Name())
+}
+
+func TestFileStorage_ConcurrentSaves(t *testing.T) {
+	tempDir := t.TempDir()
+	filePath := filepath.Join(tempDir, "token.json")
+
+	storage := NewFileStorage(filePath).WithFileMode(0640)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, storage.SaveToken(&Token{AccessToken: fmt.Sprintf("token-%d", i)}))
+
+			// Every read sees a complete token
+			token, err := storage.LoadToken()
+			if assert.NoError(t, err) {
+				assert.Contains(t, token.AccessToken, "token-")
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	fileInfo, err := os.Stat(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), fileInfo.Mode())
+
+	entries, err := os.ReadDir(tempDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
 func TestFileStorage_DirectoryCreation(t *testing.T) {
 	tempDir := t.TempDir()
 	nestedPath := filepath.Join(tempDir, "nested", "dir", "token.json")
@@ -9,6 +9,9 @@ import (
 	"net/http"
 	"net/url"
 	"sync"
+	"time"
+
+	"github.com/coltoneshaw/ynab.go/api"
 )
 
 // TokenManager handles token refresh and management
@@ -16,6 +19,7 @@ type TokenManager struct {
 	config  *Config
 	client  *http.Client
 	storage TokenStorage
+	clock   api.Clock
 
 	// Token access mutex
 	mu    sync.RWMutex
@@ -23,14 +27,19 @@ type TokenManager struct {
 
 	// Callback for token refresh events
 	onTokenRefresh func(*Token)
+
+	// Callback for failed token refreshes
+	onTokenRefreshError func(error)
 }
 
-// NewTokenManager creates a new token manager
+// NewTokenManager creates a new token manager. Token requests use an HTTP
+// client with an api.DefaultTimeout timeout unless WithHTTPClient is used.
 func NewTokenManager(config *Config, storage TokenStorage) *TokenManager {
 	return &TokenManager{
 		config:  config,
-		client:  http.DefaultClient,
+		client:  &http.Client{Timeout: api.DefaultTimeout},
 		storage: storage,
+		clock:   api.SystemClock,
 	}
 }
 
@@ -40,12 +49,28 @@ func (tm *TokenManager) WithHTTPClient(client *http.Client) *TokenManager {
 	return tm
 }
 
+// WithClock sets the clock used to evaluate and compute token expiry
+func (tm *TokenManager) WithClock(clock api.Clock) *TokenManager {
+	tm.clock = clock
+	return tm
+}
+
 // WithTokenRefreshCallback sets a callback for token refresh events
 func (tm *TokenManager) WithTokenRefreshCallback(callback func(*Token)) *TokenManager {
 	tm.onTokenRefresh = callback
 	return tm
 }
 
+// WithTokenRefreshErrorCallback sets a callback called with the error of
+// every failed token refresh, whether attempted by GetToken or
+// RefreshToken, e.g. to prompt the user to authorize again when the
+// refresh token was revoked and the error matches ErrInvalidGrant. It is
+// not called when a refresh succeeds.
+func (tm *TokenManager) WithTokenRefreshErrorCallback(callback func(error)) *TokenManager {
+	tm.onTokenRefreshError = callback
+	return tm
+}
+
 // SetToken sets the current token
 func (tm *TokenManager) SetToken(token *Token) error {
 	tm.mu.Lock()
@@ -62,20 +87,7 @@ func (tm *TokenManager) SetToken(token *Token) error {
 
 // GetToken returns the current token, refreshing if necessary
 func (tm *TokenManager) GetToken(ctx context.Context) (*Token, error) {
-	tm.mu.RLock()
-	currentToken := tm.token
-	tm.mu.RUnlock()
-
-	// If no token is loaded, try to load from storage
-	if currentToken == nil && tm.storage != nil {
-		loadedToken, err := tm.storage.LoadToken()
-		if err == nil && loadedToken != nil {
-			tm.mu.Lock()
-			tm.token = loadedToken
-			currentToken = loadedToken
-			tm.mu.Unlock()
-		}
-	}
+	currentToken := tm.loadToken()
 
 	// If still no token, return error
 	if currentToken == nil {
@@ -83,16 +95,18 @@ func (tm *TokenManager) GetToken(ctx context.Context) (*Token, error) {
 	}
 
 	// If token is valid, return it
-	if currentToken.IsValid() {
+	if currentToken.IsValidAt(tm.clock.Now()) {
 		return currentToken, nil
 	}
 
-	// If token is expired but can't be refreshed, return error
+	// If token is expired but can't be refreshed, return error. It
+	// matches both ErrTokenExpired and ErrNoRefreshToken.
 	if !currentToken.CanRefresh() {
-		return nil, ErrTokenExpired
+		return nil, fmt.Errorf("%w: %w", ErrTokenExpired, ErrNoRefreshToken)
 	}
 
-	// Refresh the token
+	// Refresh the token. No lock is held during the request, so a slow
+	// token endpoint never blocks concurrent token reads.
 	refreshedToken, err := tm.refreshToken(ctx, currentToken)
 	if err != nil {
 		return nil, fmt.Errorf("failed to refresh token: %w", err)
@@ -111,6 +125,26 @@ func (tm *TokenManager) GetToken(ctx context.Context) (*Token, error) {
 	return refreshedToken, nil
 }
 
+// loadToken returns the current token, loading it from storage if none
+// is loaded yet
+func (tm *TokenManager) loadToken() *Token {
+	tm.mu.RLock()
+	currentToken := tm.token
+	tm.mu.RUnlock()
+
+	if currentToken == nil && tm.storage != nil {
+		loadedToken, err := tm.storage.LoadToken()
+		if err == nil && loadedToken != nil {
+			tm.mu.Lock()
+			tm.token = loadedToken
+			currentToken = loadedToken
+			tm.mu.Unlock()
+		}
+	}
+
+	return currentToken
+}
+
 // ExchangeCode exchanges an authorization code for an access token
 func (tm *TokenManager) ExchangeCode(ctx context.Context, code string) (*Token, error) {
 	if err := tm.config.Validate(); err != nil {
@@ -139,7 +173,7 @@ func (tm *TokenManager) RefreshToken(ctx context.Context) (*Token, error) {
 	}
 
 	if !currentToken.CanRefresh() {
-		return nil, fmt.Errorf("token cannot be refreshed")
+		return nil, fmt.Errorf("token cannot be refreshed: %w", ErrNoRefreshToken)
 	}
 
 	refreshedToken, err := tm.refreshToken(ctx, currentToken)
@@ -163,7 +197,11 @@ func (tm *TokenManager) refreshToken(ctx context.Context, token *Token) (*Token,
 		RefreshToken: token.RefreshToken,
 	}
 
-	return tm.exchangeToken(ctx, tokenRequest)
+	refreshedToken, err := tm.exchangeToken(ctx, tokenRequest)
+	if err != nil && tm.onTokenRefreshError != nil {
+		tm.onTokenRefreshError(err)
+	}
+	return refreshedToken, err
 }
 
 // exchangeToken performs the token exchange with YNAB
@@ -208,6 +246,12 @@ func (tm *TokenManager) exchangeToken(ctx context.Context, tokenRequest *TokenRe
 	// Parse response
 	var tokenResponse TokenResponse
 	if err := json.Unmarshal(body, &tokenResponse); err != nil {
+		if resp.StatusCode >= 400 {
+			return nil, &ErrorResponse{
+				ErrorCode:  errorCodeForStatus(resp.StatusCode),
+				StatusCode: resp.StatusCode,
+			}
+		}
 		return nil, fmt.Errorf("failed to parse token response: %w", err)
 	}
 
@@ -216,6 +260,15 @@ func (tm *TokenManager) exchangeToken(ctx context.Context, tokenRequest *TokenRe
 		return nil, &ErrorResponse{
 			ErrorCode:        tokenResponse.Error,
 			ErrorDescription: tokenResponse.ErrorDescription,
+			StatusCode:       resp.StatusCode,
+		}
+	}
+
+	// An error status without an OAuth error body is still a failure
+	if resp.StatusCode >= 400 {
+		return nil, &ErrorResponse{
+			ErrorCode:  errorCodeForStatus(resp.StatusCode),
+			StatusCode: resp.StatusCode,
 		}
 	}
 
@@ -228,13 +281,30 @@ func (tm *TokenManager) exchangeToken(ctx context.Context, tokenRequest *TokenRe
 	token := tokenResponse.ToToken()
 
 	// Set default expiration if not provided (YNAB tokens typically last 2 hours)
-	if token.ExpiresIn == 0 {
-		token.SetExpiration(7200) // 2 hours
+	expiresIn := token.ExpiresIn
+	if expiresIn == 0 {
+		expiresIn = defaultExpiresIn
 	}
+	token.SetExpirationAt(expiresIn, tm.clock.Now())
 
 	return token, nil
 }
 
+// errorCodeForStatus derives an OAuth error code from the HTTP status of a
+// token endpoint response that carried no error code of its own
+func errorCodeForStatus(statusCode int) string {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return ErrorCodeInvalidRequest
+	case http.StatusUnauthorized:
+		return ErrorCodeInvalidClient
+	case http.StatusForbidden:
+		return ErrorCodeAccessDenied
+	default:
+		return fmt.Sprintf("http_%d", statusCode)
+	}
+}
+
 // ClearToken removes the current token
 func (tm *TokenManager) ClearToken() error {
 	tm.mu.Lock()
@@ -254,7 +324,16 @@ func (tm *TokenManager) IsAuthenticated() bool {
 	tm.mu.RLock()
 	defer tm.mu.RUnlock()
 
-	return tm.token != nil && tm.token.IsValid()
+	return tm.token != nil && tm.token.IsValidAt(tm.clock.Now())
+}
+
+// CanWrite returns false if the current token, or the requested scope when
+// no token carries one, is limited to ScopeReadOnly
+func (tm *TokenManager) CanWrite() bool {
+	if token := tm.loadToken(); token != nil && token.Scope != "" {
+		return token.Scope != ScopeReadOnly
+	}
+	return tm.config == nil || !tm.config.IsReadOnly()
 }
 
 // GetAccessToken returns just the access token string if available
@@ -281,15 +360,18 @@ func NewTokenSource(ctx context.Context, manager *TokenManager) *TokenSource {
 	}
 }
 
-// Token returns the current token, implementing oauth2.TokenSource interface
+// Token returns the current token, refreshing it when needed. It has the
+// shape of oauth2.TokenSource; use oauthx.TokenSource for a source of
+// golang.org/x/oauth2 tokens.
 func (ts *TokenSource) Token() (*Token, error) {
 	return ts.manager.GetToken(ts.ctx)
 }
 
 // AuthenticatedTransport creates an HTTP transport that automatically adds Bearer tokens
 type AuthenticatedTransport struct {
-	Base    http.RoundTripper
-	manager *TokenManager
+	Base        http.RoundTripper
+	manager     *TokenManager
+	rateLimiter *api.RateLimitTracker
 }
 
 // NewAuthenticatedTransport creates a new authenticated transport
@@ -300,6 +382,16 @@ func NewAuthenticatedTransport(manager *TokenManager) *AuthenticatedTransport {
 	}
 }
 
+// WithRateLimitTracker makes the transport record every request that gets
+// a successful response against tracker, as the client does, and wait for
+// a free slot while the tracker is at its limit. Sharing the tracker of a
+// client lets third-party HTTP libraries count against the same rolling
+// window.
+func (t *AuthenticatedTransport) WithRateLimitTracker(tracker *api.RateLimitTracker) *AuthenticatedTransport {
+	t.rateLimiter = tracker
+	return t
+}
+
 // RoundTrip implements http.RoundTripper
 func (t *AuthenticatedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	// Clone the request to avoid modifying the original
@@ -315,23 +407,62 @@ func (t *AuthenticatedTransport) RoundTrip(req *http.Request) (*http.Response, e
 	reqCopy.Header.Set("Authorization", "Bearer "+accessToken)
 
 	// Execute request
-	resp, err := t.Base.RoundTrip(reqCopy)
+	resp, err := t.send(reqCopy)
 
-	// If we get a 401, try refreshing the token once
-	if err == nil && resp.StatusCode == http.StatusUnauthorized {
+	// If we get a 401, try refreshing the token once. A request body that
+	// cannot be rewound has been consumed, so such a request is not retried.
+	rewindable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	if err == nil && resp.StatusCode == http.StatusUnauthorized && rewindable {
 		// Try to refresh token
 		if _, refreshErr := t.manager.RefreshToken(req.Context()); refreshErr == nil {
 			// Get new access token
 			if newAccessToken, tokenErr := t.manager.GetAccessToken(req.Context()); tokenErr == nil {
+				reqRetry := req.Clone(req.Context())
+				if req.GetBody != nil {
+					body, bodyErr := req.GetBody()
+					if bodyErr != nil {
+						return resp, nil
+					}
+					reqRetry.Body = body
+				}
+
 				// Retry the request with new token
 				_ = resp.Body.Close() // Close the original response
 
-				reqRetry := req.Clone(req.Context())
 				reqRetry.Header.Set("Authorization", "Bearer "+newAccessToken)
-				return t.Base.RoundTrip(reqRetry)
+				return t.send(reqRetry)
 			}
 		}
 	}
 
 	return resp, err
 }
+
+// send waits for the rate limit if needed, then sends the request and
+// records it against the tracker when the response is successful
+func (t *AuthenticatedTransport) send(req *http.Request) (*http.Response, error) {
+	if t.rateLimiter == nil {
+		return t.Base.RoundTrip(req)
+	}
+
+	for t.rateLimiter.IsAtLimit() {
+		wait := t.rateLimiter.TimeUntilReset()
+		if wait <= 0 {
+			break
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+
+	resp, err := t.Base.RoundTrip(req)
+	if err == nil && resp.StatusCode < 400 {
+		t.rateLimiter.RecordRequest()
+	}
+	return resp, err
+}
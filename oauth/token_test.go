@@ -0,0 +1,511 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/jarcoal/httpmock.v1"
+
+	"github.com/coltoneshaw/ynab.go/api"
+	"github.com/coltoneshaw/ynab.go/ynabtest"
+)
+
+func newTestTokenManager() *TokenManager {
+	config := NewOAuthConfig(Config{
+		ClientID:     "test-client",
+		ClientSecret: "test-secret",
+		RedirectURI:  "https://example.com/callback",
+	})
+	return NewTokenManager(config, NewMemoryStorage())
+}
+
+func TestTokenManager_ExchangeCode_Errors(t *testing.T) {
+	t.Run("mapped error code", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodPost, TokenURL,
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewStringResponse(http.StatusBadRequest, `{
+					"error": "invalid_grant",
+					"error_description": "The provided authorization grant is invalid"
+				}`), nil
+			},
+		)
+
+		token, err := newTestTokenManager().ExchangeCode(context.Background(), "bad-code")
+		assert.Nil(t, token)
+		assert.True(t, errors.Is(err, ErrInvalidGrant))
+		assert.False(t, errors.Is(err, ErrInvalidClient))
+
+		var errResp *ErrorResponse
+		require.True(t, errors.As(err, &errResp))
+		assert.Equal(t, http.StatusBadRequest, errResp.StatusCode)
+		assert.Equal(t, "The provided authorization grant is invalid", errResp.Error())
+	})
+
+	t.Run("unmapped error code", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodPost, TokenURL,
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewStringResponse(http.StatusBadRequest, `{
+					"error": "temporarily_unavailable"
+				}`), nil
+			},
+		)
+
+		_, err := newTestTokenManager().ExchangeCode(context.Background(), "code")
+		var errResp *ErrorResponse
+		require.True(t, errors.As(err, &errResp))
+		assert.Equal(t, "temporarily_unavailable", errResp.ErrorCode)
+		assert.Nil(t, errResp.Unwrap())
+		assert.True(t, errors.Is(err, &ErrorResponse{ErrorCode: "temporarily_unavailable"}))
+		assert.False(t, errors.Is(err, ErrInvalidGrant))
+	})
+
+	t.Run("error status without error body", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodPost, TokenURL,
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewStringResponse(http.StatusUnauthorized, `Unauthorized`), nil
+			},
+		)
+
+		_, err := newTestTokenManager().ExchangeCode(context.Background(), "code")
+		assert.True(t, errors.Is(err, ErrInvalidClient))
+
+		var errResp *ErrorResponse
+		require.True(t, errors.As(err, &errResp))
+		assert.Equal(t, http.StatusUnauthorized, errResp.StatusCode)
+	})
+
+	t.Run("server error status with empty json", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodPost, TokenURL,
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewStringResponse(http.StatusInternalServerError, `{}`), nil
+			},
+		)
+
+		_, err := newTestTokenManager().ExchangeCode(context.Background(), "code")
+		var errResp *ErrorResponse
+		require.True(t, errors.As(err, &errResp))
+		assert.Equal(t, "http_500", errResp.ErrorCode)
+		assert.Equal(t, http.StatusInternalServerError, errResp.StatusCode)
+	})
+}
+
+func TestTokenManager_WithClock(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	refreshes := 0
+	httpmock.RegisterResponder(http.MethodPost, TokenURL,
+		func(req *http.Request) (*http.Response, error) {
+			refreshes++
+			return httpmock.NewStringResponse(http.StatusOK, `{
+				"access_token": "refreshed-token",
+				"refresh_token": "refresh-token-2",
+				"token_type": "Bearer",
+				"expires_in": 7200
+			}`), nil
+		},
+	)
+
+	clock := ynabtest.NewClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	tm := newTestTokenManager().WithClock(clock)
+
+	token := &Token{AccessToken: "initial-token", RefreshToken: "refresh-token-1"}
+	token.SetExpirationAt(3600, clock.Now())
+	require.NoError(t, tm.SetToken(token))
+
+	// Still valid: no refresh
+	accessToken, err := tm.GetAccessToken(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "initial-token", accessToken)
+	assert.True(t, tm.IsAuthenticated())
+	assert.Equal(t, 0, refreshes)
+
+	// Inside the expiry buffer: refresh happens
+	clock.Advance(56 * time.Minute)
+	assert.False(t, tm.IsAuthenticated())
+
+	accessToken, err = tm.GetAccessToken(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "refreshed-token", accessToken)
+	assert.Equal(t, 1, refreshes)
+
+	// Refreshed token expiry is computed from the injected clock
+	refreshed, err := tm.GetToken(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, clock.Now(), refreshed.CreatedAt)
+	assert.Equal(t, clock.Now().Add(2*time.Hour), refreshed.ExpiresAt)
+}
+
+func TestTokenManager_DefaultClientTimeout(t *testing.T) {
+	tm := newTestTokenManager()
+	assert.NotSame(t, http.DefaultClient, tm.client)
+	assert.Equal(t, api.DefaultTimeout, tm.client.Timeout)
+}
+
+func TestTokenManager_GetToken_SlowRefresh(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	requested := make(chan struct{})
+	httpmock.RegisterResponder(http.MethodPost, TokenURL,
+		func(req *http.Request) (*http.Response, error) {
+			close(requested)
+			select {
+			case <-time.After(5 * time.Second):
+				return httpmock.NewStringResponse(http.StatusOK, `{"access_token": "late-token"}`), nil
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			}
+		},
+	)
+
+	clock := ynabtest.NewClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	tm := newTestTokenManager().WithClock(clock)
+
+	token := &Token{AccessToken: "expired-token", RefreshToken: "refresh-token"}
+	token.SetExpirationAt(60, clock.Now())
+	require.NoError(t, tm.SetToken(token))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	done := make(chan error, 1)
+	start := time.Now()
+	go func() {
+		_, err := tm.GetToken(ctx)
+		done <- err
+	}()
+
+	// Token reads must not block while the refresh is in flight
+	<-requested
+	assert.False(t, tm.IsAuthenticated())
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+	case <-time.After(2 * time.Second):
+		t.Fatal("GetToken did not honor the context deadline")
+	}
+}
+
+func TestTokenManager_CanWrite(t *testing.T) {
+	t.Run("full access token", func(t *testing.T) {
+		tm := newTestTokenManager()
+		require.NoError(t, tm.SetToken(&Token{AccessToken: "token"}))
+		assert.True(t, tm.CanWrite())
+	})
+
+	t.Run("read-only token", func(t *testing.T) {
+		tm := newTestTokenManager()
+		require.NoError(t, tm.SetToken(&Token{AccessToken: "token", Scope: ScopeReadOnly}))
+		assert.False(t, tm.CanWrite())
+	})
+
+	t.Run("read-only token in storage", func(t *testing.T) {
+		storage := NewMemoryStorage()
+		require.NoError(t, storage.SaveToken(&Token{AccessToken: "token", Scope: ScopeReadOnly}))
+
+		tm := NewTokenManager(NewOAuthConfig(Config{ClientID: "test-client"}), storage)
+		assert.False(t, tm.CanWrite())
+	})
+
+	t.Run("read-only config without token scope", func(t *testing.T) {
+		config := NewOAuthConfig(Config{ClientID: "test-client"}).WithReadOnlyScope()
+		tm := NewTokenManager(config, NewMemoryStorage())
+		require.NoError(t, tm.SetToken(&Token{AccessToken: "token"}))
+		assert.False(t, tm.CanWrite())
+	})
+}
+
+func TestAuthenticatedTransport_RateLimitTracker(t *testing.T) {
+	t.Run("records successful requests and retries once on 401", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodPost, TokenURL,
+			httpmock.NewStringResponder(http.StatusOK, `{
+				"access_token": "refreshed-token",
+				"refresh_token": "refresh-token-2",
+				"token_type": "Bearer",
+				"expires_in": 7200
+			}`),
+		)
+
+		tm := newTestTokenManager()
+		token := &Token{AccessToken: "stale-token", RefreshToken: "refresh-token-1"}
+		token.SetExpirationAt(7200, time.Now())
+		require.NoError(t, tm.SetToken(token))
+
+		var authorizations []string
+		tracker := api.NewYNABRateLimitTracker()
+		transport := NewAuthenticatedTransport(tm).WithRateLimitTracker(tracker)
+		transport.Base = ynabtest.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			authorizations = append(authorizations, req.Header.Get("Authorization"))
+			if req.Header.Get("Authorization") == "Bearer stale-token" {
+				return httpmock.NewStringResponse(http.StatusUnauthorized, `{}`), nil
+			}
+			return httpmock.NewStringResponse(http.StatusOK, `{}`), nil
+		})
+
+		req, err := http.NewRequest(http.MethodGet, "https://api.youneedabudget.com/v1/user", nil)
+		require.NoError(t, err)
+
+		resp, err := transport.RoundTrip(req)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []string{"Bearer stale-token", "Bearer refreshed-token"}, authorizations)
+		assert.Equal(t, 1, tracker.RequestsInWindow())
+		assert.Empty(t, req.Header.Get("Authorization"))
+	})
+
+	t.Run("does not record failed requests", func(t *testing.T) {
+		tm := newTestTokenManager()
+		token := &Token{AccessToken: "access-token"}
+		token.SetExpirationAt(7200, time.Now())
+		require.NoError(t, tm.SetToken(token))
+
+		tracker := api.NewYNABRateLimitTracker()
+		transport := NewAuthenticatedTransport(tm).WithRateLimitTracker(tracker)
+
+		transport.Base = ynabtest.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewStringResponse(http.StatusInternalServerError, `{}`), nil
+		})
+		req, err := http.NewRequest(http.MethodGet, "https://api.youneedabudget.com/v1/user", nil)
+		require.NoError(t, err)
+		resp, err := transport.RoundTrip(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+
+		transport.Base = ynabtest.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		})
+		_, err = transport.RoundTrip(req)
+		assert.Error(t, err)
+
+		assert.Equal(t, 0, tracker.RequestsInWindow())
+	})
+
+	t.Run("waits while at the limit", func(t *testing.T) {
+		tm := newTestTokenManager()
+		token := &Token{AccessToken: "access-token"}
+		token.SetExpirationAt(7200, time.Now())
+		require.NoError(t, tm.SetToken(token))
+
+		tracker := api.NewRateLimitTracker(1, time.Hour)
+		tracker.RecordRequest()
+
+		calls := 0
+		transport := NewAuthenticatedTransport(tm).WithRateLimitTracker(tracker)
+		transport.Base = ynabtest.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			return httpmock.NewStringResponse(http.StatusOK, `{}`), nil
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.youneedabudget.com/v1/user", nil)
+		require.NoError(t, err)
+
+		_, err = transport.RoundTrip(req)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 0, calls)
+		assert.Equal(t, 1, tracker.RequestsInWindow())
+	})
+}
+
+func TestAuthenticatedTransport_RetryRewindsBody(t *testing.T) {
+	setup := func(t *testing.T, bodies *[]string) *AuthenticatedTransport {
+		httpmock.Activate()
+		t.Cleanup(httpmock.DeactivateAndReset)
+		httpmock.RegisterResponder(http.MethodPost, TokenURL,
+			httpmock.NewStringResponder(http.StatusOK, `{
+				"access_token": "refreshed-token",
+				"refresh_token": "refresh-token-2",
+				"token_type": "Bearer",
+				"expires_in": 7200
+			}`),
+		)
+
+		tm := newTestTokenManager()
+		token := &Token{AccessToken: "stale-token", RefreshToken: "refresh-token-1"}
+		token.SetExpirationAt(7200, time.Now())
+		require.NoError(t, tm.SetToken(token))
+
+		transport := NewAuthenticatedTransport(tm)
+		transport.Base = ynabtest.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			*bodies = append(*bodies, string(body))
+			if req.Header.Get("Authorization") == "Bearer stale-token" {
+				return httpmock.NewStringResponse(http.StatusUnauthorized, `{}`), nil
+			}
+			return httpmock.NewStringResponse(http.StatusOK, `{}`), nil
+		})
+		return transport
+	}
+
+	t.Run("rewindable body is sent again", func(t *testing.T) {
+		var bodies []string
+		transport := setup(t, &bodies)
+
+		req, err := http.NewRequest(http.MethodPost, "https://api.youneedabudget.com/v1/budgets/aa248caa/transactions",
+			strings.NewReader(`{"transaction":{}}`))
+		require.NoError(t, err)
+
+		resp, err := transport.RoundTrip(req)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []string{`{"transaction":{}}`, `{"transaction":{}}`}, bodies)
+	})
+
+	t.Run("body without GetBody is not retried", func(t *testing.T) {
+		var bodies []string
+		transport := setup(t, &bodies)
+
+		req, err := http.NewRequest(http.MethodPost, "https://api.youneedabudget.com/v1/budgets/aa248caa/transactions",
+			io.NopCloser(strings.NewReader(`{"transaction":{}}`)))
+		require.NoError(t, err)
+		require.Nil(t, req.GetBody)
+
+		resp, err := transport.RoundTrip(req)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Equal(t, []string{`{"transaction":{}}`}, bodies)
+	})
+}
+
+func TestTokenManager_GetToken_Expired(t *testing.T) {
+	t.Run("with refresh token", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder(http.MethodPost, TokenURL,
+			httpmock.NewStringResponder(http.StatusOK, `{
+				"access_token": "refreshed-token",
+				"refresh_token": "refresh-token-2",
+				"token_type": "Bearer",
+				"expires_in": 7200
+			}`),
+		)
+
+		clock := ynabtest.NewClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+		tm := newTestTokenManager().WithClock(clock)
+
+		token := &Token{AccessToken: "expired-token", RefreshToken: "refresh-token-1"}
+		token.SetExpirationAt(3600, clock.Now().Add(-2*time.Hour))
+		require.NoError(t, tm.SetToken(token))
+
+		refreshed, err := tm.GetToken(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "refreshed-token", refreshed.AccessToken)
+	})
+
+	t.Run("without refresh token", func(t *testing.T) {
+		clock := ynabtest.NewClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+		tm := newTestTokenManager().WithClock(clock)
+
+		token := &Token{AccessToken: "implicit-token"}
+		token.SetExpirationAt(3600, clock.Now().Add(-2*time.Hour))
+		require.NoError(t, tm.SetToken(token))
+
+		_, err := tm.GetToken(context.Background())
+		assert.ErrorIs(t, err, ErrNoRefreshToken)
+		assert.ErrorIs(t, err, ErrTokenExpired)
+
+		_, err = tm.RefreshToken(context.Background())
+		assert.ErrorIs(t, err, ErrNoRefreshToken)
+		assert.NotErrorIs(t, err, ErrTokenExpired)
+	})
+}
+
+func TestTokenManager_WithTokenRefreshErrorCallback(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	clock := ynabtest.NewClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	expired := &Token{
+		AccessToken:  "expired-token",
+		RefreshToken: "revoked-refresh-token",
+		TokenType:    TokenTypeBearer,
+		ExpiresAt:    clock.Now().Add(-time.Minute),
+	}
+
+	var refreshErrs []error
+	var refreshed []*Token
+	manager := newTestTokenManager().WithClock(clock).
+		WithTokenRefreshCallback(func(token *Token) { refreshed = append(refreshed, token) }).
+		WithTokenRefreshErrorCallback(func(err error) { refreshErrs = append(refreshErrs, err) })
+
+	t.Run("invalid grant", func(t *testing.T) {
+		httpmock.RegisterResponder(http.MethodPost, TokenURL,
+			httpmock.NewStringResponder(http.StatusBadRequest, `{
+				"error": "invalid_grant",
+				"error_description": "The refresh token was revoked"
+			}`))
+		require.NoError(t, manager.SetToken(expired))
+
+		_, err := manager.GetToken(context.Background())
+		assert.ErrorIs(t, err, ErrInvalidGrant)
+		_, err = manager.RefreshToken(context.Background())
+		assert.ErrorIs(t, err, ErrInvalidGrant)
+
+		require.Len(t, refreshErrs, 2)
+		for _, refreshErr := range refreshErrs {
+			assert.ErrorIs(t, refreshErr, ErrInvalidGrant)
+			var errResp *ErrorResponse
+			if assert.ErrorAs(t, refreshErr, &errResp) {
+				assert.Equal(t, "The refresh token was revoked", errResp.ErrorDescription)
+			}
+		}
+		assert.Empty(t, refreshed)
+	})
+
+	t.Run("not called on success", func(t *testing.T) {
+		refreshErrs = nil
+		httpmock.RegisterResponder(http.MethodPost, TokenURL,
+			httpmock.NewStringResponder(http.StatusOK, `{"access_token": "fresh-token", "refresh_token": "new-refresh-token"}`))
+		require.NoError(t, manager.SetToken(expired))
+
+		token, err := manager.GetToken(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "fresh-token", token.AccessToken)
+		assert.Len(t, refreshed, 1)
+		assert.Empty(t, refreshErrs)
+	})
+
+	t.Run("not called without a refresh attempt", func(t *testing.T) {
+		refreshErrs = nil
+		require.NoError(t, manager.SetToken(&Token{AccessToken: "expired", ExpiresAt: clock.Now().Add(-time.Minute)}))
+
+		_, err := manager.GetToken(context.Background())
+		assert.ErrorIs(t, err, ErrNoRefreshToken)
+		assert.Empty(t, refreshErrs)
+	})
+}
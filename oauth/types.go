@@ -55,6 +55,11 @@ var (
 	ErrAccessDenied       = errors.New("access denied")
 	ErrTokenExpired       = errors.New("token expired")
 	ErrTokenRefreshFailed = errors.New("token refresh failed")
+
+	// ErrNoRefreshToken is returned when a refresh is needed but the token
+	// has no refresh token, as with implicit grant tokens. The full
+	// authorization flow must be restarted to get a new token.
+	ErrNoRefreshToken = errors.New("no refresh token")
 )
 
 // TokenType represents the type of token
@@ -91,18 +96,28 @@ type Token struct {
 
 // IsExpired checks if the token has expired
 func (t *Token) IsExpired() bool {
+	return t.IsExpiredAt(time.Now())
+}
+
+// IsExpiredAt checks if the token is expired at the given time
+func (t *Token) IsExpiredAt(now time.Time) bool {
 	if t.ExpiresAt.IsZero() {
 		return false
 	}
 
 	// Add 5 minute buffer to account for clock skew and network delays
 	buffer := 5 * time.Minute
-	return time.Now().Add(buffer).After(t.ExpiresAt)
+	return now.Add(buffer).After(t.ExpiresAt)
 }
 
 // IsValid checks if the token is valid and not expired
 func (t *Token) IsValid() bool {
-	return t.AccessToken != "" && !t.IsExpired()
+	return t.IsValidAt(time.Now())
+}
+
+// IsValidAt checks if the token is valid and not expired at the given time
+func (t *Token) IsValidAt(now time.Time) bool {
+	return t.AccessToken != "" && !t.IsExpiredAt(now)
 }
 
 // CanRefresh checks if the token can be refreshed
@@ -112,16 +127,36 @@ func (t *Token) CanRefresh() bool {
 
 // SetExpiration calculates and sets the expiration time
 func (t *Token) SetExpiration(expiresIn int64) {
+	t.SetExpirationAt(expiresIn, time.Now())
+}
+
+// SetExpirationAt calculates and sets the expiration time relative to now
+func (t *Token) SetExpirationAt(expiresIn int64, now time.Time) {
 	t.ExpiresIn = expiresIn
-	t.ExpiresAt = time.Now().Add(time.Duration(expiresIn) * time.Second)
-	t.CreatedAt = time.Now()
+	t.ExpiresAt = now.Add(time.Duration(expiresIn) * time.Second)
+	t.CreatedAt = now
 }
 
+// Standard OAuth 2.0 error codes (RFC 6749 section 5.2 and 4.1.2.1)
+const (
+	ErrorCodeInvalidRequest       = "invalid_request"
+	ErrorCodeInvalidClient        = "invalid_client"
+	ErrorCodeInvalidGrant         = "invalid_grant"
+	ErrorCodeInvalidScope         = "invalid_scope"
+	ErrorCodeUnauthorizedClient   = "unauthorized_client"
+	ErrorCodeUnsupportedGrantType = "unsupported_grant_type"
+	ErrorCodeAccessDenied         = "access_denied"
+)
+
 // ErrorResponse represents an OAuth error response
 type ErrorResponse struct {
 	ErrorCode        string `json:"error"`
 	ErrorDescription string `json:"error_description,omitempty"`
 	ErrorURI         string `json:"error_uri,omitempty"`
+
+	// StatusCode is the HTTP status code returned by the token endpoint,
+	// zero when the error did not come from an HTTP response
+	StatusCode int `json:"-"`
 }
 
 // Error implements the error interface
@@ -132,6 +167,39 @@ func (e *ErrorResponse) Error() string {
 	return e.ErrorCode
 }
 
+// Unwrap returns the sentinel error matching the OAuth error code, so
+// errors.Is(err, ErrInvalidGrant) works on token exchange failures.
+// Returns nil for unknown error codes.
+func (e *ErrorResponse) Unwrap() error {
+	switch e.ErrorCode {
+	case ErrorCodeInvalidRequest:
+		return ErrInvalidRequest
+	case ErrorCodeInvalidClient:
+		return ErrInvalidClient
+	case ErrorCodeInvalidGrant:
+		return ErrInvalidGrant
+	case ErrorCodeInvalidScope:
+		return ErrInvalidScope
+	case ErrorCodeUnauthorizedClient:
+		return ErrUnauthorizedClient
+	case ErrorCodeUnsupportedGrantType:
+		return ErrUnsupportedGrant
+	case ErrorCodeAccessDenied:
+		return ErrAccessDenied
+	default:
+		return nil
+	}
+}
+
+// Is reports whether target is an *ErrorResponse with the same error code
+func (e *ErrorResponse) Is(target error) bool {
+	t, ok := target.(*ErrorResponse)
+	if !ok {
+		return false
+	}
+	return e.ErrorCode == t.ErrorCode
+}
+
 // AuthorizeParams holds parameters for authorization URL generation
 type AuthorizeParams struct {
 	ClientID     string
@@ -53,6 +53,21 @@ func TestToken_IsExpired(t *testing.T) {
 	}
 }
 
+func TestToken_IsExpiredAt(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	token := &Token{AccessToken: "test-token"}
+	token.SetExpirationAt(3600, now)
+
+	assert.Equal(t, now, token.CreatedAt)
+	assert.Equal(t, now.Add(time.Hour), token.ExpiresAt)
+
+	assert.False(t, token.IsExpiredAt(now))
+	assert.False(t, token.IsExpiredAt(now.Add(55*time.Minute)))
+	assert.True(t, token.IsExpiredAt(now.Add(55*time.Minute+time.Second)), "expired inside the 5-minute buffer")
+	assert.True(t, token.IsValidAt(now))
+	assert.False(t, token.IsValidAt(now.Add(time.Hour)))
+}
+
 func TestToken_IsValid(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -166,6 +181,32 @@ func TestErrorResponse_Error(t *testing.T) {
 	}
 }
 
+func TestErrorResponse_Unwrap(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected error
+	}{
+		{ErrorCodeInvalidRequest, ErrInvalidRequest},
+		{ErrorCodeInvalidClient, ErrInvalidClient},
+		{ErrorCodeInvalidGrant, ErrInvalidGrant},
+		{ErrorCodeInvalidScope, ErrInvalidScope},
+		{ErrorCodeUnauthorizedClient, ErrUnauthorizedClient},
+		{ErrorCodeUnsupportedGrantType, ErrUnsupportedGrant},
+		{ErrorCodeAccessDenied, ErrAccessDenied},
+		{"unknown_code", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			err := &ErrorResponse{ErrorCode: tt.code}
+			assert.Equal(t, tt.expected, err.Unwrap())
+			if tt.expected != nil {
+				assert.ErrorIs(t, err, tt.expected)
+			}
+		})
+	}
+}
+
 func TestTokenResponse_ToToken(t *testing.T) {
 	tokenResponse := &TokenResponse{
 		AccessToken:  "access-token",
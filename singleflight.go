@@ -0,0 +1,60 @@
+package ynab
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// errFlightPanicked is the error waiters see when the shared call panicked
+var errFlightPanicked = errors.New("ynab: shared request panicked")
+
+// flightCall is an in-flight or completed singleFlight call
+type flightCall struct {
+	done chan struct{}
+	body []byte
+	err  error
+}
+
+// singleFlight deduplicates concurrent calls sharing the same key so only
+// the first caller runs fn and every other caller waits for its result
+type singleFlight struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// do runs fn once for all concurrent callers with the same key. leader is
+// true for the single caller that actually ran fn. A waiting caller gives
+// up with its own ctx error once ctx is done, leaving the call running for
+// the others.
+func (g *singleFlight) do(ctx context.Context, key string, fn func() ([]byte, error)) (body []byte, leader bool,
+	err error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.body, false, call.err
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		}
+	}
+
+	call := &flightCall{done: make(chan struct{}), err: errFlightPanicked}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	// Release the waiters even when fn panics
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.body, call.err = fn()
+	return call.body, true, call.err
+}
@@ -0,0 +1,81 @@
+package ynab
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSingleFlight_Do(t *testing.T) {
+	t.Run("waiter gives up when its context is done", func(t *testing.T) {
+		g := &singleFlight{}
+		started := make(chan struct{})
+		release := make(chan struct{})
+		leaderDone := make(chan struct{})
+		go func() {
+			defer close(leaderDone)
+			body, leader, err := g.do(context.Background(), "key", func() ([]byte, error) {
+				close(started)
+				<-release
+				return []byte("body"), nil
+			})
+			assert.NoError(t, err)
+			assert.True(t, leader)
+			assert.Equal(t, "body", string(body))
+		}()
+		<-started
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+		body, leader, err := g.do(ctx, "key", func() ([]byte, error) {
+			t.Fatal("waiter must not run fn")
+			return nil, nil
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.False(t, leader)
+		assert.Nil(t, body)
+
+		close(release)
+		<-leaderDone
+	})
+
+	t.Run("waiters are released when fn panics", func(t *testing.T) {
+		g := &singleFlight{}
+		started := make(chan struct{})
+		release := make(chan struct{})
+		go func() {
+			defer func() { assert.Equal(t, "boom", recover()) }()
+			_, _, _ = g.do(context.Background(), "key", func() ([]byte, error) {
+				close(started)
+				<-release
+				panic("boom")
+			})
+		}()
+		<-started
+
+		waiterDone := make(chan error)
+		go func() {
+			_, _, err := g.do(context.Background(), "key", nil)
+			waiterDone <- err
+		}()
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+
+		select {
+		case err := <-waiterDone:
+			assert.ErrorIs(t, err, errFlightPanicked)
+		case <-time.After(time.Second):
+			t.Fatal("waiter was not released")
+		}
+
+		// The key is free again for the next call
+		body, leader, err := g.do(context.Background(), "key", func() ([]byte, error) {
+			return []byte("ok"), nil
+		})
+		assert.NoError(t, err)
+		assert.True(t, leader)
+		assert.Equal(t, "ok", string(body))
+	})
+}
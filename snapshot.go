@@ -0,0 +1,372 @@
+package ynab
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/coltoneshaw/ynab.go/api"
+	"github.com/coltoneshaw/ynab.go/api/account"
+	"github.com/coltoneshaw/ynab.go/api/budget"
+	"github.com/coltoneshaw/ynab.go/api/category"
+	"github.com/coltoneshaw/ynab.go/api/month"
+	"github.com/coltoneshaw/ynab.go/api/payee"
+	"github.com/coltoneshaw/ynab.go/api/transaction"
+)
+
+// snapshotFile is the serialized form of a budget snapshot, laid out as
+// the data of a GetBudget response
+type snapshotFile struct {
+	Budget          *budget.Budget `json:"budget"`
+	ServerKnowledge uint64         `json:"server_knowledge"`
+}
+
+// ExportBudget fetches the full budget with GetBudget and writes it to w
+// as JSON, to be read back with LoadBudgetSnapshot
+func ExportBudget(client ClientServicer, budgetID string, w io.Writer) error {
+	snapshot, err := client.Budget().GetBudget(budgetID, nil)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(&snapshotFile{
+		Budget:          snapshot.Budget,
+		ServerKnowledge: snapshot.ServerKnowledge,
+	}); err != nil {
+		return fmt.Errorf("failed to write budget snapshot: %w", err)
+	}
+	return nil
+}
+
+// LoadBudgetSnapshot reads a budget snapshot written by ExportBudget
+func LoadBudgetSnapshot(r io.Reader) (*budget.Snapshot, error) {
+	var file snapshotFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to read budget snapshot: %w", err)
+	}
+	if file.Budget == nil {
+		return nil, fmt.Errorf("failed to read budget snapshot: no budget found")
+	}
+
+	return &budget.Snapshot{
+		Budget:          file.Budget,
+		ServerKnowledge: file.ServerKnowledge,
+	}, nil
+}
+
+// NewSnapshotClient creates a read-only client serving the budget of a
+// snapshot instead of calling the API, so code built on the client can be
+// run against canned data. Requests go through the regular services and
+// HTTP client, answered by a transport reading from the snapshot.
+//
+// The budget, its settings, accounts, categories, payees, months,
+// transactions (also by account and by month) and scheduled transactions
+// are served; query parameters such as since dates are ignored. Other
+// budgets and endpoints fail with a not found error, and writes fail with an
+// api.ErrorUnauthorizedScope error, as with a read-only token. Replacing
+// the HTTP client with WithHTTPClient disconnects the snapshot.
+func NewSnapshotClient(snapshot *budget.Snapshot) ClientServicer {
+	c := NewClient("snapshot")
+	c.WithHTTPClient(&http.Client{Transport: &snapshotTransport{
+		budget:          snapshot.Budget,
+		serverKnowledge: snapshot.ServerKnowledge,
+	}})
+	return c
+}
+
+// snapshotTransport is an http.RoundTripper answering API requests from
+// a budget snapshot
+type snapshotTransport struct {
+	budget          *budget.Budget
+	serverKnowledge uint64
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *snapshotTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+
+	if req.Method != http.MethodGet {
+		return snapshotError(req, http.StatusForbidden, &api.Error{
+			ID:     api.ErrorUnauthorizedScope,
+			Name:   "unauthorized_scope",
+			Detail: "Budget snapshots are read-only",
+		}), nil
+	}
+
+	path := strings.Split(strings.Trim(strings.TrimPrefix(req.URL.Path, "/v1"), "/"), "/")
+	if len(path) >= 2 && path[0] == "budgets" && !t.isBudget(path[1]) {
+		return snapshotError(req, http.StatusNotFound, &api.Error{
+			ID:     api.ErrorNotFound,
+			Name:   "not_found",
+			Detail: "The budget is not in the snapshot",
+		}), nil
+	}
+
+	data, ok := t.route(path)
+	if !ok {
+		return snapshotError(req, http.StatusNotFound, &api.Error{
+			ID:     api.ErrorResourceNotFound,
+			Name:   "resource_not_found",
+			Detail: fmt.Sprintf("%s is not available in the budget snapshot", req.URL.Path),
+		}), nil
+	}
+
+	body, err := json.Marshal(map[string]any{"data": data})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot response: %w", err)
+	}
+	return snapshotResponse(req, http.StatusOK, body), nil
+}
+
+// route returns the response data of the endpoint at the path segments,
+// or false when the snapshot cannot serve it
+func (t *snapshotTransport) route(path []string) (any, bool) {
+	b := t.budget
+	if len(path) == 1 && path[0] == "budgets" {
+		return map[string]any{"budgets": []*budget.Summary{t.summary()}}, true
+	}
+	if len(path) < 2 || path[0] != "budgets" {
+		return nil, false
+	}
+
+	resource := path[2:]
+	withKnowledge := func(key string, value any) (any, bool) {
+		return map[string]any{key: value, "server_knowledge": t.serverKnowledge}, true
+	}
+
+	switch {
+	case len(resource) == 0:
+		return withKnowledge("budget", b)
+	case len(resource) == 1 && resource[0] == "settings":
+		return map[string]any{"settings": &budget.Settings{
+			DateFormat:     b.DateFormat,
+			CurrencyFormat: b.CurrencyFormat,
+		}}, true
+	case resource[0] == "accounts":
+		switch len(resource) {
+		case 1:
+			return withKnowledge("accounts", b.Accounts)
+		case 2:
+			return findByID("account", b.Accounts, func(a *account.Account) bool { return a.ID == resource[1] })
+		case 3:
+			if resource[2] == "transactions" {
+				return withKnowledge("transactions", filterTransactions(b.HydratedTransactions(),
+					func(tx *transaction.Transaction) bool { return tx.AccountID == resource[1] }))
+			}
+		}
+	case resource[0] == "categories":
+		switch len(resource) {
+		case 1:
+			return withKnowledge("category_groups", t.categoryGroups())
+		case 2:
+			return findByID("category", b.Categories, func(c *category.Category) bool { return c.ID == resource[1] })
+		}
+	case resource[0] == "payees":
+		switch len(resource) {
+		case 1:
+			return withKnowledge("payees", b.Payees)
+		case 2:
+			return findByID("payee", b.Payees, func(p *payee.Payee) bool { return p.ID == resource[1] })
+		}
+	case resource[0] == "months":
+		if len(resource) == 1 {
+			return withKnowledge("months", b.Months)
+		}
+		first, ok := snapshotMonth(resource[1])
+		if !ok {
+			return nil, false
+		}
+		switch {
+		case len(resource) == 2:
+			return findByID("month", b.Months, func(m *month.Month) bool { return api.DateFormat(m.Month) == first })
+		case len(resource) == 3 && resource[2] == "transactions":
+			return withKnowledge("transactions", filterTransactions(b.HydratedTransactions(),
+				func(tx *transaction.Transaction) bool {
+					return api.DateFormat(tx.Date)[:len("2006-01")] == first[:len("2006-01")]
+				}))
+		}
+	case resource[0] == "transactions":
+		switch len(resource) {
+		case 1:
+			return withKnowledge("transactions", b.HydratedTransactions())
+		case 2:
+			return findByID("transaction", b.HydratedTransactions(),
+				func(tx *transaction.Transaction) bool { return tx.ID == resource[1] })
+		}
+	case resource[0] == "scheduled_transactions":
+		switch len(resource) {
+		case 1:
+			return withKnowledge("scheduled_transactions", t.scheduledTransactions())
+		case 2:
+			return findByID("scheduled_transaction", t.scheduledTransactions(),
+				func(s *transaction.Scheduled) bool { return s.ID == resource[1] })
+		}
+	}
+	return nil, false
+}
+
+// isBudget reports whether id designates the budget of the snapshot
+func (t *snapshotTransport) isBudget(id string) bool {
+	return id == t.budget.ID || id == budget.LastUsed || id == budget.Default
+}
+
+// summary returns the budget of the snapshot as listed by GetBudgets
+func (t *snapshotTransport) summary() *budget.Summary {
+	b := t.budget
+	return &budget.Summary{
+		ID:             b.ID,
+		Name:           b.Name,
+		DateFormat:     b.DateFormat,
+		CurrencyFormat: b.CurrencyFormat,
+		LastModifiedOn: b.LastModifiedOn,
+		FirstMonth:     b.FirstMonth,
+		LastMonth:      b.LastMonth,
+	}
+}
+
+// categoryGroups returns the category groups of the budget with their
+// categories, as listed by GetCategories
+func (t *snapshotTransport) categoryGroups() []*category.GroupWithCategories {
+	groups := make([]*category.GroupWithCategories, 0, len(t.budget.CategoryGroups))
+	for _, g := range t.budget.CategoryGroups {
+		group := &category.GroupWithCategories{
+			ID:         g.ID,
+			Name:       g.Name,
+			Hidden:     g.Hidden,
+			Deleted:    g.Deleted,
+			Categories: []*category.Category{},
+		}
+		for _, c := range t.budget.Categories {
+			if c.CategoryGroupID == g.ID {
+				group.Categories = append(group.Categories, c)
+			}
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// scheduledTransactions returns the scheduled transactions of the budget
+// with their subtransactions and account, payee and category names
+func (t *snapshotTransport) scheduledTransactions() []*transaction.Scheduled {
+	b := t.budget
+	accountNames := make(map[string]string, len(b.Accounts))
+	for _, a := range b.Accounts {
+		accountNames[a.ID] = a.Name
+	}
+	payeeNames := make(map[string]string, len(b.Payees))
+	for _, p := range b.Payees {
+		payeeNames[p.ID] = p.Name
+	}
+	categoryNames := make(map[string]string, len(b.Categories))
+	for _, c := range b.Categories {
+		categoryNames[c.ID] = c.Name
+	}
+	lookup := func(names map[string]string, id *string) *string {
+		if id == nil {
+			return nil
+		}
+		if name, ok := names[*id]; ok {
+			return &name
+		}
+		return nil
+	}
+
+	subTransactions := make(map[string][]*transaction.ScheduledSubTransaction)
+	for _, sub := range b.ScheduledSubTransactions {
+		subTransactions[sub.ScheduledTransactionID] = append(subTransactions[sub.ScheduledTransactionID], sub)
+	}
+
+	scheduled := make([]*transaction.Scheduled, 0, len(b.ScheduledTransactions))
+	for _, s := range b.ScheduledTransactions {
+		scheduled = append(scheduled, &transaction.Scheduled{
+			ID:                      s.ID,
+			DateFirst:               s.DateFirst,
+			DateNext:                s.DateNext,
+			Frequency:               s.Frequency,
+			Amount:                  s.Amount,
+			AccountID:               s.AccountID,
+			Deleted:                 s.Deleted,
+			AccountName:             accountNames[s.AccountID],
+			SubTransactions:         subTransactions[s.ID],
+			Memo:                    s.Memo,
+			FlagColor:               s.FlagColor,
+			FlagName:                s.FlagName,
+			PayeeID:                 s.PayeeID,
+			CategoryID:              s.CategoryID,
+			TransferAccountID:       s.TransferAccountID,
+			TransferTransactionID:   s.TransferTransactionID,
+			MatchedTransactionID:    s.MatchedTransactionID,
+			ImportPayeeName:         s.ImportPayeeName,
+			ImportPayeeNameOriginal: s.ImportPayeeNameOriginal,
+			DebtTransactionType:     s.DebtTransactionType,
+			PayeeName:               lookup(payeeNames, s.PayeeID),
+			CategoryName:            lookup(categoryNames, s.CategoryID),
+		})
+	}
+	return scheduled
+}
+
+// findByID returns the response data holding under key the first item
+// matching, or false when there is none
+func findByID[T any](key string, items []T, match func(T) bool) (any, bool) {
+	for _, item := range items {
+		if match(item) {
+			return map[string]any{key: item}, true
+		}
+	}
+	return nil, false
+}
+
+// filterTransactions returns the transactions matching keep
+func filterTransactions(txs []*transaction.Transaction, keep func(*transaction.Transaction) bool) []*transaction.Transaction {
+	kept := []*transaction.Transaction{}
+	for _, tx := range txs {
+		if keep(tx) {
+			kept = append(kept, tx)
+		}
+	}
+	return kept
+}
+
+// snapshotMonth resolves a month path argument to the "YYYY-MM-01" form
+// of the months of a snapshot, or false when it is not a month
+func snapshotMonth(param string) (string, bool) {
+	first, err := api.MonthParam(param)
+	if err != nil {
+		return "", false
+	}
+	if first == api.CurrentMonth {
+		first = time.Now().Format("2006-01") + "-01"
+	}
+	return first, true
+}
+
+// snapshotError returns an API error response
+func snapshotError(req *http.Request, status int, apiErr *api.Error) *http.Response {
+	body, _ := json.Marshal(map[string]*api.Error{"error": apiErr})
+	return snapshotResponse(req, status, body)
+}
+
+// snapshotResponse returns a JSON response with the given status and body
+func snapshotResponse(req *http.Request, status int, body []byte) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,163 @@
+package ynab
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/jarcoal/httpmock.v1"
+
+	"github.com/coltoneshaw/ynab.go/api"
+)
+
+const snapshotBudgetJSON = `{"data":{"budget":{
+	"id":"aa248caa",
+	"name":"Household",
+	"last_modified_on":"2024-02-03T10:00:00Z",
+	"first_month":"2024-01-01",
+	"last_month":"2024-02-01",
+	"date_format":{"format":"YYYY-MM-DD"},
+	"currency_format":{"iso_code":"USD","decimal_digits":2,"decimal_separator":".","group_separator":",","currency_symbol":"$","symbol_first":true,"display_symbol":true},
+	"accounts":[
+		{"id":"acc-checking","name":"Checking","type":"checking","on_budget":true,"balance":250000},
+		{"id":"acc-savings","name":"Savings","type":"savings","on_budget":true,"balance":1000000}
+	],
+	"payees":[{"id":"payee-market","name":"Supermarket"}],
+	"payee_locations":[],
+	"category_groups":[{"id":"group-everyday","name":"Everyday"}],
+	"categories":[
+		{"id":"cat-groceries","category_group_id":"group-everyday","name":"Groceries","budgeted":50000},
+		{"id":"cat-dining","category_group_id":"group-everyday","name":"Dining Out","budgeted":20000}
+	],
+	"months":[
+		{"month":"2024-01-01","to_be_budgeted":0,"categories":[{"id":"cat-groceries","name":"Groceries","budgeted":40000}]},
+		{"month":"2024-02-01","to_be_budgeted":1000,"categories":[{"id":"cat-groceries","name":"Groceries","budgeted":50000}]}
+	],
+	"transactions":[
+		{"id":"tx-1","date":"2024-01-15","amount":-4000,"cleared":"cleared","approved":true,"account_id":"acc-checking","payee_id":"payee-market","category_id":"cat-groceries"},
+		{"id":"tx-2","date":"2024-02-02","amount":-9000,"cleared":"uncleared","approved":true,"account_id":"acc-savings","payee_id":"payee-market"}
+	],
+	"subtransactions":[
+		{"id":"sub-1","transaction_id":"tx-2","amount":-5000,"category_id":"cat-groceries"},
+		{"id":"sub-2","transaction_id":"tx-2","amount":-4000,"category_id":"cat-dining"}
+	],
+	"scheduled_transactions":[
+		{"id":"sched-1","date_first":"2024-01-01","date_next":"2024-03-01","frequency":"monthly","amount":-20000,"account_id":"acc-checking","payee_id":"payee-market","category_id":"cat-dining"}
+	],
+	"scheduled_subtransactions":[]
+},"server_knowledge":42}}`
+
+func TestExportBudget_RoundTrip(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s%s", apiEndpoint, "/budgets/aa248caa"),
+		httpmock.NewStringResponder(http.StatusOK, snapshotBudgetJSON))
+
+	c := NewClient("")
+	var buf bytes.Buffer
+	require.NoError(t, ExportBudget(c, "aa248caa", &buf))
+
+	loaded, err := LoadBudgetSnapshot(&buf)
+	require.NoError(t, err)
+
+	original, err := c.Budget().GetBudget("aa248caa", nil)
+	require.NoError(t, err)
+	assert.Equal(t, original, loaded)
+	assert.Equal(t, uint64(42), loaded.ServerKnowledge)
+
+	// The snapshot client never reaches the API
+	httpmock.Reset()
+	offline := NewSnapshotClient(loaded)
+
+	budgets, err := offline.Budget().GetBudgets()
+	require.NoError(t, err)
+	if assert.Len(t, budgets, 1) {
+		assert.Equal(t, "Household", budgets[0].Name)
+	}
+
+	reloaded, err := offline.Budget().GetBudget("last-used", nil)
+	require.NoError(t, err)
+	assert.Equal(t, loaded, reloaded)
+
+	settings, err := offline.Budget().GetBudgetSettings("aa248caa")
+	require.NoError(t, err)
+	assert.Equal(t, "USD", settings.CurrencyFormat.ISOCode)
+
+	accounts, err := offline.Account().GetAccounts("aa248caa", nil)
+	require.NoError(t, err)
+	assert.Len(t, accounts.Accounts, 2)
+
+	savings, err := offline.Account().GetAccount("aa248caa", "acc-savings")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000000), savings.Balance)
+
+	groups, err := offline.Category().GetCategories("aa248caa", nil)
+	require.NoError(t, err)
+	if assert.Len(t, groups.GroupWithCategories, 1) {
+		assert.Len(t, groups.GroupWithCategories[0].Categories, 2)
+	}
+
+	date, err := api.DateFromString("2024-02-01")
+	require.NoError(t, err)
+	feb, err := offline.Month().GetMonth("aa248caa", date)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), feb.ToBeBudgetedAmount())
+
+	txs, err := offline.Transaction().GetTransactions("aa248caa", nil)
+	require.NoError(t, err)
+	require.Len(t, txs.Transactions, 2)
+	assert.Equal(t, "Checking", txs.Transactions[0].AccountName)
+	assert.Equal(t, "Supermarket", *txs.Transactions[0].PayeeName)
+	assert.Len(t, txs.Transactions[1].SubTransactions, 2)
+
+	byAccount, err := offline.Transaction().GetTransactionsByAccount("aa248caa", "acc-savings", nil)
+	require.NoError(t, err)
+	if assert.Len(t, byAccount.Transactions, 1) {
+		assert.Equal(t, "tx-2", byAccount.Transactions[0].ID)
+	}
+
+	byMonth, err := offline.Transaction().GetTransactionsByMonth("aa248caa", "2024-01-01", nil)
+	require.NoError(t, err)
+	if assert.Len(t, byMonth.Transactions, 1) {
+		assert.Equal(t, "tx-1", byMonth.Transactions[0].ID)
+	}
+
+	scheduled, err := offline.Transaction().GetScheduledTransaction("aa248caa", "sched-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Checking", scheduled.AccountName)
+	assert.Equal(t, "Dining Out", *scheduled.CategoryName)
+
+	assert.Zero(t, httpmock.GetTotalCallCount())
+}
+
+func TestSnapshotClient_Errors(t *testing.T) {
+	loaded, err := LoadBudgetSnapshot(strings.NewReader(
+		`{"budget":{"id":"aa248caa","name":"Household"},"server_knowledge":1}`))
+	require.NoError(t, err)
+	c := NewSnapshotClient(loaded)
+
+	_, err = c.Transaction().DeleteTransaction("aa248caa", "tx-1")
+	var apiErr *api.Error
+	if assert.ErrorAs(t, err, &apiErr) {
+		assert.Equal(t, api.ErrorUnauthorizedScope, apiErr.ID)
+	}
+
+	_, err = c.Account().GetAccount("aa248caa", "acc-missing")
+	if assert.ErrorAs(t, err, &apiErr) {
+		assert.True(t, apiErr.IsNotFound())
+	}
+
+	_, err = c.Account().GetAccounts("other-budget", nil)
+	if assert.ErrorAs(t, err, &apiErr) {
+		assert.True(t, apiErr.IsNotFound())
+	}
+	assert.ErrorIs(t, err, api.ErrBudgetNotFound)
+
+	_, err = LoadBudgetSnapshot(strings.NewReader(`{"server_knowledge":1}`))
+	assert.ErrorContains(t, err, "no budget found")
+}
@@ -0,0 +1,226 @@
+package ynab
+
+import (
+	"sync"
+
+	"github.com/coltoneshaw/ynab.go/api"
+	"github.com/coltoneshaw/ynab.go/api/account"
+	"github.com/coltoneshaw/ynab.go/api/category"
+	"github.com/coltoneshaw/ynab.go/api/payee"
+	"github.com/coltoneshaw/ynab.go/api/transaction"
+)
+
+// SyncEntity identifies an entity type kept in sync by a SyncEngine
+type SyncEntity string
+
+const (
+	// SyncEntityAccounts identifies budget accounts
+	SyncEntityAccounts SyncEntity = "accounts"
+	// SyncEntityCategories identifies budget categories
+	SyncEntityCategories SyncEntity = "categories"
+	// SyncEntityPayees identifies budget payees
+	SyncEntityPayees SyncEntity = "payees"
+	// SyncEntityTransactions identifies budget transactions
+	SyncEntityTransactions SyncEntity = "transactions"
+)
+
+// SyncCursors maps each entity type to its last known server knowledge
+type SyncCursors map[SyncEntity]uint64
+
+// SyncState is the serializable state of a SyncEngine. Persist it, e.g.
+// with encoding/json, and pass it to NewSyncEngine to resume syncing
+// after a restart.
+type SyncState struct {
+	Budgets map[string]*BudgetSyncState `json:"budgets"`
+}
+
+// BudgetSyncState is the sync state of a single budget
+type BudgetSyncState struct {
+	// Cursors the server knowledge to resume each entity type from
+	Cursors SyncCursors `json:"cursors"`
+	// Known the IDs of the entities seen so far, used to tell added
+	// entities from changed ones
+	Known map[SyncEntity]map[string]bool `json:"known"`
+}
+
+// SyncChanges holds the entities of one type that changed during a sync
+type SyncChanges[T any] struct {
+	// Added entities seen for the first time
+	Added []T
+	// Changed entities already seen in a previous sync
+	Changed []T
+	// Deleted entities flagged as deleted by the server
+	Deleted []T
+}
+
+// SyncResult represents the outcome of a SyncEngine.SyncAll call
+type SyncResult struct {
+	Accounts     SyncChanges[*account.Account]
+	Categories   SyncChanges[*category.Category]
+	Payees       SyncChanges[*payee.Payee]
+	Transactions SyncChanges[*transaction.Transaction]
+
+	// Cursors the updated server knowledge for each entity type
+	Cursors SyncCursors
+}
+
+// SyncEngine keeps a local replica of budget entities up to date by
+// requesting only the entities changed since the previous sync through
+// the delta endpoints of the API
+type SyncEngine struct {
+	mu sync.Mutex
+
+	c     ClientServicer
+	state *SyncState
+}
+
+// NewSyncEngine creates a sync engine for the given client. A nil state
+// starts a full sync for every budget.
+func NewSyncEngine(c ClientServicer, state *SyncState) *SyncEngine {
+	if state == nil {
+		state = &SyncState{}
+	}
+	if state.Budgets == nil {
+		state.Budgets = make(map[string]*BudgetSyncState)
+	}
+	return &SyncEngine{c: c, state: state}
+}
+
+// State returns a copy of the engine state, safe to serialize while the
+// engine keeps syncing
+func (e *SyncEngine) State() *SyncState {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	state := &SyncState{Budgets: make(map[string]*BudgetSyncState, len(e.state.Budgets))}
+	for budgetID, budgetState := range e.state.Budgets {
+		state.Budgets[budgetID] = budgetState.clone()
+	}
+	return state
+}
+
+// SyncAll pulls the accounts, categories, payees and transactions of a
+// budget changed since the previous sync. The engine state is only
+// updated once every entity type has been fetched successfully, so a
+// failed sync can simply be retried.
+func (e *SyncEngine) SyncAll(budgetID string) (*SyncResult, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	current, ok := e.state.Budgets[budgetID]
+	if !ok {
+		current = &BudgetSyncState{}
+	}
+	next := current.clone()
+
+	accounts, err := e.c.Account().GetAccounts(budgetID, deltaFilter(next.Cursors[SyncEntityAccounts]))
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := e.c.Category().GetCategories(budgetID, deltaFilter(next.Cursors[SyncEntityCategories]))
+	if err != nil {
+		return nil, err
+	}
+
+	payees, err := e.c.Payee().GetPayees(budgetID, deltaFilter(next.Cursors[SyncEntityPayees]))
+	if err != nil {
+		return nil, err
+	}
+
+	var transactionFilter *transaction.Filter
+	if knowledge := next.Cursors[SyncEntityTransactions]; knowledge > 0 {
+		transactionFilter = &transaction.Filter{LastKnowledgeOfServer: &knowledge}
+	}
+	transactions, err := e.c.Transaction().GetTransactions(budgetID, transactionFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	var flatCategories []*category.Category
+	for _, group := range categories.GroupWithCategories {
+		flatCategories = append(flatCategories, group.Categories...)
+	}
+
+	result := &SyncResult{
+		Accounts: syncChanges(next.known(SyncEntityAccounts), accounts.Accounts,
+			func(a *account.Account) (string, bool) { return a.ID, a.Deleted }),
+		Categories: syncChanges(next.known(SyncEntityCategories), flatCategories,
+			func(c *category.Category) (string, bool) { return c.ID, c.Deleted }),
+		Payees: syncChanges(next.known(SyncEntityPayees), payees.Payees,
+			func(p *payee.Payee) (string, bool) { return p.ID, p.Deleted }),
+		Transactions: syncChanges(next.known(SyncEntityTransactions), transactions.Transactions,
+			func(t *transaction.Transaction) (string, bool) { return t.ID, t.Deleted }),
+	}
+
+	next.Cursors[SyncEntityAccounts] = accounts.ServerKnowledge
+	next.Cursors[SyncEntityCategories] = categories.ServerKnowledge
+	next.Cursors[SyncEntityPayees] = payees.ServerKnowledge
+	next.Cursors[SyncEntityTransactions] = transactions.ServerKnowledge
+
+	result.Cursors = make(SyncCursors, len(next.Cursors))
+	for entity, knowledge := range next.Cursors {
+		result.Cursors[entity] = knowledge
+	}
+
+	e.state.Budgets[budgetID] = next
+	return result, nil
+}
+
+// clone returns a deep copy of the budget state with its maps allocated
+func (s *BudgetSyncState) clone() *BudgetSyncState {
+	c := &BudgetSyncState{
+		Cursors: make(SyncCursors, len(s.Cursors)),
+		Known:   make(map[SyncEntity]map[string]bool, len(s.Known)),
+	}
+	for entity, knowledge := range s.Cursors {
+		c.Cursors[entity] = knowledge
+	}
+	for entity, ids := range s.Known {
+		known := make(map[string]bool, len(ids))
+		for id := range ids {
+			known[id] = true
+		}
+		c.Known[entity] = known
+	}
+	return c
+}
+
+// known returns the set of known IDs for an entity type, creating it if needed
+func (s *BudgetSyncState) known(entity SyncEntity) map[string]bool {
+	ids, ok := s.Known[entity]
+	if !ok {
+		ids = make(map[string]bool)
+		s.Known[entity] = ids
+	}
+	return ids
+}
+
+// deltaFilter returns the filter requesting changes since knowledge, or
+// nil to request everything when no knowledge is available yet
+func deltaFilter(knowledge uint64) *api.Filter {
+	if knowledge == 0 {
+		return nil
+	}
+	return &api.Filter{LastKnowledgeOfServer: knowledge}
+}
+
+// syncChanges sorts entities into added, changed and deleted sets,
+// updating the known IDs accordingly
+func syncChanges[T any](known map[string]bool, entities []T, key func(T) (id string, deleted bool)) SyncChanges[T] {
+	var changes SyncChanges[T]
+	for _, entity := range entities {
+		id, deleted := key(entity)
+		switch {
+		case deleted:
+			delete(known, id)
+			changes.Deleted = append(changes.Deleted, entity)
+		case known[id]:
+			changes.Changed = append(changes.Changed, entity)
+		default:
+			known[id] = true
+			changes.Added = append(changes.Added, entity)
+		}
+	}
+	return changes
+}
@@ -0,0 +1,134 @@
+package ynab
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/jarcoal/httpmock.v1"
+
+	"github.com/coltoneshaw/ynab.go/api"
+)
+
+// registerSyncResponder serves the full body when no server knowledge is
+// sent and the delta body when the expected knowledge is sent
+func registerSyncResponder(t *testing.T, entity, knowledge, full, delta string) {
+	httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s/budgets/aa248caa/%s", apiEndpoint, entity),
+		func(req *http.Request) (*http.Response, error) {
+			switch req.URL.Query().Get("last_knowledge_of_server") {
+			case "":
+				return httpmock.NewStringResponse(http.StatusOK, full), nil
+			case knowledge:
+				return httpmock.NewStringResponse(http.StatusOK, delta), nil
+			default:
+				t.Errorf("unexpected %s server knowledge %q", entity, req.URL.RawQuery)
+				return httpmock.NewStringResponse(http.StatusBadRequest, ""), nil
+			}
+		},
+	)
+}
+
+func TestSyncEngine_SyncAll(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	registerSyncResponder(t, "accounts", "10",
+		`{"data":{"server_knowledge":10,"accounts":[{"id":"acc-1","name":"Checking"},{"id":"acc-2","name":"Savings"}]}}`,
+		`{"data":{"server_knowledge":12,"accounts":[{"id":"acc-2","name":"Rainy Day"},{"id":"acc-3","name":"Cash"}]}}`,
+	)
+	registerSyncResponder(t, "categories", "20",
+		`{"data":{"server_knowledge":20,"category_groups":[{"id":"grp-1","categories":[{"id":"cat-1","name":"Rent"},{"id":"cat-2","name":"Food"}]}]}}`,
+		`{"data":{"server_knowledge":21,"category_groups":[{"id":"grp-1","categories":[{"id":"cat-2","name":"Food","deleted":true}]}]}}`,
+	)
+	registerSyncResponder(t, "payees", "30",
+		`{"data":{"server_knowledge":30,"payees":[{"id":"pay-1","name":"Landlord"}]}}`,
+		`{"data":{"server_knowledge":30,"payees":[]}}`,
+	)
+	registerSyncResponder(t, "transactions", "40",
+		`{"data":{"server_knowledge":40,"transactions":[{"id":"tx-1","amount":-1000},{"id":"tx-2","amount":-2000}]}}`,
+		`{"data":{"server_knowledge":42,"transactions":[{"id":"tx-1","amount":-1500},{"id":"tx-2","deleted":true},{"id":"tx-3","amount":500}]}}`,
+	)
+
+	engine := NewSyncEngine(NewClient(""), nil)
+
+	first, err := engine.SyncAll("aa248caa")
+	require.NoError(t, err)
+
+	assert.Len(t, first.Accounts.Added, 2)
+	assert.Len(t, first.Categories.Added, 2)
+	assert.Len(t, first.Payees.Added, 1)
+	assert.Len(t, first.Transactions.Added, 2)
+	assert.Empty(t, first.Transactions.Changed)
+	assert.Empty(t, first.Transactions.Deleted)
+	assert.Equal(t, SyncCursors{
+		SyncEntityAccounts:     10,
+		SyncEntityCategories:   20,
+		SyncEntityPayees:       30,
+		SyncEntityTransactions: 40,
+	}, first.Cursors)
+
+	// Simulate a process restart by round-tripping the state through JSON
+	data, err := json.Marshal(engine.State())
+	require.NoError(t, err)
+	state := &SyncState{}
+	require.NoError(t, json.Unmarshal(data, state))
+	engine = NewSyncEngine(NewClient(""), state)
+
+	second, err := engine.SyncAll("aa248caa")
+	require.NoError(t, err)
+
+	if assert.Len(t, second.Accounts.Added, 1) {
+		assert.Equal(t, "acc-3", second.Accounts.Added[0].ID)
+	}
+	if assert.Len(t, second.Accounts.Changed, 1) {
+		assert.Equal(t, "Rainy Day", second.Accounts.Changed[0].Name)
+	}
+	if assert.Len(t, second.Categories.Deleted, 1) {
+		assert.Equal(t, "cat-2", second.Categories.Deleted[0].ID)
+	}
+	assert.Empty(t, second.Categories.Added)
+	assert.Empty(t, second.Payees.Added)
+	assert.Empty(t, second.Payees.Changed)
+	if assert.Len(t, second.Transactions.Changed, 1) {
+		assert.Equal(t, api.Milliunits(-1500), second.Transactions.Changed[0].Amount)
+	}
+	if assert.Len(t, second.Transactions.Deleted, 1) {
+		assert.Equal(t, "tx-2", second.Transactions.Deleted[0].ID)
+	}
+	if assert.Len(t, second.Transactions.Added, 1) {
+		assert.Equal(t, "tx-3", second.Transactions.Added[0].ID)
+	}
+	assert.Equal(t, SyncCursors{
+		SyncEntityAccounts:     12,
+		SyncEntityCategories:   21,
+		SyncEntityPayees:       30,
+		SyncEntityTransactions: 42,
+	}, second.Cursors)
+
+	budgetState := engine.State().Budgets["aa248caa"]
+	assert.False(t, budgetState.Known[SyncEntityTransactions]["tx-2"])
+	assert.False(t, budgetState.Known[SyncEntityCategories]["cat-2"])
+}
+
+func TestSyncEngine_SyncAll_FailureKeepsState(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s/budgets/aa248caa/accounts", apiEndpoint),
+		httpmock.NewStringResponder(http.StatusOK, `{"data":{"server_knowledge":10,"accounts":[{"id":"acc-1"}]}}`))
+	httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s/budgets/aa248caa/categories", apiEndpoint),
+		httpmock.NewStringResponder(http.StatusInternalServerError, `{"error":{"id":"500","name":"internal_server_error","detail":"Internal Server Error"}}`))
+
+	state := &SyncState{Budgets: map[string]*BudgetSyncState{
+		"aa248caa": {Cursors: SyncCursors{SyncEntityAccounts: 5}},
+	}}
+	engine := NewSyncEngine(NewClient(""), state)
+
+	_, err := engine.SyncAll("aa248caa")
+	assert.Error(t, err)
+	assert.Equal(t, uint64(5), engine.State().Budgets["aa248caa"].Cursors[SyncEntityAccounts])
+	assert.Empty(t, engine.State().Budgets["aa248caa"].Known)
+}
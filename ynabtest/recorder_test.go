@@ -0,0 +1,102 @@
+package ynabtest_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coltoneshaw/ynab.go"
+	"github.com/coltoneshaw/ynab.go/api/category"
+	"github.com/coltoneshaw/ynab.go/ynabtest"
+)
+
+func jsonResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+	}
+}
+
+func TestRecorder_RecordThenReplay(t *testing.T) {
+	cassette := filepath.Join(t.TempDir(), "cassette.json")
+
+	// Record against a fake API
+	var requestBodies []string
+	recorder, err := ynabtest.NewRecorder(cassette, ynabtest.ModeRecord)
+	require.NoError(t, err)
+	recorder.Base = ynabtest.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, "Bearer secret-token", req.Header.Get("Authorization"))
+
+		switch req.URL.Path {
+		case "/v1/user":
+			return jsonResponse(http.StatusOK, `{"data":{"user":{"id":"aa248caa-eed7-4575-a990-717386438d2c"}}}`), nil
+		default:
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			requestBodies = append(requestBodies, string(body))
+			return jsonResponse(http.StatusOK, `{"data":{"category":{"id":"13419c12","name":"Groceries","budgeted":1000}}}`), nil
+		}
+	})
+
+	c := ynab.NewClient("secret-token")
+	c.WithHTTPClient(recorder.Client())
+
+	recordedUser, err := c.User().GetUser()
+	require.NoError(t, err)
+	assert.Equal(t, "aa248caa-eed7-4575-a990-717386438d2c", recordedUser.ID)
+
+	recordedCategory, err := c.Category().UpdateCategoryForCurrentMonth("aa248caa", "13419c12",
+		category.PayloadMonthCategory{Budgeted: 1000})
+	require.NoError(t, err)
+	assert.Equal(t, []string{`{"category":{"budgeted":1000}}`}, requestBodies)
+
+	require.NoError(t, recorder.Stop())
+
+	// The token never reaches the cassette
+	data, err := os.ReadFile(cassette)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret-token")
+	assert.Contains(t, string(data), ynabtest.RedactedValue)
+
+	// Replay without any network access
+	replayer, err := ynabtest.NewRecorder(cassette, ynabtest.ModeReplay)
+	require.NoError(t, err)
+	replayer.Base = ynabtest.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		t.Fatalf("unexpected request to %s in replay mode", req.URL)
+		return nil, nil
+	})
+
+	c = ynab.NewClient("another-token")
+	c.WithHTTPClient(replayer.Client())
+
+	replayedUser, err := c.User().GetUser()
+	require.NoError(t, err)
+	assert.Equal(t, recordedUser, replayedUser)
+
+	replayedCategory, err := c.Category().UpdateCategoryForCurrentMonth("aa248caa", "13419c12",
+		category.PayloadMonthCategory{Budgeted: 1000})
+	require.NoError(t, err)
+	assert.Equal(t, recordedCategory, replayedCategory)
+
+	// Each interaction is served once, and unknown requests fail
+	_, err = c.User().GetUser()
+	assert.ErrorIs(t, err, ynabtest.ErrInteractionNotFound)
+
+	_, err = c.Category().UpdateCategoryForCurrentMonth("aa248caa", "13419c12",
+		category.PayloadMonthCategory{Budgeted: 2000})
+	assert.ErrorIs(t, err, ynabtest.ErrInteractionNotFound)
+
+	assert.NoError(t, replayer.Stop())
+}
+
+func TestNewRecorder_MissingCassette(t *testing.T) {
+	_, err := ynabtest.NewRecorder(filepath.Join(t.TempDir(), "missing.json"), ynabtest.ModeReplay)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}